@@ -6,11 +6,25 @@ import (
 	"net/http"
 	db "simple_bank/db/sqlc"
 	"simple_bank/token"
+	"simple_bank/util"
 
 	"github.com/gin-gonic/gin"
 	"github.com/lib/pq"
 )
 
+// accountResponse adds the balance formatted with the minor unit of the account currency
+type accountResponse struct {
+	db.Account
+	FormattedBalance string `json:"formattedBalance"`
+}
+
+func newAccountResponse(account db.Account) accountResponse {
+	return accountResponse{
+		Account:          account,
+		FormattedBalance: util.FormatAmount(account.Balance, account.Currency),
+	}
+}
+
 type createAccountRequest struct {
 	Currency string `json:"currency" binding:"required,currency"`
 }
@@ -22,6 +36,15 @@ func (server *Server) createAccount(ctx *gin.Context) {
 		return
 	}
 	authPayload := ctx.MustGet(authorizationPayloadKey).(*token.Payload)
+	user, err := server.store.GetUser(ctx, authPayload.Username)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+	if !user.IsEmailVerified {
+		ctx.JSON(http.StatusForbidden, errorResponse(db.ErrEmailNotVerified))
+		return
+	}
 	arg := db.CreateAccountParams{
 		Username: authPayload.Username,
 		Balance:  0,
@@ -40,7 +63,7 @@ func (server *Server) createAccount(ctx *gin.Context) {
 		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
 		return
 	}
-	ctx.JSON(http.StatusOK, account)
+	ctx.JSON(http.StatusOK, newAccountResponse(account))
 }
 
 type getAccountRequest struct {
@@ -53,21 +76,11 @@ func (server *Server) getAccount(ctx *gin.Context) {
 		ctx.JSON(http.StatusBadRequest, errorResponse(err))
 		return
 	}
-	account, err := server.store.GetAccount(ctx, req.ID)
-	if err != nil {
-		if err == sql.ErrNoRows {
-			ctx.JSON(http.StatusNotFound, errorResponse(err))
-		}
-		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
-		return
-	}
-	authPayload := ctx.MustGet(authorizationPayloadKey).(*token.Payload)
-	if account.Username != authPayload.Username {
-		err := errors.New("account doesn't belong to the authenticated user")
-		ctx.JSON(http.StatusForbidden, errorResponse(err))
+	account, ok := server.authorizedAccount(ctx, req.ID, util.BankerRole, util.AdminRole)
+	if !ok {
 		return
 	}
-	ctx.JSON(http.StatusOK, account)
+	ctx.JSON(http.StatusOK, newAccountResponse(account))
 }
 
 type getAccountsRequest struct {
@@ -95,61 +108,77 @@ func (server *Server) getAccounts(ctx *gin.Context) {
 		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
 		return
 	}
-	ctx.JSON(http.StatusOK, accounts)
-}
-
-type updateAccountRequest struct {
-	Amount int64 `json:"amount" binding:"required,gte=0"`
+	response := make([]accountResponse, len(accounts))
+	for i, account := range accounts {
+		response[i] = newAccountResponse(account)
+	}
+	ctx.JSON(http.StatusOK, response)
 }
 
-func (server *Server) updateAccount(ctx *gin.Context) {
-	var req1 getAccountRequest
-	var req2 updateAccountRequest
-	if err := ctx.ShouldBindUri(&req1); err != nil {
+// closeAccount closes the account instead of deleting it, so its entries and transfers are kept.
+// The balance has to be zero.
+func (server *Server) closeAccount(ctx *gin.Context) {
+	var req getAccountRequest
+	if err := ctx.ShouldBindUri(&req); err != nil {
 		ctx.JSON(http.StatusBadRequest, errorResponse(err))
 		return
 	}
-	if err := ctx.ShouldBindJSON(&req2); err != nil {
-		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+	if _, ok := server.authorizedAccount(ctx, req.ID); !ok {
 		return
 	}
-	arg := db.AddAmountAccountParams{
-		ID:     req1.ID,
-		Amount: req2.Amount,
-	}
-	account, err := server.store.AddAmountAccount(ctx, arg)
+	_, err := server.store.UpdateAccountStatusTx(ctx, db.UpdateAccountStatusTxParams{
+		AccountID: req.ID,
+		Status:    db.AccountStatusClosed,
+	})
 	if err != nil {
-		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		accountStatusError(ctx, err)
 		return
 	}
-	ctx.JSON(http.StatusOK, account)
+	ctx.JSON(http.StatusOK, "Account closed successfully")
+}
 
+type updateAccountStatusRequest struct {
+	// closing goes through DELETE
+	Status string `json:"status" binding:"required,oneof=active frozen"`
 }
 
-func (server *Server) deleteAccount(ctx *gin.Context) {
-	var req getAccountRequest
-	if err := ctx.ShouldBindUri(&req); err != nil {
+// updateAccountStatus freezes and activates accounts. Owners can freeze their accounts, bankers can
+// freeze any account. Only bankers can activate a frozen account again, so a freeze put in place by
+// the bank cannot be lifted by the owner.
+func (server *Server) updateAccountStatus(ctx *gin.Context) {
+	var uri getAccountRequest
+	if err := ctx.ShouldBindUri(&uri); err != nil {
 		ctx.JSON(http.StatusBadRequest, errorResponse(err))
 		return
 	}
-	account, err := server.store.GetAccount(ctx, req.ID)
-	if err != nil {
-		if err == sql.ErrNoRows {
-			ctx.JSON(http.StatusNotFound, errorResponse(err))
-		}
-		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+	var req updateAccountStatusRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
 		return
 	}
-	authPayload := ctx.MustGet(authorizationPayloadKey).(*token.Payload)
-	if account.Username != authPayload.Username {
-		err := errors.New("account doesn't belong to the authenticated user")
-		ctx.JSON(http.StatusForbidden, errorResponse(err))
+	if _, ok := server.authorizedAccount(ctx, uri.ID, util.BankerRole, util.AdminRole); !ok {
 		return
 	}
-	err = server.store.DeleteAccount(ctx, req.ID)
+	authPayload := ctx.MustGet(authorizationPayloadKey).(*token.Payload)
+	account, err := server.store.UpdateAccountStatusTx(ctx, db.UpdateAccountStatusTxParams{
+		AccountID:     uri.ID,
+		Status:        req.Status,
+		AllowUnfreeze: hasRole(authPayload, util.BankerRole, util.AdminRole),
+	})
 	if err != nil {
-		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		accountStatusError(ctx, err)
 		return
 	}
-	ctx.JSON(http.StatusOK, "Account deleted successfully")
+	ctx.JSON(http.StatusOK, newAccountResponse(account))
+}
+
+func accountStatusError(ctx *gin.Context, err error) {
+	switch {
+	case errors.Is(err, db.ErrInvalidAccountStatusTransition), errors.Is(err, db.ErrAccountBalanceNotZero):
+		ctx.JSON(http.StatusConflict, errorResponse(err))
+	case errors.Is(err, db.ErrUnfreezeNotAllowed):
+		ctx.JSON(http.StatusForbidden, errorResponse(err))
+	default:
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+	}
 }
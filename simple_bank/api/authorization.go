@@ -0,0 +1,55 @@
+package api
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"net/http"
+	db "simple_bank/db/sqlc"
+	"simple_bank/token"
+
+	"github.com/gin-gonic/gin"
+)
+
+// roleMiddleware only lets through the users with one of the roles, it runs after authMiddleware
+func roleMiddleware(roles ...string) gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		authPayload := ctx.MustGet(authorizationPayloadKey).(*token.Payload)
+		if !hasRole(authPayload, roles...) {
+			err := fmt.Errorf("role %s is not allowed to access this resource", authPayload.Role)
+			ctx.AbortWithStatusJSON(http.StatusForbidden, errorResponse(err))
+			return
+		}
+		ctx.Next()
+	}
+}
+
+func hasRole(payload *token.Payload, roles ...string) bool {
+	for _, role := range roles {
+		if payload.Role == role {
+			return true
+		}
+	}
+	return false
+}
+
+// authorizedAccount gets the account and checks the authenticated user can access it: owners can
+// access their accounts, users with one of the staff roles any account
+func (server *Server) authorizedAccount(ctx *gin.Context, accountID int64, staffRoles ...string) (db.Account, bool) {
+	account, err := server.store.GetAccount(ctx, accountID)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			ctx.JSON(http.StatusNotFound, errorResponse(err))
+			return account, false
+		}
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return account, false
+	}
+	authPayload := ctx.MustGet(authorizationPayloadKey).(*token.Payload)
+	if account.Username != authPayload.Username && !hasRole(authPayload, staffRoles...) {
+		err := errors.New("account doesn't belong to the authenticated user")
+		ctx.JSON(http.StatusForbidden, errorResponse(err))
+		return account, false
+	}
+	return account, true
+}
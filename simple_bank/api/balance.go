@@ -0,0 +1,114 @@
+package api
+
+import (
+	"errors"
+	"net/http"
+	db "simple_bank/db/sqlc"
+	"simple_bank/token"
+	"simple_bank/util"
+
+	"github.com/gin-gonic/gin"
+)
+
+type balanceChangeRequest struct {
+	// amount in minor units of Currency, or a decimal string like "12.34" in DecimalAmount
+	Amount            int64  `json:"amount" binding:"required_without=DecimalAmount,excluded_with=DecimalAmount,omitempty,gt=0"`
+	DecimalAmount     string `json:"decimalAmount" binding:"required_without=Amount"`
+	Currency          string `json:"currency" binding:"required,currency"`
+	Reason            string `json:"reason" binding:"max=255"`
+	ExternalReference string `json:"externalReference" binding:"max=255"`
+}
+
+type balanceChangeResponse struct {
+	Account         accountResponse `json:"account"`
+	Entry           db.Entry        `json:"entry"`
+	FormattedAmount string          `json:"formattedAmount"`
+}
+
+func newBalanceChangeResponse(result db.BalanceTxResult) balanceChangeResponse {
+	return balanceChangeResponse{
+		Account:         newAccountResponse(result.Account),
+		Entry:           result.Entry,
+		FormattedAmount: util.FormatAmount(result.Entry.Amount, result.Account.Currency),
+	}
+}
+
+// deposit adds money to an account. Only bankers and admins can deposit, e.g. for cash paid in at
+// a branch, owners would otherwise create money out of nothing.
+func (server *Server) deposit(ctx *gin.Context) {
+	req, account, amount, valid := server.validBalanceChange(ctx, util.BankerRole, util.AdminRole)
+	if !valid {
+		return
+	}
+	result, err := server.store.DepositTx(ctx, db.DepositTxParams{
+		AccountID:         account.ID,
+		Amount:            amount.Amount,
+		Reason:            req.Reason,
+		ExternalReference: req.ExternalReference,
+	})
+	if err != nil {
+		balanceChangeError(ctx, err)
+		return
+	}
+	ctx.JSON(http.StatusOK, newBalanceChangeResponse(result))
+}
+
+func (server *Server) withdraw(ctx *gin.Context) {
+	req, account, amount, valid := server.validBalanceChange(ctx)
+	if !valid {
+		return
+	}
+	result, err := server.store.WithdrawTx(ctx, db.WithdrawTxParams{
+		AccountID:         account.ID,
+		Amount:            amount.Amount,
+		Reason:            req.Reason,
+		ExternalReference: req.ExternalReference,
+	})
+	if err != nil {
+		balanceChangeError(ctx, err)
+		return
+	}
+	ctx.JSON(http.StatusOK, newBalanceChangeResponse(result))
+}
+
+// validBalanceChange binds a deposit or withdrawal and checks the account belongs to the
+// authenticated user, or the user has one of the staff roles, and holds the requested currency
+func (server *Server) validBalanceChange(ctx *gin.Context, staffRoles ...string) (balanceChangeRequest, db.Account, util.Money, bool) {
+	var uri getAccountRequest
+	var req balanceChangeRequest
+	if err := ctx.ShouldBindUri(&uri); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return req, db.Account{}, util.Money{}, false
+	}
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return req, db.Account{}, util.Money{}, false
+	}
+	amount, err := requestAmount(req.Amount, req.DecimalAmount, req.Currency)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return req, db.Account{}, util.Money{}, false
+	}
+	account, valid := server.validAccount(ctx, uri.ID, req.Currency)
+	if !valid {
+		return req, account, amount, false
+	}
+	authPayload := ctx.MustGet(authorizationPayloadKey).(*token.Payload)
+	if account.Username != authPayload.Username && !hasRole(authPayload, staffRoles...) {
+		err := errors.New("account doesn't belong to the authenticated user")
+		ctx.JSON(http.StatusForbidden, errorResponse(err))
+		return req, db.Account{}, util.Money{}, false
+	}
+	return req, account, amount, true
+}
+
+func balanceChangeError(ctx *gin.Context, err error) {
+	switch {
+	case errors.Is(err, db.ErrDuplicateExternalReference):
+		ctx.JSON(http.StatusConflict, errorResponse(err))
+	case errors.Is(err, db.ErrInsufficientFunds), errors.Is(err, db.ErrAccountNotActive), errors.Is(err, util.ErrMoneyOverflow):
+		ctx.JSON(http.StatusUnprocessableEntity, errorResponse(err))
+	default:
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+	}
+}
@@ -0,0 +1,97 @@
+package api
+
+import (
+	"database/sql"
+	"net/http"
+	db "simple_bank/db/sqlc"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/lib/pq"
+)
+
+type currencyResponse struct {
+	Code      string    `json:"code"`
+	MinorUnit int16     `json:"minorUnit"`
+	Symbol    string    `json:"symbol"`
+	Enabled   bool      `json:"enabled"`
+	CreatedAt time.Time `json:"createdAt"`
+}
+
+func newCurrencyResponse(currency db.Currency) currencyResponse {
+	return currencyResponse{
+		Code:      currency.Code,
+		MinorUnit: currency.MinorUnit,
+		Symbol:    currency.Symbol,
+		Enabled:   currency.Enabled,
+		CreatedAt: currency.CreatedAt,
+	}
+}
+
+type createCurrencyRequest struct {
+	Code      string `json:"code" binding:"required,len=3,uppercase,alpha"`
+	MinorUnit *int16 `json:"minorUnit" binding:"required,min=0,max=4"`
+	Symbol    string `json:"symbol" binding:"required,max=10"`
+	Enabled   bool   `json:"enabled"`
+}
+
+// createCurrency adds a currency to the registry, only admins can reach it. The servers pick it up
+// with their next currency refresh.
+func (server *Server) createCurrency(ctx *gin.Context) {
+	var req createCurrencyRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+	currency, err := server.store.CreateCurrency(ctx, db.CreateCurrencyParams{
+		Code:      req.Code,
+		MinorUnit: *req.MinorUnit,
+		Symbol:    req.Symbol,
+		Enabled:   req.Enabled,
+	})
+	if err != nil {
+		if pqErr, ok := err.(*pq.Error); ok && pqErr.Code.Name() == "unique_violation" {
+			ctx.JSON(http.StatusForbidden, errorResponse(err))
+			return
+		}
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+	ctx.JSON(http.StatusOK, newCurrencyResponse(currency))
+}
+
+type updateCurrencyUri struct {
+	Code string `uri:"code" binding:"required,len=3,uppercase,alpha"`
+}
+
+type updateCurrencyRequest struct {
+	Enabled *bool `json:"enabled" binding:"required"`
+}
+
+// updateCurrency enables or disables a currency, only admins can reach it. Once the servers refresh
+// their currencies, requests in a disabled currency are rejected, the accounts holding it keep their balance.
+func (server *Server) updateCurrency(ctx *gin.Context) {
+	var uri updateCurrencyUri
+	if err := ctx.ShouldBindUri(&uri); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+	var req updateCurrencyRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+	currency, err := server.store.UpdateCurrencyEnabled(ctx, db.UpdateCurrencyEnabledParams{
+		Code:    uri.Code,
+		Enabled: *req.Enabled,
+	})
+	if err != nil {
+		if err == sql.ErrNoRows {
+			ctx.JSON(http.StatusNotFound, errorResponse(err))
+			return
+		}
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+	ctx.JSON(http.StatusOK, newCurrencyResponse(currency))
+}
@@ -0,0 +1,74 @@
+package api
+
+import (
+	"errors"
+	"net/http"
+	db "simple_bank/db/sqlc"
+	"simple_bank/util"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+type listAccountEntriesRequest struct {
+	From   time.Time `form:"from"`
+	To     time.Time `form:"to"`
+	Cursor string    `form:"cursor"`
+	Limit  int32     `form:"limit" binding:"required,min=5,max=1000"`
+}
+
+type listAccountEntriesResponse struct {
+	Entries    []db.ListAccountEntriesRow `json:"entries"`
+	NextCursor string                     `json:"nextCursor,omitempty"`
+}
+
+func (server *Server) listAccountEntries(ctx *gin.Context) {
+	var uri getAccountRequest
+	var req listAccountEntriesRequest
+	if err := ctx.ShouldBindUri(&uri); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+	if err := ctx.ShouldBindQuery(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+	if !req.From.IsZero() && !req.To.IsZero() && !req.From.Before(req.To) {
+		err := errors.New("from must be before to")
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+	afterCreatedAt, afterID, err := util.DecodeCursor(req.Cursor)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	account, ok := server.authorizedAccount(ctx, uri.ID, util.BankerRole, util.AdminRole)
+	if !ok {
+		return
+	}
+
+	arg := db.ListAccountEntriesParams{
+		AccountID:      account.ID,
+		FromTime:       util.TimeToSqlNullTime(req.From),
+		ToTime:         util.TimeToSqlNullTime(req.To),
+		AfterCreatedAt: afterCreatedAt,
+		AfterID:        afterID,
+		RowLimit:       req.Limit,
+	}
+	entries, err := server.store.ListAccountEntries(ctx, arg)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+	response := listAccountEntriesResponse{
+		Entries: entries,
+	}
+	// a full page means there may be more rows after the last one
+	if len(entries) == int(req.Limit) {
+		last := entries[len(entries)-1]
+		response.NextCursor = util.EncodeCursor(last.CreatedAt, last.ID)
+	}
+	ctx.JSON(http.StatusOK, response)
+}
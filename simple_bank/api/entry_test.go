@@ -0,0 +1,321 @@
+package api
+
+import (
+	"bytes"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	mockdb "simple_bank/db/mock"
+	db "simple_bank/db/sqlc"
+	"simple_bank/token"
+	"simple_bank/util"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+)
+
+func TestListAccountEntriesApi(t *testing.T) {
+	user, _ := randomUser(t)
+	account := randomAccount(user.Username)
+	n := 5
+	entries := make([]db.ListAccountEntriesRow, n)
+	for i := 0; i < n; i++ {
+		entries[i] = randomStatementEntry(account.ID, i)
+	}
+	last := entries[n-1]
+
+	type Query struct {
+		from   string
+		to     string
+		cursor string
+		limit  int
+	}
+
+	testCases := []struct {
+		name          string
+		accountID     int64
+		query         Query
+		setupAuth     func(t *testing.T, request *http.Request, tokenMaker token.Maker)
+		buildStubs    func(store *mockdb.MockStore)
+		checkResponse func(t *testing.T, recorder *httptest.ResponseRecorder)
+	}{
+		{
+			name:      "OK",
+			accountID: account.ID,
+			query: Query{
+				limit: n,
+			},
+			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
+				addAuthorization(t, request, tokenMaker, authorizationTypeBearer, user.Username, util.DepositorRole, time.Minute)
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					GetAccount(gomock.Any(), gomock.Eq(account.ID)).
+					Times(1).
+					Return(account, nil)
+				arg := db.ListAccountEntriesParams{
+					AccountID: account.ID,
+					RowLimit:  int32(n),
+				}
+				store.EXPECT().
+					ListAccountEntries(gomock.Any(), gomock.Eq(arg)).
+					Times(1).
+					Return(entries, nil)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+				requireBodyMatchStatement(t, recorder.Body, entries, util.EncodeCursor(last.CreatedAt, last.ID))
+			},
+		},
+		{
+			name:      "OKWithCursorAndRange",
+			accountID: account.ID,
+			query: Query{
+				from:   "2023-01-01T00:00:00Z",
+				to:     "2023-02-01T00:00:00Z",
+				cursor: util.EncodeCursor(last.CreatedAt, last.ID),
+				limit:  n + 1,
+			},
+			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
+				addAuthorization(t, request, tokenMaker, authorizationTypeBearer, user.Username, util.DepositorRole, time.Minute)
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					GetAccount(gomock.Any(), gomock.Eq(account.ID)).
+					Times(1).
+					Return(account, nil)
+				arg := db.ListAccountEntriesParams{
+					AccountID:      account.ID,
+					FromTime:       sql.NullTime{Time: time.Date(2023, 1, 1, 0, 0, 0, 0, time.UTC), Valid: true},
+					ToTime:         sql.NullTime{Time: time.Date(2023, 2, 1, 0, 0, 0, 0, time.UTC), Valid: true},
+					AfterCreatedAt: last.CreatedAt,
+					AfterID:        last.ID,
+					RowLimit:       int32(n + 1),
+				}
+				store.EXPECT().
+					ListAccountEntries(gomock.Any(), gomock.Eq(arg)).
+					Times(1).
+					Return(entries, nil)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+				requireBodyMatchStatement(t, recorder.Body, entries, "")
+			},
+		},
+		{
+			name:      "NotFound",
+			accountID: account.ID,
+			query: Query{
+				limit: n,
+			},
+			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
+				addAuthorization(t, request, tokenMaker, authorizationTypeBearer, user.Username, util.DepositorRole, time.Minute)
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					GetAccount(gomock.Any(), gomock.Eq(account.ID)).
+					Times(1).
+					Return(db.Account{}, sql.ErrNoRows)
+				store.EXPECT().
+					ListAccountEntries(gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusNotFound, recorder.Code)
+			},
+		},
+		{
+			name:      "AuthenticatedUserMismatch",
+			accountID: account.ID,
+			query: Query{
+				limit: n,
+			},
+			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
+				addAuthorization(t, request, tokenMaker, authorizationTypeBearer, "user.Username", util.DepositorRole, time.Minute)
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					GetAccount(gomock.Any(), gomock.Eq(account.ID)).
+					Times(1).
+					Return(account, nil)
+				store.EXPECT().
+					ListAccountEntries(gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusForbidden, recorder.Code)
+			},
+		},
+		{
+			name:      "InternalError",
+			accountID: account.ID,
+			query: Query{
+				limit: n,
+			},
+			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
+				addAuthorization(t, request, tokenMaker, authorizationTypeBearer, user.Username, util.DepositorRole, time.Minute)
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					GetAccount(gomock.Any(), gomock.Eq(account.ID)).
+					Times(1).
+					Return(account, nil)
+				store.EXPECT().
+					ListAccountEntries(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return([]db.ListAccountEntriesRow{}, sql.ErrConnDone)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusInternalServerError, recorder.Code)
+			},
+		},
+		{
+			name:      "InvalidCursor",
+			accountID: account.ID,
+			query: Query{
+				cursor: "not a cursor",
+				limit:  n,
+			},
+			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
+				addAuthorization(t, request, tokenMaker, authorizationTypeBearer, user.Username, util.DepositorRole, time.Minute)
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					GetAccount(gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+		{
+			name:      "InvalidRange",
+			accountID: account.ID,
+			query: Query{
+				from:  "2023-02-01T00:00:00Z",
+				to:    "2023-01-01T00:00:00Z",
+				limit: n,
+			},
+			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
+				addAuthorization(t, request, tokenMaker, authorizationTypeBearer, user.Username, util.DepositorRole, time.Minute)
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					GetAccount(gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+		{
+			name:      "InvalidLimit",
+			accountID: account.ID,
+			query: Query{
+				limit: 10000,
+			},
+			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
+				addAuthorization(t, request, tokenMaker, authorizationTypeBearer, user.Username, util.DepositorRole, time.Minute)
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					GetAccount(gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+		{
+			name:      "InvalidID",
+			accountID: 0,
+			query: Query{
+				limit: n,
+			},
+			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
+				addAuthorization(t, request, tokenMaker, authorizationTypeBearer, user.Username, util.DepositorRole, time.Minute)
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					GetAccount(gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+		{
+			name:      "NoAuthorization",
+			accountID: account.ID,
+			query: Query{
+				limit: n,
+			},
+			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					GetAccount(gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusUnauthorized, recorder.Code)
+			},
+		},
+	}
+	for i := range testCases {
+		tc := testCases[i]
+		t.Run(
+			tc.name,
+			func(t *testing.T) {
+				ctrl := gomock.NewController(t)
+				defer ctrl.Finish()
+				store := mockdb.NewMockStore(ctrl)
+				tc.buildStubs(store)
+				server := newTestServer(t, store)
+				recorder := httptest.NewRecorder()
+				url := fmt.Sprintf("/accounts/%d/entries", tc.accountID)
+				request, err := http.NewRequest(http.MethodGet, url, nil)
+				require.NoError(t, err)
+				q := request.URL.Query()
+				if tc.query.from != "" {
+					q.Add("from", tc.query.from)
+				}
+				if tc.query.to != "" {
+					q.Add("to", tc.query.to)
+				}
+				if tc.query.cursor != "" {
+					q.Add("cursor", tc.query.cursor)
+				}
+				q.Add("limit", fmt.Sprintf("%d", tc.query.limit))
+				request.URL.RawQuery = q.Encode()
+				tc.setupAuth(t, request, server.tokenMaker)
+				server.router.ServeHTTP(recorder, request)
+				tc.checkResponse(t, recorder)
+			},
+		)
+	}
+}
+
+func randomStatementEntry(accountID int64, i int) db.ListAccountEntriesRow {
+	return db.ListAccountEntriesRow{
+		ID:             util.RandomInt(1, 1000),
+		AccountId:      accountID,
+		Amount:         util.RandomMoney(),
+		CreatedAt:      time.Date(2023, 1, 1, 0, 0, i, 0, time.UTC),
+		RunningBalance: util.RandomMoney(),
+	}
+}
+
+func requireBodyMatchStatement(t *testing.T, body *bytes.Buffer, entries []db.ListAccountEntriesRow, nextCursor string) {
+	data, err := io.ReadAll(body)
+	require.NoError(t, err)
+	var gotResponse listAccountEntriesResponse
+	err = json.Unmarshal(data, &gotResponse)
+	require.NoError(t, err)
+	require.Equal(t, entries, gotResponse.Entries)
+	require.Equal(t, nextCursor, gotResponse.NextCursor)
+}
@@ -2,14 +2,17 @@ package api
 
 import (
 	"os"
+	mockdb "simple_bank/db/mock"
 	db "simple_bank/db/sqlc"
 	"simple_bank/util"
+	"simple_bank/worker"
 	"testing"
 	"time"
 
 	"github.com/gin-gonic/gin"
 	_ "github.com/lib/pq"
 	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
 )
 
 func TestMain(m *testing.M) {
@@ -22,7 +25,15 @@ func newTestServer(t *testing.T, store db.Store) *Server {
 		TokenKey:            util.RandomString(32),
 		AccessTokenDuration: time.Minute,
 	}
-	server, err := NewServer(config, store)
+	if mockStore, ok := store.(*mockdb.MockStore); ok {
+		// the auth middleware checks every access token against the last password change, tests
+		// that care about it stub GetUserPasswordChangedAt before this one
+		mockStore.EXPECT().
+			GetUserPasswordChangedAt(gomock.Any(), gomock.Any()).
+			AnyTimes().
+			Return(time.Time{}, nil)
+	}
+	server, err := NewServer(config, store, worker.NewPGTaskDistributor(1))
 	require.NoError(t, err)
 	return server
 }
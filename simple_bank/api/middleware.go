@@ -1,9 +1,11 @@
 package api
 
 import (
+	"database/sql"
 	"errors"
 	"fmt"
 	"net/http"
+	db "simple_bank/db/sqlc"
 	"simple_bank/token"
 	"strings"
 
@@ -14,15 +16,20 @@ var (
 	ErrNoAuthorizationHeader     = errors.New("no authorization header provided")
 	ErrInvalidAuthrizationHeader = errors.New("no authorization header provided")
 	ErrUnsupportedAuthorization  = fmt.Errorf("unsupported authorization type")
+	ErrAccessTokenRevoked        = errors.New("access token was issued before the last password change")
 )
 
 const (
 	authorizationHeaderKey  = "authorization"
 	authorizationTypeBearer = "bearer"
 	authorizationPayloadKey = "authPayload"
+	// the raw access token, to tell the session of the request apart from the other sessions of the user
+	authorizationTokenKey = "authToken"
 )
 
-func authMiddleware(tokenMaker token.Maker) gin.HandlerFunc {
+// authMiddleware verifies the bearer access token. Tokens issued before the last password change of
+// the user are rejected, so a changed password locks out whoever held the old one right away.
+func authMiddleware(tokenMaker token.Maker, store db.Store) gin.HandlerFunc {
 	return func(ctx *gin.Context) {
 		authorizationHeader := ctx.GetHeader(authorizationHeaderKey)
 		if len(authorizationHeader) == 0 {
@@ -40,6 +47,7 @@ func authMiddleware(tokenMaker token.Maker) gin.HandlerFunc {
 		if authorizationType != authorizationTypeBearer {
 			err := ErrUnsupportedAuthorization
 			ctx.AbortWithStatusJSON(http.StatusUnauthorized, errorResponse(err))
+			return
 		}
 		accessToken := fields[1]
 		payload, err := tokenMaker.VerifyToken(accessToken)
@@ -47,7 +55,21 @@ func authMiddleware(tokenMaker token.Maker) gin.HandlerFunc {
 			ctx.AbortWithStatusJSON(http.StatusUnauthorized, errorResponse(err))
 			return
 		}
+		passwordChangedAt, err := store.GetUserPasswordChangedAt(ctx, payload.Username)
+		if err != nil {
+			if err == sql.ErrNoRows {
+				ctx.AbortWithStatusJSON(http.StatusUnauthorized, errorResponse(err))
+				return
+			}
+			ctx.AbortWithStatusJSON(http.StatusInternalServerError, errorResponse(err))
+			return
+		}
+		if payload.IssuedAt.Before(passwordChangedAt) {
+			ctx.AbortWithStatusJSON(http.StatusUnauthorized, errorResponse(ErrAccessTokenRevoked))
+			return
+		}
 		ctx.Set(authorizationPayloadKey, payload)
+		ctx.Set(authorizationTokenKey, accessToken)
 		ctx.Next()
 	}
 }
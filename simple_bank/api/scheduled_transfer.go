@@ -0,0 +1,281 @@
+package api
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"net/http"
+	db "simple_bank/db/sqlc"
+	"simple_bank/token"
+	"simple_bank/util"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+type createScheduledTransferRequest struct {
+	FromAccountID int64 `json:"fromAccountId" binding:"required,min=1"`
+	ToAccountID   int64 `json:"toAccountId" binding:"required,min=1,nefield=FromAccountID"`
+	// amount in minor units of Currency, or a decimal string like "12.34" in DecimalAmount
+	Amount        int64  `json:"amount" binding:"required_without=DecimalAmount,excluded_with=DecimalAmount,omitempty,gt=0"`
+	DecimalAmount string `json:"decimalAmount" binding:"required_without=Amount"`
+	Currency      string `json:"currency" binding:"required,currency"`
+	// currency of the destination account, defaults to Currency. When it differs the amount is converted at the rate of each run.
+	ToCurrency string `json:"toCurrency" binding:"omitempty,currency"`
+	// cron expression like "0 9 1 * *" for a recurring transfer, empty for a one-off transfer
+	Schedule string `json:"schedule" binding:"omitempty,schedule"`
+	// first run, required for a one-off transfer. A recurring transfer starts at the next occurrence of its schedule by default.
+	RunAt time.Time `json:"runAt"`
+}
+
+func (server *Server) createScheduledTransfer(ctx *gin.Context) {
+	var req createScheduledTransferRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+	amount, err := requestAmount(req.Amount, req.DecimalAmount, req.Currency)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+	nextRunAt, err := firstRunAt(req.Schedule, req.RunAt)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+	fromAccount, valid := server.validAccount(ctx, req.FromAccountID, req.Currency)
+	if !valid {
+		return
+	}
+	authPayload := ctx.MustGet(authorizationPayloadKey).(*token.Payload)
+	if fromAccount.Username != authPayload.Username {
+		err := errors.New("from account does not belong to the authenticated user")
+		ctx.JSON(http.StatusForbidden, errorResponse(err))
+		return
+	}
+	toCurrency := req.ToCurrency
+	if toCurrency == "" {
+		toCurrency = req.Currency
+	}
+	_, valid = server.validAccount(ctx, req.ToAccountID, toCurrency)
+	if !valid {
+		return
+	}
+	arg := db.CreateScheduledTransferParams{
+		Username:      authPayload.Username,
+		FromAccountId: req.FromAccountID,
+		ToAccountId:   req.ToAccountID,
+		Amount:        amount.Amount,
+		Schedule:      req.Schedule,
+		NextRunAt:     nextRunAt,
+	}
+	scheduledTransfer, err := server.store.CreateScheduledTransfer(ctx, arg)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+	ctx.JSON(http.StatusOK, scheduledTransfer)
+}
+
+// firstRunAt returns when a scheduled transfer runs first: at runAt when it is given, otherwise at the next
+// occurrence of the schedule
+func firstRunAt(schedule string, runAt time.Time) (time.Time, error) {
+	if !runAt.IsZero() {
+		if !runAt.After(time.Now()) {
+			return runAt, errors.New("runAt must be in the future")
+		}
+		return runAt, nil
+	}
+	if schedule == "" {
+		return runAt, errors.New("runAt is required for a one-off transfer")
+	}
+	parsed, err := util.ParseSchedule(schedule)
+	if err != nil {
+		return runAt, err
+	}
+	return parsed.Next(time.Now()), nil
+}
+
+type getScheduledTransferRequest struct {
+	ID int64 `uri:"id" binding:"required,min=1"`
+}
+
+func (server *Server) getScheduledTransfer(ctx *gin.Context) {
+	scheduledTransfer, valid := server.validScheduledTransfer(ctx)
+	if !valid {
+		return
+	}
+	ctx.JSON(http.StatusOK, scheduledTransfer)
+}
+
+type listScheduledTransfersRequest struct {
+	Offset *int32 `form:"offset" binding:"required,min=0"`
+	Limit  int32  `form:"limit" binding:"required,min=5,max=1000"`
+}
+
+func (server *Server) listScheduledTransfers(ctx *gin.Context) {
+	var req listScheduledTransfersRequest
+	if err := ctx.ShouldBindQuery(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+	authPayload := ctx.MustGet(authorizationPayloadKey).(*token.Payload)
+	arg := db.ListScheduledTransfersParams{
+		Username: authPayload.Username,
+		Limit:    req.Limit,
+		Offset:   *req.Offset * req.Limit,
+	}
+	scheduledTransfers, err := server.store.ListScheduledTransfers(ctx, arg)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+	ctx.JSON(http.StatusOK, scheduledTransfers)
+}
+
+// updateScheduledTransferRequest changes only the fields that are set
+type updateScheduledTransferRequest struct {
+	// amount in minor units of the source account currency, or a decimal string like "12.34" in DecimalAmount
+	Amount        int64  `json:"amount" binding:"excluded_with=DecimalAmount,omitempty,gt=0"`
+	DecimalAmount string `json:"decimalAmount"`
+	// new cron expression, an empty string turns the transfer into a one-off transfer at RunAt
+	Schedule *string `json:"schedule" binding:"omitempty,schedule"`
+	// moves the next run, it is the next occurrence of the new schedule by default
+	RunAt  time.Time `json:"runAt"`
+	Status string    `json:"status" binding:"omitempty,oneof=active paused"`
+}
+
+func (server *Server) updateScheduledTransfer(ctx *gin.Context) {
+	scheduledTransfer, valid := server.validScheduledTransfer(ctx)
+	if !valid {
+		return
+	}
+	var req updateScheduledTransferRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+	if !isPending(scheduledTransfer) {
+		err := fmt.Errorf("scheduled transfer is %s", scheduledTransfer.Status)
+		ctx.JSON(http.StatusConflict, errorResponse(err))
+		return
+	}
+	arg := db.UpdateScheduledTransferParams{
+		ID:     scheduledTransfer.ID,
+		Status: sql.NullString{String: req.Status, Valid: req.Status != ""},
+	}
+	if req.Amount != 0 || req.DecimalAmount != "" {
+		fromAccount, err := server.store.GetAccount(ctx, scheduledTransfer.FromAccountId)
+		if err != nil {
+			ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+			return
+		}
+		amount, err := requestAmount(req.Amount, req.DecimalAmount, fromAccount.Currency)
+		if err != nil {
+			ctx.JSON(http.StatusBadRequest, errorResponse(err))
+			return
+		}
+		arg.Amount = sql.NullInt64{Int64: amount.Amount, Valid: true}
+	}
+	if req.Schedule != nil || !req.RunAt.IsZero() {
+		schedule := scheduledTransfer.Schedule
+		if req.Schedule != nil {
+			schedule = *req.Schedule
+		}
+		nextRunAt, err := firstRunAt(schedule, req.RunAt)
+		if err != nil {
+			ctx.JSON(http.StatusBadRequest, errorResponse(err))
+			return
+		}
+		arg.Schedule = sql.NullString{String: schedule, Valid: true}
+		arg.NextRunAt = sql.NullTime{Time: nextRunAt, Valid: true}
+	}
+	scheduledTransfer, err := server.store.UpdateScheduledTransfer(ctx, arg)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+	ctx.JSON(http.StatusOK, scheduledTransfer)
+}
+
+// deleteScheduledTransfer cancels the transfer, its runs are kept
+func (server *Server) deleteScheduledTransfer(ctx *gin.Context) {
+	scheduledTransfer, valid := server.validScheduledTransfer(ctx)
+	if !valid {
+		return
+	}
+	if !isPending(scheduledTransfer) {
+		err := fmt.Errorf("scheduled transfer is %s", scheduledTransfer.Status)
+		ctx.JSON(http.StatusConflict, errorResponse(err))
+		return
+	}
+	_, err := server.store.UpdateScheduledTransfer(ctx, db.UpdateScheduledTransferParams{
+		ID:     scheduledTransfer.ID,
+		Status: sql.NullString{String: db.ScheduledTransferStatusCancelled, Valid: true},
+	})
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+	ctx.JSON(http.StatusOK, "Scheduled transfer cancelled successfully")
+}
+
+type listScheduledTransferRunsRequest struct {
+	Offset *int32 `form:"offset" binding:"required,min=0"`
+	Limit  int32  `form:"limit" binding:"required,min=5,max=1000"`
+}
+
+// listScheduledTransferRuns returns the attempts of a scheduled transfer, latest first
+func (server *Server) listScheduledTransferRuns(ctx *gin.Context) {
+	scheduledTransfer, valid := server.validScheduledTransfer(ctx)
+	if !valid {
+		return
+	}
+	var req listScheduledTransferRunsRequest
+	if err := ctx.ShouldBindQuery(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+	runs, err := server.store.ListScheduledTransferRuns(ctx, db.ListScheduledTransferRunsParams{
+		ScheduledTransferId: scheduledTransfer.ID,
+		Limit:               req.Limit,
+		Offset:              *req.Offset * req.Limit,
+	})
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+	ctx.JSON(http.StatusOK, runs)
+}
+
+// validScheduledTransfer loads the scheduled transfer of the uri and checks it belongs to the authenticated user
+func (server *Server) validScheduledTransfer(ctx *gin.Context) (db.ScheduledTransfer, bool) {
+	var uri getScheduledTransferRequest
+	if err := ctx.ShouldBindUri(&uri); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return db.ScheduledTransfer{}, false
+	}
+	scheduledTransfer, err := server.store.GetScheduledTransfer(ctx, uri.ID)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			ctx.JSON(http.StatusNotFound, errorResponse(err))
+			return scheduledTransfer, false
+		}
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return scheduledTransfer, false
+	}
+	authPayload := ctx.MustGet(authorizationPayloadKey).(*token.Payload)
+	if scheduledTransfer.Username != authPayload.Username {
+		err := errors.New("scheduled transfer doesn't belong to the authenticated user")
+		ctx.JSON(http.StatusForbidden, errorResponse(err))
+		return scheduledTransfer, false
+	}
+	return scheduledTransfer, true
+}
+
+// isPending reports whether the transfer still has runs ahead, only those can be changed or cancelled
+func isPending(scheduledTransfer db.ScheduledTransfer) bool {
+	return scheduledTransfer.Status == db.ScheduledTransferStatusActive ||
+		scheduledTransfer.Status == db.ScheduledTransferStatusPaused
+}
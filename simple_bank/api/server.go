@@ -2,9 +2,12 @@ package api
 
 import (
 	"fmt"
+	"net/http"
 	db "simple_bank/db/sqlc"
+	"simple_bank/exchange"
 	"simple_bank/token"
 	util "simple_bank/util"
+	"simple_bank/worker"
 
 	"github.com/gin-gonic/gin"
 	"github.com/gin-gonic/gin/binding"
@@ -16,22 +19,34 @@ type Server struct {
 	router     *gin.Engine
 	store      db.Store
 	tokenMaker token.Maker
+	// converts amounts of cross-currency transfers
+	exchangeRates exchange.ExchangeRateProvider
+	// enqueues the side effects run in the background, like the verification emails of new users
+	taskDistributor worker.TaskDistributor
 }
 
-func NewServer(config util.Config, store db.Store) (*Server, error) {
+func NewServer(config util.Config, store db.Store, taskDistributor worker.TaskDistributor) (*Server, error) {
 	tokenMaker, err := token.NewPasetoMaker(config.TokenKey)
 	if err != nil {
 		return nil, fmt.Errorf("cannot create a token maker: %w", err)
 	}
+	exchangeRates, err := exchange.NewFileProvider(config.ExchangeRatesFile)
+	if err != nil {
+		return nil, fmt.Errorf("cannot create an exchange rate provider: %w", err)
+	}
 
 	server := &Server{
-		config:     config,
-		store:      store,
-		tokenMaker: tokenMaker,
+		config:          config,
+		store:           store,
+		tokenMaker:      tokenMaker,
+		exchangeRates:   exchangeRates,
+		taskDistributor: taskDistributor,
 	}
 	// custom validation
 	if v, ok := binding.Validator.Engine().(*validator.Validate); ok {
 		v.RegisterValidation("currency", validCurrency)
+		v.RegisterValidation("schedule", validSchedule)
+		v.RegisterValidation("role", validRole)
 	}
 
 	server.setupRouter()
@@ -44,6 +59,11 @@ func (server *Server) Start(address string) error {
 	return server.router.Run(address)
 }
 
+// Handler exposes the router so the caller can control the HTTP server lifecycle
+func (server *Server) Handler() http.Handler {
+	return server.router
+}
+
 func errorResponse(err error) gin.H {
 	return gin.H{"error": err.Error()}
 }
@@ -53,13 +73,39 @@ func (server *Server) setupRouter() {
 	router.POST("/users/login", server.loginUser)
 	router.POST("/users/renew_access", server.renewAccessToken)
 	router.POST("/users", server.createUser)
-	authRoutes := router.Group("/").Use(authMiddleware(server.tokenMaker))
+	router.GET("/verify_email", server.verifyEmail)
+	authRoutes := router.Group("/").Use(authMiddleware(server.tokenMaker, server.store))
+	authRoutes.PATCH("/users/:username", server.updateUser)
+	authRoutes.POST("/users/:username/verify_email", server.resendVerifyEmail)
 	authRoutes.POST("/accounts", server.createAccount)
 	authRoutes.GET("/accounts/:id", server.getAccount)
 	authRoutes.GET("/accounts", server.getAccounts)
-	authRoutes.PATCH("/accounts/:id", server.updateAccount)
-	authRoutes.DELETE("/accounts/:id", server.deleteAccount)
+	authRoutes.DELETE("/accounts/:id", server.closeAccount)
+	authRoutes.PATCH("/accounts/:id/status", server.updateAccountStatus)
+	authRoutes.GET("/accounts/:id/entries", server.listAccountEntries)
+	authRoutes.POST("/accounts/:id/withdraw", server.withdraw)
+	authRoutes.GET("/accounts/:id/limits", server.getAccountLimits)
 
 	authRoutes.POST("/transfers", server.createTransfer)
+	authRoutes.GET("/transfers", server.listTransfers)
+
+	authRoutes.POST("/scheduled_transfers", server.createScheduledTransfer)
+	authRoutes.GET("/scheduled_transfers", server.listScheduledTransfers)
+	authRoutes.GET("/scheduled_transfers/:id", server.getScheduledTransfer)
+	authRoutes.PATCH("/scheduled_transfers/:id", server.updateScheduledTransfer)
+	authRoutes.DELETE("/scheduled_transfers/:id", server.deleteScheduledTransfer)
+	authRoutes.GET("/scheduled_transfers/:id/runs", server.listScheduledTransferRuns)
+
+	staffRoutes := router.Group("/").Use(authMiddleware(server.tokenMaker, server.store), roleMiddleware(util.BankerRole, util.AdminRole))
+	staffRoutes.POST("/accounts/:id/deposit", server.deposit)
+	staffRoutes.PUT("/accounts/:id/limits", server.updateAccountLimits)
+	staffRoutes.PUT("/users/:username/limits/:currency", server.updateUserLimits)
+	staffRoutes.POST("/transfers/:id/reverse", server.reverseTransfer)
+
+	adminRoutes := router.Group("/").Use(authMiddleware(server.tokenMaker, server.store), roleMiddleware(util.AdminRole))
+	adminRoutes.GET("/users", server.getUsers)
+	adminRoutes.PATCH("/users/:username/role", server.updateUserRole)
+	adminRoutes.POST("/currencies", server.createCurrency)
+	adminRoutes.PATCH("/currencies/:code", server.updateCurrency)
 	server.router = router
 }
@@ -4,26 +4,48 @@ import (
 	"database/sql"
 	"errors"
 	"fmt"
+	"io"
 	"net/http"
 	db "simple_bank/db/sqlc"
+	"simple_bank/exchange"
 	"simple_bank/token"
+	"simple_bank/util"
+	"simple_bank/validator"
+	"time"
 
 	"github.com/gin-gonic/gin"
 )
 
 type transferRequest struct {
-	FromAccountID int64  `json:"fromAccountId" binding:"required,min=1"`
-	ToAccountID   int64  `json:"toAccountId" binding:"required,min=1"`
-	Amount        int64  `json:"amount" binding:"required,gt=0"`
+	FromAccountID int64 `json:"fromAccountId" binding:"required,min=1"`
+	ToAccountID   int64 `json:"toAccountId" binding:"required,min=1"`
+	// amount in minor units of Currency, or a decimal string like "12.34" in DecimalAmount
+	Amount        int64  `json:"amount" binding:"required_without=DecimalAmount,excluded_with=DecimalAmount,omitempty,gt=0"`
+	DecimalAmount string `json:"decimalAmount" binding:"required_without=Amount"`
 	Currency      string `json:"currency" binding:"required,currency"`
+	// currency of the destination account, defaults to Currency. When it differs the amount is converted.
+	ToCurrency string `json:"toCurrency" binding:"omitempty,currency"`
 }
 
+// idempotencyKeyHeader lets clients safely retry a transfer, see db.TransferTxParams
+const idempotencyKeyHeader = "Idempotency-Key"
+
 func (server *Server) createTransfer(ctx *gin.Context) {
 	var req transferRequest
 	if err := ctx.ShouldBindJSON(&req); err != nil {
 		ctx.JSON(http.StatusBadRequest, errorResponse(err))
 		return
 	}
+	idempotencyKey := ctx.GetHeader(idempotencyKeyHeader)
+	if err := validator.ValidateIdempotencyKey(idempotencyKey); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+	amount, err := requestAmount(req.Amount, req.DecimalAmount, req.Currency)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
 	fromAccount, valid := server.validAccount(ctx, req.FromAccountID, req.Currency)
 	if !valid {
 		return
@@ -34,21 +56,95 @@ func (server *Server) createTransfer(ctx *gin.Context) {
 		ctx.JSON(http.StatusUnauthorized, errorResponse(err))
 		return
 	}
-	_, valid = server.validAccount(ctx, req.ToAccountID, req.Currency)
+	toCurrency := req.ToCurrency
+	if toCurrency == "" {
+		toCurrency = req.Currency
+	}
+	_, valid = server.validAccount(ctx, req.ToAccountID, toCurrency)
 	if !valid {
 		return
 	}
 	arg := db.TransferTxParams{
-		FromAccountID: req.FromAccountID,
-		ToAccountID:   req.ToAccountID,
-		Amount:        req.Amount,
+		FromAccountID:  req.FromAccountID,
+		ToAccountID:    req.ToAccountID,
+		Amount:         amount.Amount,
+		IdempotencyKey: idempotencyKey,
+		Username:       authPayload.Username,
+	}
+	if toCurrency != req.Currency {
+		rate, err := server.exchangeRates.GetRate(ctx, req.Currency, toCurrency)
+		if err != nil {
+			if errors.Is(err, exchange.ErrRateNotFound) {
+				ctx.JSON(http.StatusBadRequest, errorResponse(err))
+				return
+			}
+			ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+			return
+		}
+		arg.ExchangeRate = &rate
 	}
 	result, err := server.store.TransferTx(ctx, arg)
 	if err != nil {
+		var limitErr *db.LimitExceededError
+		if errors.As(err, &limitErr) {
+			ctx.JSON(http.StatusUnprocessableEntity, limitExceededResponse(limitErr))
+			return
+		}
+		if errors.Is(err, db.ErrIdempotencyKeyConflict) {
+			ctx.JSON(http.StatusConflict, errorResponse(err))
+			return
+		}
+		if errors.Is(err, db.ErrEmailNotVerified) {
+			ctx.JSON(http.StatusForbidden, errorResponse(err))
+			return
+		}
+		if errors.Is(err, db.ErrInsufficientFunds) || errors.Is(err, db.ErrAccountNotActive) {
+			ctx.JSON(http.StatusUnprocessableEntity, errorResponse(err))
+			return
+		}
+		if errors.Is(err, db.ErrCurrencyMismatch) || errors.Is(err, exchange.ErrAmountTooSmall) {
+			ctx.JSON(http.StatusBadRequest, errorResponse(err))
+			return
+		}
 		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
 		return
 	}
-	ctx.JSON(http.StatusOK, result)
+	ctx.JSON(http.StatusOK, newTransferResponse(result, req.Currency, toCurrency))
+}
+
+// limitExceededResponse names the exceeded limit next to the error message
+func limitExceededResponse(err *db.LimitExceededError) gin.H {
+	return gin.H{"error": err.Error(), "limit": err}
+}
+
+// transferResponse adds the transferred amounts as decimal strings in the currency of each account
+type transferResponse struct {
+	db.TransferTxResult
+	FormattedAmount   string `json:"formattedAmount"`
+	FormattedToAmount string `json:"formattedToAmount"`
+}
+
+func newTransferResponse(result db.TransferTxResult, currency string, toCurrency string) transferResponse {
+	return transferResponse{
+		TransferTxResult:  result,
+		FormattedAmount:   util.FormatAmount(result.Transfer.Amount, currency),
+		FormattedToAmount: util.FormatAmount(result.Transfer.ToAmount, toCurrency),
+	}
+}
+
+// requestAmount returns the amount of a request in minor units, parsing decimalAmount when it is set
+func requestAmount(amount int64, decimalAmount string, currency string) (util.Money, error) {
+	if decimalAmount == "" {
+		return util.NewMoney(amount, currency), nil
+	}
+	money, err := util.ParseMoney(decimalAmount, currency)
+	if err != nil {
+		return money, err
+	}
+	if money.Amount <= 0 {
+		return money, errors.New("amount must be greater than 0")
+	}
+	return money, nil
 }
 
 func (server *Server) validAccount(ctx *gin.Context, accountID int64, currency string) (db.Account, bool) {
@@ -68,3 +164,158 @@ func (server *Server) validAccount(ctx *gin.Context, accountID int64, currency s
 	}
 	return account, true
 }
+
+type reverseTransferUri struct {
+	ID int64 `uri:"id" binding:"required,min=1"`
+}
+
+// reverseTransferRequest refunds part of a transfer, an empty body refunds what is left of it.
+// The amount is in the currency of the account that sent the original transfer.
+type reverseTransferRequest struct {
+	Amount        int64  `json:"amount" binding:"excluded_with=DecimalAmount,omitempty,gt=0"`
+	DecimalAmount string `json:"decimalAmount"`
+}
+
+// reverseTransfer gives the money of a transfer back to its sender. Only bankers and admins can
+// reverse transfers, so senders ask them to refund a mistaken transfer.
+func (server *Server) reverseTransfer(ctx *gin.Context) {
+	var uri reverseTransferUri
+	var req reverseTransferRequest
+	if err := ctx.ShouldBindUri(&uri); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+	if err := ctx.ShouldBindJSON(&req); err != nil && !errors.Is(err, io.EOF) {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+	transfer, err := server.store.GetTransfer(ctx, uri.ID)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			ctx.JSON(http.StatusNotFound, errorResponse(err))
+			return
+		}
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+	payer, err := server.store.GetAccount(ctx, transfer.ToAccountId)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+	payee, err := server.store.GetAccount(ctx, transfer.FromAccountId)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+	var amount util.Money
+	if req.Amount != 0 || req.DecimalAmount != "" {
+		amount, err = requestAmount(req.Amount, req.DecimalAmount, payee.Currency)
+		if err != nil {
+			ctx.JSON(http.StatusBadRequest, errorResponse(err))
+			return
+		}
+	}
+	result, err := server.store.ReverseTransferTx(ctx, db.ReverseTransferTxParams{
+		TransferID: transfer.ID,
+		Amount:     amount.Amount,
+	})
+	if err != nil {
+		if errors.Is(err, db.ErrTransferAlreadyReversed) || errors.Is(err, db.ErrTransferNotReversible) {
+			ctx.JSON(http.StatusConflict, errorResponse(err))
+			return
+		}
+		if errors.Is(err, db.ErrInsufficientFunds) || errors.Is(err, db.ErrAccountNotActive) {
+			ctx.JSON(http.StatusUnprocessableEntity, errorResponse(err))
+			return
+		}
+		if errors.Is(err, db.ErrReversalExceedsTransfer) || errors.Is(err, exchange.ErrAmountTooSmall) {
+			ctx.JSON(http.StatusBadRequest, errorResponse(err))
+			return
+		}
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+	ctx.JSON(http.StatusOK, newTransferResponse(result, payer.Currency, payee.Currency))
+}
+
+type listTransfersRequest struct {
+	AccountID      int64     `form:"accountId" binding:"omitempty,min=1"`
+	Direction      string    `form:"direction" binding:"omitempty,oneof=in out"`
+	CounterpartyID int64     `form:"counterpartyId" binding:"omitempty,min=1"`
+	MinAmount      int64     `form:"minAmount" binding:"omitempty,gt=0"`
+	MaxAmount      int64     `form:"maxAmount" binding:"omitempty,gt=0,gtefield=MinAmount"`
+	From           time.Time `form:"from"`
+	To             time.Time `form:"to"`
+	Cursor         string    `form:"cursor"`
+	Limit          int32     `form:"limit" binding:"required,min=5,max=1000"`
+}
+
+type listTransfersResponse struct {
+	Transfers  []db.Transfer `json:"transfers"`
+	NextCursor string        `json:"nextCursor,omitempty"`
+}
+
+// listTransfers returns the transfers that touch any account of the authenticated user
+func (server *Server) listTransfers(ctx *gin.Context) {
+	var req listTransfersRequest
+	if err := ctx.ShouldBindQuery(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+	if !req.From.IsZero() && !req.To.IsZero() && !req.From.Before(req.To) {
+		err := errors.New("from must be before to")
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+	afterCreatedAt, afterID, err := util.DecodeCursor(req.Cursor)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+	authPayload := ctx.MustGet(authorizationPayloadKey).(*token.Payload)
+	if req.AccountID != 0 {
+		account, err := server.store.GetAccount(ctx, req.AccountID)
+		if err != nil {
+			if err == sql.ErrNoRows {
+				ctx.JSON(http.StatusNotFound, errorResponse(err))
+				return
+			}
+			ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+			return
+		}
+		if account.Username != authPayload.Username {
+			err := errors.New("account doesn't belong to the authenticated user")
+			ctx.JSON(http.StatusForbidden, errorResponse(err))
+			return
+		}
+	}
+
+	arg := db.ListUserTransfersParams{
+		Username:       authPayload.Username,
+		Direction:      req.Direction,
+		AccountID:      util.Int64ToSqlNullInt64(req.AccountID),
+		CounterpartyID: util.Int64ToSqlNullInt64(req.CounterpartyID),
+		FromTime:       util.TimeToSqlNullTime(req.From),
+		ToTime:         util.TimeToSqlNullTime(req.To),
+		MinAmount:      util.Int64ToSqlNullInt64(req.MinAmount),
+		MaxAmount:      util.Int64ToSqlNullInt64(req.MaxAmount),
+		AfterCreatedAt: afterCreatedAt,
+		AfterID:        afterID,
+		RowLimit:       req.Limit,
+	}
+	transfers, err := server.store.ListUserTransfers(ctx, arg)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+	response := listTransfersResponse{
+		Transfers: transfers,
+	}
+	// a full page means there may be more rows after the last one
+	if len(transfers) == int(req.Limit) {
+		last := transfers[len(transfers)-1]
+		response.NextCursor = util.EncodeCursor(last.CreatedAt, last.ID)
+	}
+	ctx.JSON(http.StatusOK, response)
+}
@@ -0,0 +1,185 @@
+package api
+
+import (
+	"database/sql"
+	"net/http"
+	db "simple_bank/db/sqlc"
+	"simple_bank/util"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/lib/pq"
+)
+
+// transferLimitsRequest replaces all limits of a scope, a limit left out is removed.
+// Amounts are in minor units of the account currency.
+type transferLimitsRequest struct {
+	MaxTransferAmount *int64 `json:"maxTransferAmount" binding:"omitempty,gt=0"`
+	DailyAmount       *int64 `json:"dailyAmount" binding:"omitempty,gt=0"`
+	MonthlyAmount     *int64 `json:"monthlyAmount" binding:"omitempty,gt=0"`
+	HourlyTransfers   *int32 `json:"hourlyTransfers" binding:"omitempty,gt=0"`
+}
+
+// transferLimitsResponse renders the limits that are not set as null
+type transferLimitsResponse struct {
+	MaxTransferAmount *int64    `json:"maxTransferAmount"`
+	DailyAmount       *int64    `json:"dailyAmount"`
+	MonthlyAmount     *int64    `json:"monthlyAmount"`
+	HourlyTransfers   *int32    `json:"hourlyTransfers"`
+	UpdatedAt         time.Time `json:"updatedAt"`
+}
+
+type accountLimitsResponse struct {
+	Account *transferLimitsResponse `json:"account"`
+	// limits of the owner in the account currency
+	User *transferLimitsResponse `json:"user"`
+}
+
+func (server *Server) getAccountLimits(ctx *gin.Context) {
+	var req getAccountRequest
+	if err := ctx.ShouldBindUri(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+	account, ok := server.authorizedAccount(ctx, req.ID, util.BankerRole, util.AdminRole)
+	if !ok {
+		return
+	}
+
+	var response accountLimitsResponse
+	accountLimit, err := server.store.GetAccountLimit(ctx, account.ID)
+	if err != nil && err != sql.ErrNoRows {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+	if err == nil {
+		response.Account = newAccountLimitResponse(accountLimit)
+	}
+	userLimit, err := server.store.GetUserLimit(ctx, db.GetUserLimitParams{
+		Username: account.Username,
+		Currency: account.Currency,
+	})
+	if err != nil && err != sql.ErrNoRows {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+	if err == nil {
+		response.User = newUserLimitResponse(userLimit)
+	}
+	ctx.JSON(http.StatusOK, response)
+}
+
+// updateAccountLimits replaces the limits of an account. Only bankers and admins can change limits,
+// so owners cannot lift the limits that are set on them.
+func (server *Server) updateAccountLimits(ctx *gin.Context) {
+	var uri getAccountRequest
+	if err := ctx.ShouldBindUri(&uri); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+	var req transferLimitsRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+	if _, ok := server.authorizedAccount(ctx, uri.ID, util.BankerRole, util.AdminRole); !ok {
+		return
+	}
+	accountLimit, err := server.store.UpsertAccountLimit(ctx, db.UpsertAccountLimitParams{
+		AccountId:         uri.ID,
+		MaxTransferAmount: nullInt64(req.MaxTransferAmount),
+		DailyAmount:       nullInt64(req.DailyAmount),
+		MonthlyAmount:     nullInt64(req.MonthlyAmount),
+		HourlyTransfers:   nullInt32(req.HourlyTransfers),
+	})
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+	ctx.JSON(http.StatusOK, newAccountLimitResponse(accountLimit))
+}
+
+type updateUserLimitsUri struct {
+	Username string `uri:"username" binding:"required,alphanum"`
+	Currency string `uri:"currency" binding:"required,currency"`
+}
+
+// updateUserLimits sets the limits shared by all accounts of a user in a currency, like
+// updateAccountLimits only bankers and admins can change them
+func (server *Server) updateUserLimits(ctx *gin.Context) {
+	var uri updateUserLimitsUri
+	if err := ctx.ShouldBindUri(&uri); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+	var req transferLimitsRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+	userLimit, err := server.store.UpsertUserLimit(ctx, db.UpsertUserLimitParams{
+		Username:          uri.Username,
+		Currency:          uri.Currency,
+		MaxTransferAmount: nullInt64(req.MaxTransferAmount),
+		DailyAmount:       nullInt64(req.DailyAmount),
+		MonthlyAmount:     nullInt64(req.MonthlyAmount),
+		HourlyTransfers:   nullInt32(req.HourlyTransfers),
+	})
+	if err != nil {
+		if pqErr, value := err.(*pq.Error); value && pqErr.Code.Name() == "foreign_key_violation" {
+			ctx.JSON(http.StatusNotFound, errorResponse(err))
+			return
+		}
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+	ctx.JSON(http.StatusOK, newUserLimitResponse(userLimit))
+}
+
+func newAccountLimitResponse(limit db.AccountLimit) *transferLimitsResponse {
+	return &transferLimitsResponse{
+		MaxTransferAmount: int64Pointer(limit.MaxTransferAmount),
+		DailyAmount:       int64Pointer(limit.DailyAmount),
+		MonthlyAmount:     int64Pointer(limit.MonthlyAmount),
+		HourlyTransfers:   int32Pointer(limit.HourlyTransfers),
+		UpdatedAt:         limit.UpdatedAt,
+	}
+}
+
+func newUserLimitResponse(limit db.UserLimit) *transferLimitsResponse {
+	return &transferLimitsResponse{
+		MaxTransferAmount: int64Pointer(limit.MaxTransferAmount),
+		DailyAmount:       int64Pointer(limit.DailyAmount),
+		MonthlyAmount:     int64Pointer(limit.MonthlyAmount),
+		HourlyTransfers:   int32Pointer(limit.HourlyTransfers),
+		UpdatedAt:         limit.UpdatedAt,
+	}
+}
+
+func nullInt64(value *int64) sql.NullInt64 {
+	if value == nil {
+		return sql.NullInt64{}
+	}
+	return sql.NullInt64{Int64: *value, Valid: true}
+}
+
+func nullInt32(value *int32) sql.NullInt32 {
+	if value == nil {
+		return sql.NullInt32{}
+	}
+	return sql.NullInt32{Int32: *value, Valid: true}
+}
+
+func int64Pointer(value sql.NullInt64) *int64 {
+	if !value.Valid {
+		return nil
+	}
+	return &value.Int64
+}
+
+func int32Pointer(value sql.NullInt32) *int32 {
+	if !value.Valid {
+		return nil
+	}
+	return &value.Int32
+}
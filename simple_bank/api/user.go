@@ -2,10 +2,14 @@ package api
 
 import (
 	"database/sql"
+	"errors"
 	"fmt"
 	"net/http"
 	db "simple_bank/db/sqlc"
+	"simple_bank/token"
 	util "simple_bank/util"
+	"simple_bank/validator"
+	"simple_bank/worker"
 	"time"
 
 	"github.com/gin-gonic/gin"
@@ -29,6 +33,8 @@ type userResponse struct {
 	Lastname1         string    `json:"lastname1" binding:"required,min=2"`
 	Lastname2         *string   `json:"lastname2"`
 	Email             string    `json:"email" binding:"required,email"`
+	Role              string    `json:"role"`
+	IsEmailVerified   bool      `json:"isEmailVerified"`
 	PasswordChangedAt time.Time `json:"passwordChangedAt"`
 	CreatedAt         time.Time `json:"createdAt"`
 }
@@ -46,17 +52,26 @@ func (server *Server) createUser(ctx *gin.Context) {
 		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
 		return
 	}
-	arg := db.CreateUserParams{
-		Username:       req.Username,
-		Name1:          req.Name1,
-		Name2:          util.StringPtrToSqlNullString(req.Name2),
-		Lastname1:      req.Lastname1,
-		Lastname2:      util.StringPtrToSqlNullString(req.Lastname2),
-		Email:          req.Email,
-		HashedPassword: hashedPassword,
+	arg := db.CreateUserTxParams{
+		CreateUserParams: db.CreateUserParams{
+			Username:       req.Username,
+			Name1:          req.Name1,
+			Name2:          util.StringPtrToSqlNullString(req.Name2),
+			Lastname1:      req.Lastname1,
+			Lastname2:      util.StringPtrToSqlNullString(req.Lastname2),
+			Email:          req.Email,
+			HashedPassword: hashedPassword,
+		},
+		SecretCode: util.RandomString(32),
+		// the email is sent in the background, the task is only enqueued when the user is created
+		AfterCreate: func(q db.Querier, user db.User, verifyEmail db.VerifyEmail) error {
+			return server.taskDistributor.DistributeTaskSendVerifyEmail(ctx, q, &worker.PayloadSendVerifyEmail{
+				EmailId: verifyEmail.ID,
+			})
+		},
 	}
 
-	user, err := server.store.CreateUser(ctx, arg)
+	result, err := server.store.CreateUserTx(ctx, arg)
 	if err != nil {
 		if pqErr, value := err.(*pq.Error); value {
 			switch pqErr.Code.Name() {
@@ -68,10 +83,87 @@ func (server *Server) createUser(ctx *gin.Context) {
 		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
 		return
 	}
-	responseUser := newUserResponse(user)
+	responseUser := newUserResponse(result.User)
 	ctx.JSON(http.StatusOK, responseUser)
 }
 
+type verifyEmailRequest struct {
+	EmailId    int64  `form:"emailId" binding:"required,min=1"`
+	SecretCode string `form:"secretCode" binding:"required,min=32,max=128"`
+}
+
+type verifyEmailResponse struct {
+	IsVerified bool `json:"isVerified"`
+}
+
+// verifyEmail is reached through the link of the verification email, so it is a GET without authentication
+func (server *Server) verifyEmail(ctx *gin.Context) {
+	var req verifyEmailRequest
+	if err := ctx.ShouldBindQuery(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+	result, err := server.store.VerifyEmailTx(ctx, db.VerifyEmailTxParams{
+		EmailId:    req.EmailId,
+		SecretCode: req.SecretCode,
+	})
+	if err != nil {
+		if errors.Is(err, db.ErrInvalidVerifyEmail) {
+			ctx.JSON(http.StatusBadRequest, errorResponse(err))
+			return
+		}
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+	ctx.JSON(http.StatusOK, verifyEmailResponse{IsVerified: result.User.IsEmailVerified})
+}
+
+type resendVerifyEmailResponse struct {
+	Email     string    `json:"email"`
+	ExpiredAt time.Time `json:"expiredAt"`
+}
+
+// resendVerifyEmail sends a new verification code to the email of the authenticated user, e.g.
+// after the previous one expired. Like for a new user the email is sent in the background.
+func (server *Server) resendVerifyEmail(ctx *gin.Context) {
+	var uri userUri
+	if err := ctx.ShouldBindUri(&uri); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+	authPayload := ctx.MustGet(authorizationPayloadKey).(*token.Payload)
+	if uri.Username != authPayload.Username {
+		err := errors.New("cannot verify the email of another user")
+		ctx.JSON(http.StatusForbidden, errorResponse(err))
+		return
+	}
+	result, err := server.store.ResendVerifyEmailTx(ctx, db.ResendVerifyEmailTxParams{
+		Username:   uri.Username,
+		SecretCode: util.RandomString(32),
+		AfterCreate: func(q db.Querier, user db.User, verifyEmail db.VerifyEmail) error {
+			return server.taskDistributor.DistributeTaskSendVerifyEmail(ctx, q, &worker.PayloadSendVerifyEmail{
+				EmailId: verifyEmail.ID,
+			})
+		},
+	})
+	if err != nil {
+		if err == sql.ErrNoRows {
+			ctx.JSON(http.StatusNotFound, errorResponse(err))
+			return
+		}
+		if errors.Is(err, db.ErrEmailAlreadyVerified) {
+			ctx.JSON(http.StatusConflict, errorResponse(err))
+			return
+		}
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+	ctx.JSON(http.StatusOK, resendVerifyEmailResponse{
+		Email:     result.VerifyEmail.Email,
+		ExpiredAt: result.VerifyEmail.ExpiredAt,
+	})
+}
+
 type loginUserRequest struct {
 	Username string `json:"username" binding:"required,alphanum,min=3,max=24"`
 	Password string `json:"password" binding:"required,alphanum,min=8,max=60"`
@@ -107,12 +199,12 @@ func (server *Server) loginUser(ctx *gin.Context) {
 		return
 	}
 
-	accessToken, accessPayload, err := server.tokenMaker.CreateToken(user.Username, server.config.AccessTokenDuration)
+	accessToken, accessPayload, err := server.tokenMaker.CreateToken(user.Username, user.Role, server.config.AccessTokenDuration)
 	if err != nil {
 		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
 		return
 	}
-	refreshToken, refreshPayload, err := server.tokenMaker.CreateToken(user.Username, server.config.RefreshTokenDuration)
+	refreshToken, refreshPayload, err := server.tokenMaker.CreateToken(user.Username, user.Role, server.config.RefreshTokenDuration)
 	if err != nil {
 		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
 		return
@@ -187,7 +279,13 @@ func (server *Server) renewAccessToken(ctx *gin.Context) {
 		ctx.JSON(http.StatusUnauthorized, errorResponse(err))
 		return
 	}
-	accessToken, accessPayload, err := server.tokenMaker.CreateToken(session.Username, server.config.AccessTokenDuration)
+	// the role may have changed since the refresh token was created
+	user, err := server.store.GetUser(ctx, session.Username)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+	accessToken, accessPayload, err := server.tokenMaker.CreateToken(user.Username, user.Role, server.config.AccessTokenDuration)
 	if err != nil {
 		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
 		return
@@ -217,6 +315,8 @@ func newUserResponse(user db.User) userResponse {
 		Lastname1:         user.Lastname1,
 		Lastname2:         util.SqlNullStringToStringPtr(user.Lastname2),
 		Email:             user.Email,
+		Role:              user.Role,
+		IsEmailVerified:   user.IsEmailVerified,
 		PasswordChangedAt: user.PasswordChangedAt,
 		CreatedAt:         user.CreatedAt,
 	}
@@ -243,59 +343,226 @@ func newUserResponse(user db.User) userResponse {
 // 	ctx.JSON(http.StatusOK, user)
 // }
 
-// type getUsersRequest struct {
-// 	Offset *int32 `form:"offset" binding:"required,min=0"`
-// 	Limit  int32  `form:"limit" binding:"required,min=5,max=100"`
-// }
+type getUsersRequest struct {
+	Offset *int32 `form:"offset" binding:"required,min=0"`
+	Limit  int32  `form:"limit" binding:"required,min=5,max=100"`
+}
 
-// func (server *Server) getUsers(ctx *gin.Context) {
-// 	var req getUsersRequest
-// 	if err := ctx.ShouldBindQuery(&req); err != nil {
-// 		ctx.JSON(http.StatusBadRequest, errorResponse(err))
-// 		return
-// 	}
-// 	arg := db.GetUsersParams{
-// 		Limit:  req.Limit,
-// 		Offset: *req.Offset * req.Limit,
-// 	}
-// 	users, err := server.store.GetUsers(ctx, arg)
-// 	if err != nil {
-// 		if err == sql.ErrNoRows {
-// 			ctx.JSON(http.StatusNotFound, errorResponse(err))
-// 		}
-// 		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
-// 		return
-// 	}
-// 	ctx.JSON(http.StatusOK, users)
-// }
+// getUsers lists all users, only admins can reach it
+func (server *Server) getUsers(ctx *gin.Context) {
+	var req getUsersRequest
+	if err := ctx.ShouldBindQuery(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+	arg := db.GetUsersParams{
+		Limit:  req.Limit,
+		Offset: *req.Offset * req.Limit,
+	}
+	users, err := server.store.GetUsers(ctx, arg)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+	response := make([]userResponse, len(users))
+	for i, user := range users {
+		response[i] = newUserResponse(user)
+	}
+	ctx.JSON(http.StatusOK, response)
+}
 
-// type updateUserRequest struct {
-// 	Amount int64 `json:"amount" binding:"required,gte=0"`
-// }
+type userUri struct {
+	Username string `uri:"username" binding:"required,alphanum"`
+}
 
-// func (server *Server) updateUser(ctx *gin.Context) {
-// 	var req1 getUserRequest
-// 	var req2 updateUserRequest
-// 	if err := ctx.ShouldBindUri(&req1); err != nil {
-// 		ctx.JSON(http.StatusBadRequest, errorResponse(err))
-// 		return
-// 	}
-// 	if err := ctx.ShouldBindJSON(&req2); err != nil {
-// 		ctx.JSON(http.StatusBadRequest, errorResponse(err))
-// 		return
-// 	}
-// 	arg := db.AddAmountUserParams{
-// 		ID:     req1.ID,
-// 		Amount: req2.Amount,
-// 	}
-// 	user, err := server.store.AddAmountUser(ctx, arg)
-// 	if err != nil {
-// 		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
-// 		return
-// 	}
-// 	ctx.JSON(http.StatusOK, user)
+type updateUserRoleRequest struct {
+	Role string `json:"role" binding:"required,role"`
+}
 
-// }
+// updateUserRole changes the role of a user, only admins can reach it. Tokens created before keep
+// the old role until they are renewed.
+func (server *Server) updateUserRole(ctx *gin.Context) {
+	var uri userUri
+	if err := ctx.ShouldBindUri(&uri); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+	var req updateUserRoleRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+	user, err := server.store.UpdateUserRole(ctx, db.UpdateUserRoleParams{
+		Username: uri.Username,
+		Role:     req.Role,
+	})
+	if err != nil {
+		if err == sql.ErrNoRows {
+			ctx.JSON(http.StatusNotFound, errorResponse(err))
+			return
+		}
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+	ctx.JSON(http.StatusOK, newUserResponse(user))
+}
+
+// updateUserRequest changes the fields that are set, an empty name2 or lastname2 removes it.
+// Changing the email or the password requires the current password.
+type updateUserRequest struct {
+	Name1           *string `json:"name1"`
+	Name2           *string `json:"name2"`
+	Lastname1       *string `json:"lastname1"`
+	Lastname2       *string `json:"lastname2"`
+	Email           *string `json:"email"`
+	Password        *string `json:"password"`
+	CurrentPassword string  `json:"currentPassword"`
+}
+
+// updateUser updates the profile of the authenticated user. A password change blocks the other
+// sessions of the user and revokes the access tokens issued before it, the session of the request
+// keeps working once it renews its access token. A new email gets a verification email like the one
+// of a new user.
+func (server *Server) updateUser(ctx *gin.Context) {
+	var uri userUri
+	if err := ctx.ShouldBindUri(&uri); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+	var req updateUserRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+	authPayload := ctx.MustGet(authorizationPayloadKey).(*token.Payload)
+	if uri.Username != authPayload.Username {
+		err := errors.New("cannot update another user")
+		ctx.JSON(http.StatusForbidden, errorResponse(err))
+		return
+	}
+	if err := validateUpdateUserRequest(req); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	user, err := server.store.GetUser(ctx, authPayload.Username)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			ctx.JSON(http.StatusNotFound, errorResponse(err))
+			return
+		}
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+	arg := db.UpdateUserParams{
+		Username:          user.Username,
+		Name1:             user.Name1,
+		Name2:             user.Name2,
+		Lastname1:         user.Lastname1,
+		Lastname2:         user.Lastname2,
+		Email:             user.Email,
+		HashedPassword:    user.HashedPassword,
+		PasswordChangedAt: user.PasswordChangedAt,
+	}
+	if req.Name1 != nil {
+		arg.Name1 = *req.Name1
+	}
+	if req.Name2 != nil {
+		arg.Name2 = util.StringPtrToSqlNullString(req.Name2)
+	}
+	if req.Lastname1 != nil {
+		arg.Lastname1 = *req.Lastname1
+	}
+	if req.Lastname2 != nil {
+		arg.Lastname2 = util.StringPtrToSqlNullString(req.Lastname2)
+	}
+
+	if (req.Email != nil && *req.Email != user.Email) || req.Password != nil {
+		if req.CurrentPassword == "" {
+			err := errors.New("the current password is required to change the email or the password")
+			ctx.JSON(http.StatusBadRequest, errorResponse(err))
+			return
+		}
+		if err := util.CheckPasswordHash(req.CurrentPassword, user.HashedPassword); err != nil {
+			ctx.JSON(http.StatusUnauthorized, errorResponse(err))
+			return
+		}
+	}
+	if req.Email != nil {
+		arg.Email = *req.Email
+	}
+	if req.Password != nil {
+		hashedPassword, err := util.HashPassword(*req.Password)
+		if err != nil {
+			ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+			return
+		}
+		arg.HashedPassword = hashedPassword
+		arg.PasswordChangedAt = time.Now()
+	}
+
+	txArg := db.UpdateUserTxParams{
+		User:               arg,
+		BlockOtherSessions: req.Password != nil,
+		AccessToken:        ctx.GetString(authorizationTokenKey),
+	}
+	if arg.Email != user.Email {
+		// the new email is verified like the one of a new user
+		txArg.SecretCode = util.RandomString(32)
+		txArg.AfterEmailChange = func(q db.Querier, user db.User, verifyEmail db.VerifyEmail) error {
+			return server.taskDistributor.DistributeTaskSendVerifyEmail(ctx, q, &worker.PayloadSendVerifyEmail{
+				EmailId: verifyEmail.ID,
+			})
+		}
+	}
+	result, err := server.store.UpdateUserTx(ctx, txArg)
+	if err != nil {
+		if pqErr, value := err.(*pq.Error); value {
+			switch pqErr.Code.Name() {
+			case "unique_violation":
+				ctx.JSON(http.StatusForbidden, errorResponse(err))
+				return
+			}
+		}
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+	ctx.JSON(http.StatusOK, newUserResponse(result.User))
+}
+
+func validateUpdateUserRequest(req updateUserRequest) error {
+	if req.Name1 != nil {
+		if err := validator.ValidateName(*req.Name1); err != nil {
+			return err
+		}
+	}
+	if req.Name2 != nil && *req.Name2 != "" {
+		if err := validator.ValidateName(*req.Name2); err != nil {
+			return err
+		}
+	}
+	if req.Lastname1 != nil {
+		if err := validator.ValidateName(*req.Lastname1); err != nil {
+			return err
+		}
+	}
+	if req.Lastname2 != nil && *req.Lastname2 != "" {
+		if err := validator.ValidateName(*req.Lastname2); err != nil {
+			return err
+		}
+	}
+	if req.Email != nil {
+		if err := validator.ValidateEmail(*req.Email); err != nil {
+			return err
+		}
+	}
+	if req.Password != nil {
+		if err := validator.ValidatePassword(*req.Password); err != nil {
+			return err
+		}
+	}
+	return nil
+}
 
 // func (server *Server) deleteUser(ctx *gin.Context) {
 // 	var req getUserRequest
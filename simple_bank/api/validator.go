@@ -11,3 +11,18 @@ var validCurrency validator.Func = func(fl validator.FieldLevel) bool {
 	}
 	return false
 }
+
+var validSchedule validator.Func = func(fl validator.FieldLevel) bool {
+	if schedule, value := fl.Field().Interface().(string); value {
+		_, err := util.ParseSchedule(schedule)
+		return err == nil
+	}
+	return false
+}
+
+var validRole validator.Func = func(fl validator.FieldLevel) bool {
+	if role, value := fl.Field().Interface().(string); value {
+		return util.IsSupportedRole(role)
+	}
+	return false
+}
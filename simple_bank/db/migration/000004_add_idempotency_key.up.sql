@@ -0,0 +1,10 @@
+CREATE TABLE "idempotency_key" (
+  "username" varchar NOT NULL,
+  "key" varchar NOT NULL,
+  "request_hash" varchar NOT NULL,
+  "response" jsonb NOT NULL,
+  "createdAt" timestamptz NOT NULL DEFAULT (now()),
+  PRIMARY KEY ("username", "key")
+);
+
+ALTER TABLE "idempotency_key" ADD FOREIGN KEY ("username") REFERENCES "user"("username");
\ No newline at end of file
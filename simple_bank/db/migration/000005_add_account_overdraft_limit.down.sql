@@ -0,0 +1,5 @@
+ALTER TABLE "account" DROP CONSTRAINT IF EXISTS "account_balance_check";
+
+ALTER TABLE "account" DROP CONSTRAINT IF EXISTS "account_overdraft_limit_check";
+
+ALTER TABLE "account" DROP COLUMN IF EXISTS "overdraftLimit";
\ No newline at end of file
@@ -0,0 +1,10 @@
+ALTER TABLE "account" ADD COLUMN "overdraftLimit" bigint NOT NULL DEFAULT 0;
+
+COMMENT ON COLUMN "account"."overdraftLimit" IS 'how far below zero the balance may go';
+
+ALTER TABLE "account" ADD CONSTRAINT "account_overdraft_limit_check" CHECK ("overdraftLimit" >= 0);
+
+-- accounts already below zero keep their balance as overdraft, or the check below could not be added
+UPDATE "account" SET "overdraftLimit" = -"balance" WHERE "balance" < 0;
+
+ALTER TABLE "account" ADD CONSTRAINT "account_balance_check" CHECK ("balance" >= -"overdraftLimit");
\ No newline at end of file
@@ -0,0 +1,7 @@
+ALTER TABLE "transfer" DROP COLUMN IF EXISTS "rateTimestamp";
+
+ALTER TABLE "transfer" DROP COLUMN IF EXISTS "exchangeRate";
+
+ALTER TABLE "transfer" DROP COLUMN IF EXISTS "toAmount";
+
+COMMENT ON COLUMN "transfer"."amount" IS 'must be positive';
\ No newline at end of file
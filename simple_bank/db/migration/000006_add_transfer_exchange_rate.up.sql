@@ -0,0 +1,17 @@
+ALTER TABLE "transfer" ADD COLUMN "toAmount" bigint;
+
+UPDATE "transfer" SET "toAmount" = "amount";
+
+ALTER TABLE "transfer" ALTER COLUMN "toAmount" SET NOT NULL;
+
+ALTER TABLE "transfer" ADD COLUMN "exchangeRate" numeric NOT NULL DEFAULT 1;
+
+ALTER TABLE "transfer" ADD COLUMN "rateTimestamp" timestamptz;
+
+COMMENT ON COLUMN "transfer"."amount" IS 'must be positive, in the source account currency';
+
+COMMENT ON COLUMN "transfer"."toAmount" IS 'must be positive, in the destination account currency';
+
+COMMENT ON COLUMN "transfer"."exchangeRate" IS 'destination units per source unit, 1 for same currency transfers';
+
+COMMENT ON COLUMN "transfer"."rateTimestamp" IS 'when the applied rate was published, null for same currency transfers';
\ No newline at end of file
@@ -0,0 +1,5 @@
+ALTER TABLE "account" DROP CONSTRAINT IF EXISTS "account_currency_fkey";
+
+UPDATE "account" SET "currency" = 'YEN' WHERE "currency" = 'JPY';
+
+DROP TABLE IF EXISTS "currency";
\ No newline at end of file
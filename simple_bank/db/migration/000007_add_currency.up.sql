@@ -0,0 +1,24 @@
+CREATE TABLE "currency" (
+  "code" varchar(3) PRIMARY KEY,
+  "minorUnit" smallint NOT NULL,
+  "symbol" varchar NOT NULL,
+  "enabled" boolean NOT NULL DEFAULT true,
+  "createdAt" timestamptz NOT NULL DEFAULT (now()),
+  CONSTRAINT "currency_code_check" CHECK ("code" ~ '^[A-Z]{3}$'),
+  CONSTRAINT "currency_minor_unit_check" CHECK ("minorUnit" BETWEEN 0 AND 4)
+);
+
+COMMENT ON COLUMN "currency"."code" IS 'ISO 4217 alphabetic code';
+
+COMMENT ON COLUMN "currency"."minorUnit" IS 'digits after the decimal point, amounts are stored in minor units';
+
+INSERT INTO "currency" ("code", "minorUnit", "symbol") VALUES
+  ('USD', 2, '$'),
+  ('EUR', 2, '€'),
+  ('JPY', 0, '¥'),
+  ('CAD', 2, 'CA$'),
+  ('AUD', 2, 'A$');
+
+UPDATE "account" SET "currency" = 'JPY' WHERE "currency" = 'YEN';
+
+ALTER TABLE "account" ADD CONSTRAINT "account_currency_fkey" FOREIGN KEY ("currency") REFERENCES "currency" ("code");
\ No newline at end of file
@@ -0,0 +1,5 @@
+DROP INDEX IF EXISTS "entry_external_reference_key";
+
+ALTER TABLE "entry" DROP COLUMN IF EXISTS "externalReference";
+
+ALTER TABLE "entry" DROP COLUMN IF EXISTS "reason";
\ No newline at end of file
@@ -0,0 +1,11 @@
+ALTER TABLE "entry" ADD COLUMN "reason" varchar NOT NULL DEFAULT '';
+
+ALTER TABLE "entry" ADD COLUMN "externalReference" varchar NOT NULL DEFAULT '';
+
+UPDATE "entry" SET "reason" = 'transfer';
+
+COMMENT ON COLUMN "entry"."reason" IS 'why the balance changed, transfer for transfer entries';
+
+COMMENT ON COLUMN "entry"."externalReference" IS 'id of the deposit or withdrawal in the external system, empty for transfers';
+
+CREATE UNIQUE INDEX "entry_external_reference_key" ON "entry" ("accountId", "externalReference") WHERE "externalReference" <> '';
\ No newline at end of file
@@ -0,0 +1 @@
+ALTER TABLE "entry" DROP COLUMN IF EXISTS "transferId";
\ No newline at end of file
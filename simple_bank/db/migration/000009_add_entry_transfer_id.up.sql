@@ -0,0 +1,16 @@
+ALTER TABLE "entry" ADD COLUMN "transferId" bigint;
+
+COMMENT ON COLUMN "entry"."transferId" IS 'transfer that booked the entry, null for deposits and withdrawals';
+
+ALTER TABLE "entry" ADD FOREIGN KEY ("transferId") REFERENCES "transfer" ("id");
+
+CREATE INDEX ON "entry" ("transferId");
+
+-- entries of a transfer were inserted in the same transaction, so they share its createdAt
+UPDATE "entry" e
+SET "transferId" = t.id
+FROM "transfer" t
+WHERE e."transferId" IS NULL
+  AND e."createdAt" = t."createdAt"
+  AND ((e."accountId" = t."fromAccountId" AND e.amount = -t.amount)
+    OR (e."accountId" = t."toAccountId" AND e.amount = t."toAmount"));
\ No newline at end of file
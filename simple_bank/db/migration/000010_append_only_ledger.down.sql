@@ -0,0 +1,11 @@
+ALTER TABLE "transfer" DROP COLUMN IF EXISTS "reversedTransferId";
+
+DROP TRIGGER IF EXISTS "transfer_no_truncate" ON "transfer";
+
+DROP TRIGGER IF EXISTS "transfer_append_only" ON "transfer";
+
+DROP TRIGGER IF EXISTS "entry_no_truncate" ON "entry";
+
+DROP TRIGGER IF EXISTS "entry_append_only" ON "entry";
+
+DROP FUNCTION IF EXISTS "forbid_ledger_mutation"();
\ No newline at end of file
@@ -0,0 +1,30 @@
+CREATE FUNCTION "forbid_ledger_mutation"() RETURNS trigger AS $$
+BEGIN
+  RAISE EXCEPTION '% is append-only, % is not allowed', TG_TABLE_NAME, TG_OP
+    USING ERRCODE = 'restrict_violation';
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE TRIGGER "entry_append_only"
+BEFORE UPDATE OR DELETE ON "entry"
+FOR EACH ROW EXECUTE FUNCTION "forbid_ledger_mutation"();
+
+CREATE TRIGGER "entry_no_truncate"
+BEFORE TRUNCATE ON "entry"
+FOR EACH STATEMENT EXECUTE FUNCTION "forbid_ledger_mutation"();
+
+CREATE TRIGGER "transfer_append_only"
+BEFORE UPDATE OR DELETE ON "transfer"
+FOR EACH ROW EXECUTE FUNCTION "forbid_ledger_mutation"();
+
+CREATE TRIGGER "transfer_no_truncate"
+BEFORE TRUNCATE ON "transfer"
+FOR EACH STATEMENT EXECUTE FUNCTION "forbid_ledger_mutation"();
+
+ALTER TABLE "transfer" ADD COLUMN "reversedTransferId" bigint;
+
+COMMENT ON COLUMN "transfer"."reversedTransferId" IS 'transfer this one reverses, null for regular transfers';
+
+ALTER TABLE "transfer" ADD FOREIGN KEY ("reversedTransferId") REFERENCES "transfer" ("id");
+
+CREATE UNIQUE INDEX ON "transfer" ("reversedTransferId");
\ No newline at end of file
@@ -0,0 +1,3 @@
+DROP INDEX IF EXISTS "transfer_reversedTransferId_idx";
+
+CREATE UNIQUE INDEX ON "transfer" ("reversedTransferId");
\ No newline at end of file
@@ -0,0 +1,3 @@
+DROP TABLE IF EXISTS "scheduled_transfer_run";
+
+DROP TABLE IF EXISTS "scheduled_transfer";
\ No newline at end of file
@@ -0,0 +1,56 @@
+CREATE TABLE "scheduled_transfer" (
+  "id" bigserial PRIMARY KEY,
+  "username" varchar NOT NULL,
+  "fromAccountId" bigint NOT NULL,
+  "toAccountId" bigint NOT NULL,
+  "amount" bigint NOT NULL CHECK ("amount" > 0),
+  "schedule" varchar NOT NULL DEFAULT '',
+  "nextRunAt" timestamptz NOT NULL,
+  "status" varchar NOT NULL DEFAULT 'active',
+  "attempts" integer NOT NULL DEFAULT 0,
+  "lockedUntil" timestamptz NOT NULL DEFAULT '0001-01-01 00:00:00Z',
+  "createdAt" timestamptz NOT NULL DEFAULT (now())
+);
+
+CREATE TABLE "scheduled_transfer_run" (
+  "id" bigserial PRIMARY KEY,
+  "scheduledTransferId" bigint NOT NULL,
+  "scheduledFor" timestamptz NOT NULL,
+  "attempt" integer NOT NULL,
+  "status" varchar NOT NULL,
+  "transferId" bigint,
+  "error" varchar NOT NULL DEFAULT '',
+  "createdAt" timestamptz NOT NULL DEFAULT (now())
+);
+
+COMMENT ON COLUMN "scheduled_transfer"."amount" IS 'in the source account currency';
+
+COMMENT ON COLUMN "scheduled_transfer"."schedule" IS 'cron expression, empty for a one-off transfer';
+
+COMMENT ON COLUMN "scheduled_transfer"."status" IS 'active, paused, completed, failed or cancelled';
+
+COMMENT ON COLUMN "scheduled_transfer"."attempts" IS 'failed attempts of the current run';
+
+COMMENT ON COLUMN "scheduled_transfer"."lockedUntil" IS 'a scheduler claimed the transfer and is running it until then';
+
+COMMENT ON COLUMN "scheduled_transfer_run"."scheduledFor" IS 'when the attempt was due';
+
+COMMENT ON COLUMN "scheduled_transfer_run"."status" IS 'succeeded or failed';
+
+COMMENT ON COLUMN "scheduled_transfer_run"."transferId" IS 'booked transfer, null when the attempt failed';
+
+ALTER TABLE "scheduled_transfer" ADD FOREIGN KEY ("username") REFERENCES "user" ("username");
+
+ALTER TABLE "scheduled_transfer" ADD FOREIGN KEY ("fromAccountId") REFERENCES "account" ("id");
+
+ALTER TABLE "scheduled_transfer" ADD FOREIGN KEY ("toAccountId") REFERENCES "account" ("id");
+
+ALTER TABLE "scheduled_transfer_run" ADD FOREIGN KEY ("scheduledTransferId") REFERENCES "scheduled_transfer" ("id");
+
+ALTER TABLE "scheduled_transfer_run" ADD FOREIGN KEY ("transferId") REFERENCES "transfer" ("id");
+
+CREATE INDEX ON "scheduled_transfer" ("username");
+
+CREATE INDEX ON "scheduled_transfer" ("nextRunAt") WHERE "status" = 'active';
+
+CREATE INDEX ON "scheduled_transfer_run" ("scheduledTransferId");
\ No newline at end of file
@@ -0,0 +1,5 @@
+DROP INDEX IF EXISTS "account_username_currency_idx";
+
+CREATE UNIQUE INDEX ON "account" ("username", "currency");
+
+ALTER TABLE "account" DROP COLUMN IF EXISTS "status";
\ No newline at end of file
@@ -0,0 +1,10 @@
+ALTER TABLE "account" ADD COLUMN "status" varchar NOT NULL DEFAULT 'active';
+
+ALTER TABLE "account" ADD CONSTRAINT "account_status_check" CHECK ("status" IN ('active', 'frozen', 'closed'));
+
+COMMENT ON COLUMN "account"."status" IS 'active, frozen or closed, only active accounts can move money';
+
+-- a closed account keeps its history, the owner can open a new one in the same currency
+DROP INDEX IF EXISTS "account_username_currency_idx";
+
+CREATE UNIQUE INDEX "account_username_currency_idx" ON "account" ("username", "currency") WHERE "status" <> 'closed';
\ No newline at end of file
@@ -0,0 +1,5 @@
+DROP TABLE IF EXISTS "user_limit";
+
+DROP TABLE IF EXISTS "account_limit";
+
+DROP INDEX IF EXISTS "transfer_fromAccountId_createdAt_idx";
\ No newline at end of file
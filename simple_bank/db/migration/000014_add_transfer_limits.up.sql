@@ -0,0 +1,37 @@
+CREATE TABLE "account_limit" (
+  "accountId" bigint PRIMARY KEY,
+  "maxTransferAmount" bigint CHECK ("maxTransferAmount" > 0),
+  "dailyAmount" bigint CHECK ("dailyAmount" > 0),
+  "monthlyAmount" bigint CHECK ("monthlyAmount" > 0),
+  "hourlyTransfers" integer CHECK ("hourlyTransfers" > 0),
+  "updatedAt" timestamptz NOT NULL DEFAULT (now())
+);
+
+CREATE TABLE "user_limit" (
+  "username" varchar NOT NULL,
+  "currency" varchar NOT NULL,
+  "maxTransferAmount" bigint CHECK ("maxTransferAmount" > 0),
+  "dailyAmount" bigint CHECK ("dailyAmount" > 0),
+  "monthlyAmount" bigint CHECK ("monthlyAmount" > 0),
+  "hourlyTransfers" integer CHECK ("hourlyTransfers" > 0),
+  "updatedAt" timestamptz NOT NULL DEFAULT (now()),
+  PRIMARY KEY ("username", "currency")
+);
+
+CREATE INDEX ON "transfer" ("fromAccountId", "createdAt");
+
+COMMENT ON COLUMN "account_limit"."maxTransferAmount" IS 'in the account currency, null means no limit';
+
+COMMENT ON COLUMN "account_limit"."dailyAmount" IS 'outgoing total since midnight UTC';
+
+COMMENT ON COLUMN "account_limit"."monthlyAmount" IS 'outgoing total since the first of the month UTC';
+
+COMMENT ON COLUMN "account_limit"."hourlyTransfers" IS 'outgoing transfers in the last hour';
+
+COMMENT ON COLUMN "user_limit"."maxTransferAmount" IS 'applies to all accounts of the user in the currency, null means no limit';
+
+ALTER TABLE "account_limit" ADD FOREIGN KEY ("accountId") REFERENCES "account" ("id");
+
+ALTER TABLE "user_limit" ADD FOREIGN KEY ("username") REFERENCES "user" ("username");
+
+ALTER TABLE "user_limit" ADD FOREIGN KEY ("currency") REFERENCES "currency" ("code");
\ No newline at end of file
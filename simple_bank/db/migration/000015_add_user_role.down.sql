@@ -0,0 +1 @@
+ALTER TABLE "user" DROP COLUMN IF EXISTS "role";
\ No newline at end of file
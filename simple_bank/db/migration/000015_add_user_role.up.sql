@@ -0,0 +1,5 @@
+ALTER TABLE "user" ADD COLUMN "role" varchar NOT NULL DEFAULT 'depositor';
+
+ALTER TABLE "user" ADD CONSTRAINT "user_role_check" CHECK ("role" IN ('depositor', 'banker', 'admin'));
+
+COMMENT ON COLUMN "user"."role" IS 'depositor, banker or admin';
\ No newline at end of file
@@ -0,0 +1,3 @@
+DROP TABLE IF EXISTS "verify_email";
+
+ALTER TABLE "user" DROP COLUMN IF EXISTS "isEmailVerified";
\ No newline at end of file
@@ -0,0 +1,24 @@
+ALTER TABLE "user" ADD COLUMN "isEmailVerified" boolean NOT NULL DEFAULT false;
+
+-- users that signed up before the verification existed keep using their accounts
+UPDATE "user" SET "isEmailVerified" = true;
+
+CREATE TABLE "verify_email" (
+  "id" bigserial PRIMARY KEY,
+  "username" varchar NOT NULL,
+  "email" varchar NOT NULL,
+  "secretCode" varchar NOT NULL,
+  "isUsed" boolean NOT NULL DEFAULT false,
+  "createdAt" timestamptz NOT NULL DEFAULT (now()),
+  "expiredAt" timestamptz NOT NULL DEFAULT (now() + interval '15 minutes')
+);
+
+COMMENT ON COLUMN "user"."isEmailVerified" IS 'accounts can only be created and transfers made once the email is verified';
+
+COMMENT ON COLUMN "verify_email"."email" IS 'address the code was sent to, the code does not verify another email of the user';
+
+COMMENT ON COLUMN "verify_email"."isUsed" IS 'a code verifies the email only once';
+
+ALTER TABLE "verify_email" ADD FOREIGN KEY ("username") REFERENCES "user" ("username");
+
+CREATE INDEX ON "verify_email" ("username");
\ No newline at end of file
@@ -0,0 +1 @@
+DROP TABLE IF EXISTS "task";
\ No newline at end of file
@@ -0,0 +1,27 @@
+CREATE TABLE "task" (
+  "id" bigserial PRIMARY KEY,
+  "type" varchar NOT NULL,
+  "payload" jsonb NOT NULL,
+  "status" varchar NOT NULL DEFAULT 'pending',
+  "attempts" integer NOT NULL DEFAULT 0,
+  "maxAttempts" integer NOT NULL,
+  "runAt" timestamptz NOT NULL DEFAULT (now()),
+  "lockedUntil" timestamptz NOT NULL DEFAULT '0001-01-01 00:00:00Z',
+  "lastError" varchar NOT NULL DEFAULT '',
+  "createdAt" timestamptz NOT NULL DEFAULT (now()),
+  "finishedAt" timestamptz
+);
+
+COMMENT ON COLUMN "task"."type" IS 'selects the handler of the task, e.g. task:send_verify_email';
+
+COMMENT ON COLUMN "task"."status" IS 'pending, done or dead, a dead task failed maxAttempts times and is not retried';
+
+COMMENT ON COLUMN "task"."attempts" IS 'failed attempts so far';
+
+COMMENT ON COLUMN "task"."runAt" IS 'the task is not run before, pushed back after every failed attempt';
+
+COMMENT ON COLUMN "task"."lockedUntil" IS 'a processor claimed the task and is running it until then';
+
+COMMENT ON COLUMN "task"."lastError" IS 'error of the last failed attempt';
+
+CREATE INDEX ON "task" ("runAt") WHERE "status" = 'pending';
\ No newline at end of file
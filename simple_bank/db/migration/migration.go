@@ -0,0 +1,72 @@
+package migration
+
+import (
+	"embed"
+	"errors"
+	"fmt"
+
+	"github.com/golang-migrate/migrate/v4"
+	_ "github.com/golang-migrate/migrate/v4/database/postgres"
+	"github.com/golang-migrate/migrate/v4/source/iofs"
+)
+
+//go:embed *.sql
+var files embed.FS
+
+// NewMigrate returns a migrator that reads the migrations embedded in the binary
+func NewMigrate(dbSource string) (*migrate.Migrate, error) {
+	source, err := iofs.New(files, ".")
+	if err != nil {
+		return nil, fmt.Errorf("cannot read embedded migrations: %w", err)
+	}
+	m, err := migrate.NewWithSourceInstance("iofs", source, dbSource)
+	if err != nil {
+		return nil, fmt.Errorf("cannot create migrate instance: %w", err)
+	}
+	return m, nil
+}
+
+// Up applies every pending migration
+func Up(dbSource string) error {
+	m, err := NewMigrate(dbSource)
+	if err != nil {
+		return err
+	}
+	defer m.Close()
+	err = m.Up()
+	if err != nil && !errors.Is(err, migrate.ErrNoChange) {
+		return fmt.Errorf("failed to run migrate up: %w", err)
+	}
+	return nil
+}
+
+// Down rolls back the last n applied migrations
+func Down(dbSource string, n int) error {
+	if n < 1 {
+		return fmt.Errorf("number of migrations to roll back must be positive")
+	}
+	m, err := NewMigrate(dbSource)
+	if err != nil {
+		return err
+	}
+	defer m.Close()
+	err = m.Steps(-n)
+	if err != nil && !errors.Is(err, migrate.ErrNoChange) {
+		return fmt.Errorf("failed to run migrate down: %w", err)
+	}
+	return nil
+}
+
+// Version returns the current schema version and whether it is dirty
+func Version(dbSource string) (version uint, dirty bool, err error) {
+	m, err := NewMigrate(dbSource)
+	if err != nil {
+		return 0, false, err
+	}
+	defer m.Close()
+	version, dirty, err = m.Version()
+	if errors.Is(err, migrate.ErrNilVersion) {
+		return 0, false, nil
+	}
+	return version, dirty, err
+}
@@ -34,6 +34,14 @@ set balance = balance + sqlc.arg(amount)
 where id = $1
 RETURNING *;
 
--- name: DeleteAccount :exec
-delete from account
-where id = $1;
\ No newline at end of file
+-- name: UpdateAccountOverdraftLimit :one
+update account
+set "overdraftLimit" = $2
+where id = $1
+RETURNING *;
+
+-- name: UpdateAccountStatus :one
+update account
+set status = $2
+where id = $1
+RETURNING *;
\ No newline at end of file
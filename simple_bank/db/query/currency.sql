@@ -0,0 +1,19 @@
+-- name: CreateCurrency :one
+INSERT INTO "currency" ("code", "minorUnit", "symbol", "enabled")
+VALUES ($1, $2, $3, $4)
+RETURNING *;
+
+-- name: GetCurrency :one
+SELECT * FROM "currency"
+WHERE "code" = $1
+LIMIT 1;
+
+-- name: ListCurrencies :many
+SELECT * FROM "currency"
+ORDER BY "code";
+
+-- name: UpdateCurrencyEnabled :one
+UPDATE "currency"
+SET "enabled" = $2
+WHERE "code" = $1
+RETURNING *;
\ No newline at end of file
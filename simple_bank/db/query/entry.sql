@@ -1,6 +1,6 @@
 -- name: CreateEntry :one
-insert into entry("accountId", amount)
-values($1, $2)
+insert into entry("accountId", amount, reason, "externalReference", "transferId")
+values($1, $2, $3, $4, $5)
 RETURNING *;
 
 -- name: GetEntry :one
@@ -15,12 +15,26 @@ from entry
 order by id
 limit $1 offset $2;
 
--- name: UpdateEntry :one
-update entry
-set amount = $2
-where id = $1
-RETURNING *;
-
--- name: DeleteEntry :exec
-delete from entry
-where id = $1;
\ No newline at end of file
+-- name: ListAccountEntries :many
+WITH ledger AS (
+  SELECT id,
+    "accountId",
+    amount,
+    "createdAt",
+    SUM(amount) OVER (ORDER BY "createdAt", id) AS cumulative,
+    SUM(amount) OVER () AS total
+  FROM entry
+  WHERE "accountId" = sqlc.arg(account_id)
+)
+SELECT ledger.id,
+  ledger."accountId",
+  ledger.amount,
+  ledger."createdAt",
+  (account.balance - ledger.total + ledger.cumulative)::bigint AS "runningBalance"
+FROM ledger
+JOIN account ON account.id = ledger."accountId"
+WHERE (sqlc.narg(from_time)::timestamptz IS NULL OR ledger."createdAt" >= sqlc.narg(from_time))
+  AND (sqlc.narg(to_time)::timestamptz IS NULL OR ledger."createdAt" < sqlc.narg(to_time))
+  AND (ledger."createdAt", ledger.id) > (sqlc.arg(after_created_at)::timestamptz, sqlc.arg(after_id)::bigint)
+ORDER BY ledger."createdAt", ledger.id
+LIMIT sqlc.arg(row_limit);
\ No newline at end of file
@@ -0,0 +1,15 @@
+-- name: CreateIdempotencyKey :one
+INSERT INTO "idempotency_key" (
+    username,
+    key,
+    request_hash,
+    response
+  )
+VALUES($1, $2, $3, $4)
+ON CONFLICT (username, key) DO NOTHING
+RETURNING *;
+
+-- name: GetIdempotencyKey :one
+SELECT * FROM "idempotency_key"
+WHERE username = $1 AND key = $2
+LIMIT 1;
\ No newline at end of file
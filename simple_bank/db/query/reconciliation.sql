@@ -0,0 +1,27 @@
+-- name: ListBalanceMismatches :many
+SELECT account.id,
+  account.currency,
+  account.balance,
+  COALESCE(SUM(entry.amount), 0)::bigint AS "entrySum"
+FROM account
+LEFT JOIN entry ON entry."accountId" = account.id
+GROUP BY account.id
+HAVING account.balance <> COALESCE(SUM(entry.amount), 0)
+ORDER BY account.id;
+
+-- name: ListUnbalancedTransfers :many
+SELECT transfer.id,
+  transfer."fromAccountId",
+  transfer."toAccountId",
+  transfer.amount,
+  transfer."toAmount",
+  COUNT(entry.id) AS "entryCount",
+  COALESCE(SUM(entry.amount) FILTER (WHERE entry."accountId" = transfer."fromAccountId"), 0)::bigint AS "fromEntrySum",
+  COALESCE(SUM(entry.amount) FILTER (WHERE entry."accountId" = transfer."toAccountId"), 0)::bigint AS "toEntrySum"
+FROM transfer
+LEFT JOIN entry ON entry."transferId" = transfer.id
+GROUP BY transfer.id
+HAVING COUNT(entry.id) <> 2
+  OR COALESCE(SUM(entry.amount) FILTER (WHERE entry."accountId" = transfer."fromAccountId"), 0) <> -transfer.amount
+  OR COALESCE(SUM(entry.amount) FILTER (WHERE entry."accountId" = transfer."toAccountId"), 0) <> transfer."toAmount"
+ORDER BY transfer.id;
@@ -0,0 +1,65 @@
+-- name: CreateScheduledTransfer :one
+insert into scheduled_transfer(username, "fromAccountId", "toAccountId", amount, schedule, "nextRunAt")
+values($1, $2, $3, $4, $5, $6)
+RETURNING *;
+
+-- name: GetScheduledTransfer :one
+select *
+from scheduled_transfer
+where id = $1
+limit 1;
+
+-- name: ListScheduledTransfers :many
+select *
+from scheduled_transfer
+where username = $1
+order by id
+limit $2 offset $3;
+
+-- name: UpdateScheduledTransfer :one
+update scheduled_transfer
+set amount = COALESCE(sqlc.narg(amount), amount),
+  schedule = COALESCE(sqlc.narg(schedule), schedule),
+  "nextRunAt" = COALESCE(sqlc.narg(next_run_at), "nextRunAt"),
+  status = COALESCE(sqlc.narg(status), status),
+  attempts = 0
+where id = sqlc.arg(id)
+RETURNING *;
+
+-- name: ClaimDueScheduledTransfers :many
+-- locks the due transfers for the caller until locked_until, rows locked by another scheduler are skipped
+update scheduled_transfer
+set "lockedUntil" = sqlc.arg(locked_until)
+where id in (
+    select id
+    from scheduled_transfer
+    where status = 'active'
+      and "nextRunAt" <= sqlc.arg(now)
+      and "lockedUntil" < sqlc.arg(now)
+    order by "nextRunAt"
+    limit sqlc.arg(row_limit)
+    for update skip locked
+  )
+RETURNING *;
+
+-- name: FinishScheduledTransferRun :one
+update scheduled_transfer
+set "nextRunAt" = $2,
+  -- a transfer paused or cancelled while it was running keeps its status
+  status = CASE WHEN status = 'active' THEN $3 ELSE status END,
+  attempts = $4,
+  "lockedUntil" = '0001-01-01 00:00:00Z'
+where id = $1
+RETURNING *;
+
+-- name: CreateScheduledTransferRun :one
+insert into scheduled_transfer_run("scheduledTransferId", "scheduledFor", attempt, status, "transferId", error)
+values($1, $2, $3, $4, $5, $6)
+RETURNING *;
+
+-- name: ListScheduledTransferRuns :many
+select *
+from scheduled_transfer_run
+where "scheduledTransferId" = $1
+order by id desc
+limit $2 offset $3;
\ No newline at end of file
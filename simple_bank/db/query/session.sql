@@ -36,4 +36,10 @@ LIMIT 1;
 
 -- name: DeleteSession :exec
 DELETE FROM "session"
-WHERE "id" = $1;
\ No newline at end of file
+WHERE "id" = $1;
+
+-- name: BlockOtherUserSessions :exec
+-- blocks the sessions of the user except the one the access token belongs to
+UPDATE "session"
+SET is_blocked = true
+WHERE username = $1 AND access_token <> $2;
\ No newline at end of file
@@ -0,0 +1,37 @@
+-- name: CreateTask :one
+insert into task(type, payload, "maxAttempts")
+values($1, $2, $3)
+RETURNING *;
+
+-- name: GetTask :one
+select *
+from task
+where id = $1
+limit 1;
+
+-- name: ClaimDueTasks :many
+-- locks the due tasks for the caller until locked_until, rows locked by another processor are skipped
+update task
+set "lockedUntil" = sqlc.arg(locked_until)
+where id in (
+    select id
+    from task
+    where status = 'pending'
+      and "runAt" <= sqlc.arg(now)
+      and "lockedUntil" < sqlc.arg(now)
+    order by "runAt"
+    limit sqlc.arg(row_limit)
+    for update skip locked
+  )
+RETURNING *;
+
+-- name: FinishTask :one
+update task
+set status = $2,
+  attempts = $3,
+  "runAt" = $4,
+  "lastError" = $5,
+  "finishedAt" = $6,
+  "lockedUntil" = '0001-01-01 00:00:00Z'
+where id = $1
+RETURNING *;
\ No newline at end of file
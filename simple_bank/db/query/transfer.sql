@@ -1,6 +1,6 @@
 -- name: CreateTransfer :one
-insert into transfer("fromAccountId", "toAccountId", "amount")
-values($1, $2, $3)
+insert into transfer("fromAccountId", "toAccountId", "amount", "toAmount", "exchangeRate", "rateTimestamp", "reversedTransferId")
+values($1, $2, $3, $4, $5, $6, $7)
 RETURNING *;
 
 -- name: GetTransfer :one
@@ -9,18 +9,46 @@ from transfer
 where id = $1
 limit 1;
 
+-- name: GetTransferReversedAmounts :one
+select COALESCE(SUM("toAmount"), 0)::bigint AS "reversedAmount",
+  COALESCE(SUM(amount), 0)::bigint AS "reversedToAmount"
+from transfer
+where "reversedTransferId" = $1;
+
 -- name: GetTransfers :many
 select *
 from transfer
 order by id
 limit $1 offset $2;
 
--- name: UpdateTransfer :one
-update transfer
-set amount = $2
-where id = $1
-RETURNING *;
-
--- name: DeleteTransfer :exec
-delete from transfer
-where id = $1;
\ No newline at end of file
+-- name: ListUserTransfers :many
+select *
+from transfer
+where id in (
+    -- incoming transfers, served by the ("toAccountId", "createdAt") index
+    select t.id
+    from transfer t
+      join account a on a.id = t."toAccountId"
+    where a.username = sqlc.arg(username)
+      and sqlc.arg(direction)::text <> 'out'
+      and (sqlc.narg(account_id)::bigint is null or t."toAccountId" = sqlc.narg(account_id))
+      and (sqlc.narg(counterparty_id)::bigint is null or t."fromAccountId" = sqlc.narg(counterparty_id))
+      and (sqlc.narg(from_time)::timestamptz is null or t."createdAt" >= sqlc.narg(from_time))
+      and (sqlc.narg(to_time)::timestamptz is null or t."createdAt" < sqlc.narg(to_time))
+    union
+    -- outgoing transfers
+    select t.id
+    from transfer t
+      join account a on a.id = t."fromAccountId"
+    where a.username = sqlc.arg(username)
+      and sqlc.arg(direction)::text <> 'in'
+      and (sqlc.narg(account_id)::bigint is null or t."fromAccountId" = sqlc.narg(account_id))
+      and (sqlc.narg(counterparty_id)::bigint is null or t."toAccountId" = sqlc.narg(counterparty_id))
+      and (sqlc.narg(from_time)::timestamptz is null or t."createdAt" >= sqlc.narg(from_time))
+      and (sqlc.narg(to_time)::timestamptz is null or t."createdAt" < sqlc.narg(to_time))
+  )
+  and (sqlc.narg(min_amount)::bigint is null or amount >= sqlc.narg(min_amount))
+  and (sqlc.narg(max_amount)::bigint is null or amount <= sqlc.narg(max_amount))
+  and ("createdAt", id) > (sqlc.arg(after_created_at)::timestamptz, sqlc.arg(after_id)::bigint)
+order by "createdAt", id
+limit sqlc.arg(row_limit);
\ No newline at end of file
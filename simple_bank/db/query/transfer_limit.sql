@@ -0,0 +1,55 @@
+-- name: GetAccountLimit :one
+select *
+from account_limit
+where "accountId" = $1
+limit 1;
+
+-- name: UpsertAccountLimit :one
+insert into account_limit("accountId", "maxTransferAmount", "dailyAmount", "monthlyAmount", "hourlyTransfers")
+values($1, $2, $3, $4, $5)
+on conflict ("accountId") do update
+set "maxTransferAmount" = excluded."maxTransferAmount",
+  "dailyAmount" = excluded."dailyAmount",
+  "monthlyAmount" = excluded."monthlyAmount",
+  "hourlyTransfers" = excluded."hourlyTransfers",
+  "updatedAt" = now()
+RETURNING *;
+
+-- name: GetUserLimit :one
+select *
+from user_limit
+where username = $1 and currency = $2
+limit 1;
+
+-- name: UpsertUserLimit :one
+insert into user_limit(username, currency, "maxTransferAmount", "dailyAmount", "monthlyAmount", "hourlyTransfers")
+values($1, $2, $3, $4, $5, $6)
+on conflict (username, currency) do update
+set "maxTransferAmount" = excluded."maxTransferAmount",
+  "dailyAmount" = excluded."dailyAmount",
+  "monthlyAmount" = excluded."monthlyAmount",
+  "hourlyTransfers" = excluded."hourlyTransfers",
+  "updatedAt" = now()
+RETURNING *;
+
+-- name: GetAccountTransferTotals :one
+-- outgoing totals of the account in the limit windows, reversals are refunds and do not count
+SELECT COALESCE(SUM(amount) FILTER (WHERE "createdAt" >= sqlc.arg(day_start)), 0)::bigint AS "dailyAmount",
+  COALESCE(SUM(amount) FILTER (WHERE "createdAt" >= sqlc.arg(month_start)), 0)::bigint AS "monthlyAmount",
+  COUNT(*) FILTER (WHERE "createdAt" >= sqlc.arg(hour_start)) AS "hourlyTransfers"
+FROM transfer
+WHERE "fromAccountId" = sqlc.arg(account_id)
+  AND "reversedTransferId" IS NULL
+  AND "createdAt" >= LEAST(sqlc.arg(month_start)::timestamptz, sqlc.arg(hour_start)::timestamptz);
+
+-- name: GetUserTransferTotals :one
+-- outgoing totals of all accounts of the user in the currency, like GetAccountTransferTotals
+SELECT COALESCE(SUM(transfer.amount) FILTER (WHERE transfer."createdAt" >= sqlc.arg(day_start)), 0)::bigint AS "dailyAmount",
+  COALESCE(SUM(transfer.amount) FILTER (WHERE transfer."createdAt" >= sqlc.arg(month_start)), 0)::bigint AS "monthlyAmount",
+  COUNT(*) FILTER (WHERE transfer."createdAt" >= sqlc.arg(hour_start)) AS "hourlyTransfers"
+FROM transfer
+JOIN account ON account.id = transfer."fromAccountId"
+WHERE account.username = sqlc.arg(username)
+  AND account.currency = sqlc.arg(currency)
+  AND transfer."reversedTransferId" IS NULL
+  AND transfer."createdAt" >= LEAST(sqlc.arg(month_start)::timestamptz, sqlc.arg(hour_start)::timestamptz);
\ No newline at end of file
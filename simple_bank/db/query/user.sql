@@ -20,7 +20,9 @@ SELECT username,
   email,
   "hashedPassword",
   "passwordChangedAt",
-  "createdAt"
+  "createdAt",
+  role,
+  "isEmailVerified"
 FROM "user"
 WHERE username = $1 LIMIT 1;
 
@@ -33,11 +35,18 @@ SELECT username,
   email,
   "hashedPassword",
   "passwordChangedAt",
-  "createdAt"
+  "createdAt",
+  role,
+  "isEmailVerified"
 FROM "user"
 WHERE email = $1 LIMIT 1;
 
+-- name: GetUserPasswordChangedAt :one
+SELECT "passwordChangedAt" FROM "user"
+WHERE username = $1 LIMIT 1;
+
 -- name: UpdateUser :one
+-- a changed email has to be verified again
 UPDATE "user"
 SET
   name1 = $2,
@@ -46,10 +55,24 @@ SET
   lastname2 = $5,
   email = $6,
   "hashedPassword" = $7,
-  "passwordChangedAt" = $8
+  "passwordChangedAt" = $8,
+  "isEmailVerified" = "isEmailVerified" AND email = $6
 WHERE username = $1
 RETURNING *;
 
+-- name: UpdateUserRole :one
+UPDATE "user"
+SET role = $2
+WHERE username = $1
+RETURNING *;
+
+-- name: VerifyUserEmail :one
+-- only verifies the email the code was sent to, not one the user changed to since
+UPDATE "user"
+SET "isEmailVerified" = true
+WHERE username = $1 AND email = $2
+RETURNING *;
+
 -- name: DeleteUser :exec
 DELETE FROM "user"
 WHERE username = $1;
@@ -63,7 +86,9 @@ SELECT username,
   email,
   "hashedPassword",
   "passwordChangedAt",
-  "createdAt"
+  "createdAt",
+  role,
+  "isEmailVerified"
 FROM "user"
 ORDER BY username
 LIMIT $1 OFFSET $2;
\ No newline at end of file
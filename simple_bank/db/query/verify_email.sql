@@ -0,0 +1,24 @@
+-- name: CreateVerifyEmail :one
+INSERT INTO "verify_email" (
+    username,
+    email,
+    "secretCode"
+  )
+VALUES ($1, $2, $3)
+RETURNING *;
+
+-- name: GetVerifyEmail :one
+SELECT *
+FROM "verify_email"
+WHERE id = $1
+LIMIT 1;
+
+-- name: UseVerifyEmail :one
+-- marks the code as used, there is no row when the code is wrong, used or expired
+UPDATE "verify_email"
+SET "isUsed" = true
+WHERE id = $1
+  AND "secretCode" = $2
+  AND "isUsed" = false
+  AND "expiredAt" > now()
+RETURNING *;
\ No newline at end of file
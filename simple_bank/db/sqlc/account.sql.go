@@ -13,7 +13,7 @@ const addAmountAccount = `-- name: AddAmountAccount :one
 update account
 set balance = balance + $2
 where id = $1
-RETURNING id, username, currency, balance, "createdAt"
+RETURNING id, username, currency, balance, "createdAt", "overdraftLimit", status
 `
 
 type AddAmountAccountParams struct {
@@ -30,6 +30,8 @@ func (q *Queries) AddAmountAccount(ctx context.Context, arg AddAmountAccountPara
 		&i.Currency,
 		&i.Balance,
 		&i.CreatedAt,
+		&i.OverdraftLimit,
+		&i.Status,
 	)
 	return i, err
 }
@@ -37,7 +39,7 @@ func (q *Queries) AddAmountAccount(ctx context.Context, arg AddAmountAccountPara
 const createAccount = `-- name: CreateAccount :one
 insert into account(username, balance, currency)
 values($1, $2, $3)
-RETURNING id, username, currency, balance, "createdAt"
+RETURNING id, username, currency, balance, "createdAt", "overdraftLimit", status
 `
 
 type CreateAccountParams struct {
@@ -55,22 +57,14 @@ func (q *Queries) CreateAccount(ctx context.Context, arg CreateAccountParams) (A
 		&i.Currency,
 		&i.Balance,
 		&i.CreatedAt,
+		&i.OverdraftLimit,
+		&i.Status,
 	)
 	return i, err
 }
 
-const deleteAccount = `-- name: DeleteAccount :exec
-delete from account
-where id = $1
-`
-
-func (q *Queries) DeleteAccount(ctx context.Context, id int64) error {
-	_, err := q.db.ExecContext(ctx, deleteAccount, id)
-	return err
-}
-
 const getAccount = `-- name: GetAccount :one
-select id, username, currency, balance, "createdAt"
+select id, username, currency, balance, "createdAt", "overdraftLimit", status
 from account
 where id = $1
 limit 1
@@ -85,12 +79,14 @@ func (q *Queries) GetAccount(ctx context.Context, id int64) (Account, error) {
 		&i.Currency,
 		&i.Balance,
 		&i.CreatedAt,
+		&i.OverdraftLimit,
+		&i.Status,
 	)
 	return i, err
 }
 
 const getAccountForUpdate = `-- name: GetAccountForUpdate :one
-select id, username, currency, balance, "createdAt"
+select id, username, currency, balance, "createdAt", "overdraftLimit", status
 from account
 where id = $1
 limit 1 
@@ -106,12 +102,14 @@ func (q *Queries) GetAccountForUpdate(ctx context.Context, id int64) (Account, e
 		&i.Currency,
 		&i.Balance,
 		&i.CreatedAt,
+		&i.OverdraftLimit,
+		&i.Status,
 	)
 	return i, err
 }
 
 const getAccounts = `-- name: GetAccounts :many
-select id, username, currency, balance, "createdAt"
+select id, username, currency, balance, "createdAt", "overdraftLimit", status
 from account
 where username = $1
 order by id
@@ -139,6 +137,8 @@ func (q *Queries) GetAccounts(ctx context.Context, arg GetAccountsParams) ([]Acc
 			&i.Currency,
 			&i.Balance,
 			&i.CreatedAt,
+			&i.OverdraftLimit,
+			&i.Status,
 		); err != nil {
 			return nil, err
 		}
@@ -157,7 +157,7 @@ const updateAccount = `-- name: UpdateAccount :one
 update account
 set balance = $2
 where id = $1
-RETURNING id, username, currency, balance, "createdAt"
+RETURNING id, username, currency, balance, "createdAt", "overdraftLimit", status
 `
 
 type UpdateAccountParams struct {
@@ -174,6 +174,62 @@ func (q *Queries) UpdateAccount(ctx context.Context, arg UpdateAccountParams) (A
 		&i.Currency,
 		&i.Balance,
 		&i.CreatedAt,
+		&i.OverdraftLimit,
+		&i.Status,
+	)
+	return i, err
+}
+
+const updateAccountOverdraftLimit = `-- name: UpdateAccountOverdraftLimit :one
+update account
+set "overdraftLimit" = $2
+where id = $1
+RETURNING id, username, currency, balance, "createdAt", "overdraftLimit", status
+`
+
+type UpdateAccountOverdraftLimitParams struct {
+	ID             int64 `json:"id"`
+	OverdraftLimit int64 `json:"overdraftLimit"`
+}
+
+func (q *Queries) UpdateAccountOverdraftLimit(ctx context.Context, arg UpdateAccountOverdraftLimitParams) (Account, error) {
+	row := q.db.QueryRowContext(ctx, updateAccountOverdraftLimit, arg.ID, arg.OverdraftLimit)
+	var i Account
+	err := row.Scan(
+		&i.ID,
+		&i.Username,
+		&i.Currency,
+		&i.Balance,
+		&i.CreatedAt,
+		&i.OverdraftLimit,
+		&i.Status,
+	)
+	return i, err
+}
+
+const updateAccountStatus = `-- name: UpdateAccountStatus :one
+update account
+set status = $2
+where id = $1
+RETURNING id, username, currency, balance, "createdAt", "overdraftLimit", status
+`
+
+type UpdateAccountStatusParams struct {
+	ID     int64  `json:"id"`
+	Status string `json:"status"`
+}
+
+func (q *Queries) UpdateAccountStatus(ctx context.Context, arg UpdateAccountStatusParams) (Account, error) {
+	row := q.db.QueryRowContext(ctx, updateAccountStatus, arg.ID, arg.Status)
+	var i Account
+	err := row.Scan(
+		&i.ID,
+		&i.Username,
+		&i.Currency,
+		&i.Balance,
+		&i.CreatedAt,
+		&i.OverdraftLimit,
+		&i.Status,
 	)
 	return i, err
 }
@@ -0,0 +1,91 @@
+package db
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+)
+
+const (
+	AccountStatusActive = "active"
+	// blocked until it is activated again, e.g. while a lost card or suspicious activity is looked into
+	AccountStatusFrozen = "frozen"
+	// final, the account and its history are kept but it can never be used again
+	AccountStatusClosed = "closed"
+)
+
+// accountStatusTransitions lists the statuses each status can change to
+var accountStatusTransitions = map[string][]string{
+	AccountStatusActive: {AccountStatusFrozen, AccountStatusClosed},
+	AccountStatusFrozen: {AccountStatusActive},
+	AccountStatusClosed: {},
+}
+
+// ErrAccountNotActive is returned when money is moved to or from an account that is not active
+var ErrAccountNotActive = errors.New("account is not active")
+
+// ErrInvalidAccountStatusTransition is returned when the account cannot change from its status to the requested one
+var ErrInvalidAccountStatusTransition = errors.New("invalid account status transition")
+
+// ErrAccountBalanceNotZero is returned when an account that still holds money is closed
+var ErrAccountBalanceNotZero = errors.New("account balance is not zero")
+
+// ErrUnfreezeNotAllowed is returned when a frozen account is activated by someone who may not lift the freeze
+var ErrUnfreezeNotAllowed = errors.New("not allowed to lift the freeze of the account")
+
+// CanChangeAccountStatus reports whether an account can change from one status to the other
+func CanChangeAccountStatus(from string, to string) bool {
+	for _, status := range accountStatusTransitions[from] {
+		if status == to {
+			return true
+		}
+	}
+	return false
+}
+
+// checkAccountsActive fails with ErrAccountNotActive unless all accounts are active, the caller must hold the account locks
+func checkAccountsActive(accounts ...Account) error {
+	for _, account := range accounts {
+		if account.Status != AccountStatusActive {
+			return fmt.Errorf("%w: account [%d] is %s", ErrAccountNotActive, account.ID, account.Status)
+		}
+	}
+	return nil
+}
+
+type UpdateAccountStatusTxParams struct {
+	AccountID int64  `json:"accountId"`
+	Status    string `json:"status"`
+	// whether the caller may activate a frozen account, only the bank can lift a freeze
+	AllowUnfreeze bool `json:"allowUnfreeze"`
+}
+
+// UpdateAccountStatusTx moves the account to a new status within a transaction. The account is locked
+// like in a transfer, so no money moves while the status changes. Closing requires a zero balance and
+// activating a frozen account requires AllowUnfreeze, both are checked against the locked account.
+func (store *SqlStore) UpdateAccountStatusTx(ctx context.Context, arg UpdateAccountStatusTxParams) (Account, error) {
+	var result Account
+	_, err := store.execTx(ctx, sql.LevelSerializable, func(q *Queries) error {
+		account, err := q.GetAccountForUpdate(ctx, arg.AccountID)
+		if err != nil {
+			return err
+		}
+		if !CanChangeAccountStatus(account.Status, arg.Status) {
+			return fmt.Errorf("%w: account [%d] cannot change from %s to %s",
+				ErrInvalidAccountStatusTransition, account.ID, account.Status, arg.Status)
+		}
+		if account.Status == AccountStatusFrozen && arg.Status == AccountStatusActive && !arg.AllowUnfreeze {
+			return fmt.Errorf("%w: account [%d]", ErrUnfreezeNotAllowed, account.ID)
+		}
+		if arg.Status == AccountStatusClosed && account.Balance != 0 {
+			return fmt.Errorf("%w: account [%d] has a balance of %d", ErrAccountBalanceNotZero, account.ID, account.Balance)
+		}
+		result, err = q.UpdateAccountStatus(ctx, UpdateAccountStatusParams{
+			ID:     arg.AccountID,
+			Status: arg.Status,
+		})
+		return err
+	})
+	return result, err
+}
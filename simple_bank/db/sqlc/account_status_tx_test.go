@@ -0,0 +1,100 @@
+package db
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestCanChangeAccountStatus(t *testing.T) {
+	require.True(t, CanChangeAccountStatus(AccountStatusActive, AccountStatusFrozen))
+	require.True(t, CanChangeAccountStatus(AccountStatusFrozen, AccountStatusActive))
+	require.True(t, CanChangeAccountStatus(AccountStatusActive, AccountStatusClosed))
+	// a frozen account is activated before it is closed
+	require.False(t, CanChangeAccountStatus(AccountStatusFrozen, AccountStatusClosed))
+	require.False(t, CanChangeAccountStatus(AccountStatusClosed, AccountStatusActive))
+	require.False(t, CanChangeAccountStatus(AccountStatusActive, AccountStatusActive))
+	require.False(t, CanChangeAccountStatus(AccountStatusActive, "deleted"))
+}
+
+func TestUpdateAccountStatusTx(t *testing.T) {
+	store := NewStore(testDB)
+	account1 := CreateRandomAccount(t)
+	account2 := CreateRandomAccount(t)
+
+	account, err := store.UpdateAccountStatusTx(context.Background(), UpdateAccountStatusTxParams{
+		AccountID: account1.ID,
+		Status:    AccountStatusFrozen,
+	})
+	require.NoError(t, err)
+	require.Equal(t, AccountStatusFrozen, account.Status)
+
+	// a frozen account neither sends nor receives money
+	_, err = store.TransferTx(context.Background(), TransferTxParams{
+		FromAccountID: account1.ID,
+		ToAccountID:   account2.ID,
+		Amount:        1,
+	})
+	require.ErrorIs(t, err, ErrAccountNotActive)
+	_, err = store.TransferTx(context.Background(), TransferTxParams{
+		FromAccountID: account2.ID,
+		ToAccountID:   account1.ID,
+		Amount:        1,
+	})
+	require.ErrorIs(t, err, ErrAccountNotActive)
+	_, err = store.DepositTx(context.Background(), DepositTxParams{AccountID: account1.ID, Amount: 1})
+	require.ErrorIs(t, err, ErrAccountNotActive)
+
+	_, err = store.UpdateAccountStatusTx(context.Background(), UpdateAccountStatusTxParams{
+		AccountID: account1.ID,
+		Status:    AccountStatusClosed,
+	})
+	require.ErrorIs(t, err, ErrInvalidAccountStatusTransition)
+
+	// only the bank lifts a freeze
+	_, err = store.UpdateAccountStatusTx(context.Background(), UpdateAccountStatusTxParams{
+		AccountID: account1.ID,
+		Status:    AccountStatusActive,
+	})
+	require.ErrorIs(t, err, ErrUnfreezeNotAllowed)
+	account, err = store.UpdateAccountStatusTx(context.Background(), UpdateAccountStatusTxParams{
+		AccountID:     account1.ID,
+		Status:        AccountStatusActive,
+		AllowUnfreeze: true,
+	})
+	require.NoError(t, err)
+	require.Equal(t, AccountStatusActive, account.Status)
+}
+
+func TestCloseAccountTx(t *testing.T) {
+	store := NewStore(testDB)
+	account1 := CreateRandomAccount(t)
+	arg := UpdateAccountStatusTxParams{
+		AccountID: account1.ID,
+		Status:    AccountStatusClosed,
+	}
+
+	_, err := store.UpdateAccountStatusTx(context.Background(), arg)
+	require.ErrorIs(t, err, ErrAccountBalanceNotZero)
+
+	_, err = store.WithdrawTx(context.Background(), WithdrawTxParams{AccountID: account1.ID, Amount: account1.Balance})
+	require.NoError(t, err)
+	account, err := store.UpdateAccountStatusTx(context.Background(), arg)
+	require.NoError(t, err)
+	require.Equal(t, AccountStatusClosed, account.Status)
+	require.Zero(t, account.Balance)
+
+	// the account is kept
+	account, err = testQueries.GetAccount(context.Background(), account1.ID)
+	require.NoError(t, err)
+	require.Equal(t, AccountStatusClosed, account.Status)
+
+	// the owner can open a new account in the same currency
+	account2, err := testQueries.CreateAccount(context.Background(), CreateAccountParams{
+		Username: account1.Username,
+		Currency: account1.Currency,
+	})
+	require.NoError(t, err)
+	require.NotEqual(t, account1.ID, account2.ID)
+}
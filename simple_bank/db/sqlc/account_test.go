@@ -2,7 +2,6 @@ package db
 
 import (
 	"context"
-	"database/sql"
 	"simple_bank/util"
 	"testing"
 	"time"
@@ -11,11 +10,22 @@ import (
 )
 
 func CreateRandomAccount(t *testing.T) Account {
+	return CreateRandomAccountWithCurrency(t, util.RandomCurrency())
+}
+
+func CreateRandomAccountWithCurrency(t *testing.T, currency string) Account {
 	user := CreateRandomUser(t)
+	// only users with a verified email send money
+	_, err := testQueries.VerifyUserEmail(context.Background(), VerifyUserEmailParams{
+		Username: user.Username,
+		Email:    user.Email,
+	})
+	require.NoError(t, err)
 	arg := CreateAccountParams{
 		Username: user.Username,
-		Balance:  util.RandomMoney(),
-		Currency: util.RandomCurrency(),
+		// enough for the transfer tests to never run out of funds
+		Balance:  util.RandomInt(100, 9999),
+		Currency: currency,
 	}
 
 	account, err := testQueries.CreateAccount(context.Background(), arg)
@@ -61,14 +71,22 @@ func TestUpdateAccount(t *testing.T) {
 	require.WithinDuration(t, account1.CreatedAt, account2.CreatedAt, time.Second)
 }
 
-func TestDeleleAccount(t *testing.T) {
+func TestUpdateAccountStatus(t *testing.T) {
 	account1 := CreateRandomAccount(t)
-	err := testQueries.DeleteAccount(context.Background(), account1.ID)
+	require.Equal(t, AccountStatusActive, account1.Status)
+	arg := UpdateAccountStatusParams{
+		ID:     account1.ID,
+		Status: AccountStatusFrozen,
+	}
+	account2, err := testQueries.UpdateAccountStatus(context.Background(), arg)
 	require.NoError(t, err)
-	account2, err := testQueries.GetAccount(context.Background(), account1.ID)
+	require.Equal(t, account1.ID, account2.ID)
+	require.Equal(t, account1.Balance, account2.Balance)
+	require.Equal(t, AccountStatusFrozen, account2.Status)
+
+	arg.Status = "deleted"
+	_, err = testQueries.UpdateAccountStatus(context.Background(), arg)
 	require.Error(t, err)
-	require.EqualError(t, err, sql.ErrNoRows.Error())
-	require.Empty(t, account2)
 }
 
 func TestGetAccounts(t *testing.T) {
@@ -91,3 +109,21 @@ func TestGetAccounts(t *testing.T) {
 		require.Equal(t, lastAccount.Username, account.Username)
 	}
 }
+
+func TestUpdateAccountOverdraftLimit(t *testing.T) {
+	account1 := CreateRandomAccount(t)
+	require.Zero(t, account1.OverdraftLimit)
+	arg := UpdateAccountOverdraftLimitParams{
+		ID:             account1.ID,
+		OverdraftLimit: util.RandomMoney(),
+	}
+	account2, err := testQueries.UpdateAccountOverdraftLimit(context.Background(), arg)
+	require.NoError(t, err)
+	require.Equal(t, account1.ID, account2.ID)
+	require.Equal(t, account1.Balance, account2.Balance)
+	require.Equal(t, arg.OverdraftLimit, account2.OverdraftLimit)
+
+	arg.OverdraftLimit = -1
+	_, err = testQueries.UpdateAccountOverdraftLimit(context.Background(), arg)
+	require.Error(t, err)
+}
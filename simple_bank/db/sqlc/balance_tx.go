@@ -0,0 +1,115 @@
+package db
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"simple_bank/util"
+
+	"github.com/lib/pq"
+)
+
+const (
+	EntryReasonTransfer   = "transfer"
+	EntryReasonDeposit    = "deposit"
+	EntryReasonWithdrawal = "withdrawal"
+)
+
+const entryExternalReferenceConstraint = "entry_external_reference_key"
+
+// ErrDuplicateExternalReference is returned when the external reference was already booked on the account
+var ErrDuplicateExternalReference = errors.New("external reference was already booked on this account")
+
+type DepositTxParams struct {
+	AccountID int64 `json:"accountId"`
+	Amount    int64 `json:"amount"`
+	// defaults to EntryReasonDeposit
+	Reason string `json:"reason"`
+	// optional, id of the deposit in the external system, booking it twice on the same account fails
+	ExternalReference string `json:"externalReference"`
+}
+
+type WithdrawTxParams struct {
+	AccountID int64 `json:"accountId"`
+	Amount    int64 `json:"amount"`
+	// defaults to EntryReasonWithdrawal
+	Reason string `json:"reason"`
+	// optional, id of the withdrawal in the external system, booking it twice on the same account fails
+	ExternalReference string `json:"externalReference"`
+}
+
+type BalanceTxResult struct {
+	Account Account `json:"account"`
+	Entry   Entry   `json:"entry"`
+}
+
+// DepositTx credits the account and records the matching entry within a transaction.
+func (store *SqlStore) DepositTx(ctx context.Context, arg DepositTxParams) (BalanceTxResult, error) {
+	reason := arg.Reason
+	if reason == "" {
+		reason = EntryReasonDeposit
+	}
+	return store.balanceTx(ctx, arg.AccountID, arg.Amount, reason, arg.ExternalReference)
+}
+
+// WithdrawTx debits the account and records the matching entry within a transaction.
+// It fails with ErrInsufficientFunds when the balance would go below the overdraft limit.
+func (store *SqlStore) WithdrawTx(ctx context.Context, arg WithdrawTxParams) (BalanceTxResult, error) {
+	reason := arg.Reason
+	if reason == "" {
+		reason = EntryReasonWithdrawal
+	}
+	return store.balanceTx(ctx, arg.AccountID, -arg.Amount, reason, arg.ExternalReference)
+}
+
+// balanceTx adds amount to the account balance, negative amounts are withdrawals
+func (store *SqlStore) balanceTx(ctx context.Context, accountID int64, amount int64, reason string, externalReference string) (BalanceTxResult, error) {
+	var result BalanceTxResult
+	_, err := store.execTx(ctx, sql.LevelSerializable, func(q *Queries) error {
+		result = BalanceTxResult{}
+		account, err := q.GetAccountForUpdate(ctx, accountID)
+		if err != nil {
+			return err
+		}
+		if err := checkAccountsActive(account); err != nil {
+			return err
+		}
+		balance, err := util.NewMoney(account.Balance, account.Currency).Add(util.NewMoney(amount, account.Currency))
+		if err != nil {
+			return err
+		}
+		if balance.Amount < -account.OverdraftLimit {
+			return fmt.Errorf("%w: account [%d] has %d available, %d requested",
+				ErrInsufficientFunds, account.ID, account.Balance+account.OverdraftLimit, -amount)
+		}
+
+		result.Entry, err = q.CreateEntry(ctx, CreateEntryParams{
+			AccountId:         accountID,
+			Amount:            amount,
+			Reason:            reason,
+			ExternalReference: externalReference,
+		})
+		if err != nil {
+			return translateExternalReferenceError(err)
+		}
+		result.Account, err = q.AddAmountAccount(ctx, AddAmountAccountParams{
+			ID:     accountID,
+			Amount: amount,
+		})
+		if err != nil {
+			return translateBalanceError(err)
+		}
+		return nil
+	})
+	return result, err
+}
+
+// translateExternalReferenceError reports a violation of the entry external reference index as ErrDuplicateExternalReference
+func translateExternalReferenceError(err error) error {
+	var pqErr *pq.Error
+	if errors.As(err, &pqErr) && pqErr.Constraint == entryExternalReferenceConstraint {
+		return fmt.Errorf("%w: %v", ErrDuplicateExternalReference, pqErr.Detail)
+	}
+	return err
+}
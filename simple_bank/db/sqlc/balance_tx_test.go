@@ -0,0 +1,63 @@
+package db
+
+import (
+	"context"
+	"simple_bank/util"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestDepositTx(t *testing.T) {
+	store := NewStore(testDB)
+	account := CreateRandomAccount(t)
+	arg := DepositTxParams{
+		AccountID:         account.ID,
+		Amount:            util.RandomMoney() + 1,
+		Reason:            "salary",
+		ExternalReference: util.RandomString(12),
+	}
+
+	result, err := store.DepositTx(context.Background(), arg)
+	require.NoError(t, err)
+	require.Equal(t, account.ID, result.Entry.AccountId)
+	require.Equal(t, arg.Amount, result.Entry.Amount)
+	require.Equal(t, arg.Reason, result.Entry.Reason)
+	require.Equal(t, arg.ExternalReference, result.Entry.ExternalReference)
+	require.Equal(t, account.Balance+arg.Amount, result.Account.Balance)
+
+	// the same external reference cannot be booked twice
+	_, err = store.DepositTx(context.Background(), arg)
+	require.ErrorIs(t, err, ErrDuplicateExternalReference)
+
+	updatedAccount, err := testQueries.GetAccount(context.Background(), account.ID)
+	require.NoError(t, err)
+	require.Equal(t, result.Account.Balance, updatedAccount.Balance)
+}
+
+func TestWithdrawTx(t *testing.T) {
+	store := NewStore(testDB)
+	account := CreateRandomAccount(t)
+	arg := WithdrawTxParams{
+		AccountID: account.ID,
+		Amount:    account.Balance,
+	}
+
+	result, err := store.WithdrawTx(context.Background(), arg)
+	require.NoError(t, err)
+	require.Equal(t, -arg.Amount, result.Entry.Amount)
+	require.Equal(t, EntryReasonWithdrawal, result.Entry.Reason)
+	require.Empty(t, result.Entry.ExternalReference)
+	require.Zero(t, result.Account.Balance)
+
+	_, err = store.WithdrawTx(context.Background(), WithdrawTxParams{
+		AccountID: account.ID,
+		Amount:    1,
+	})
+	require.ErrorIs(t, err, ErrInsufficientFunds)
+
+	// nothing was written
+	updatedAccount, err := testQueries.GetAccount(context.Background(), account.ID)
+	require.NoError(t, err)
+	require.Zero(t, updatedAccount.Balance)
+}
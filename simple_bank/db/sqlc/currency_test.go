@@ -0,0 +1,52 @@
+package db
+
+import (
+	"context"
+	"simple_bank/util"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestGetCurrency(t *testing.T) {
+	currency, err := testQueries.GetCurrency(context.Background(), "JPY")
+	require.NoError(t, err)
+	require.Equal(t, "JPY", currency.Code)
+	require.Zero(t, currency.MinorUnit)
+	require.True(t, currency.Enabled)
+}
+
+func TestListCurrencies(t *testing.T) {
+	currencies, err := testQueries.ListCurrencies(context.Background())
+	require.NoError(t, err)
+	codes := make([]string, len(currencies))
+	for i, currency := range currencies {
+		codes[i] = currency.Code
+	}
+	require.Subset(t, codes, []string{"USD", "EUR", "JPY", "CAD", "AUD"})
+	require.IsIncreasing(t, codes)
+}
+
+func TestUpdateCurrencyEnabled(t *testing.T) {
+	arg := CreateCurrencyParams{
+		Code:      strings.ToUpper(util.RandomString(3)),
+		MinorUnit: 2,
+		Symbol:    util.RandomString(2),
+		Enabled:   true,
+	}
+	currency1, err := testQueries.CreateCurrency(context.Background(), arg)
+	require.NoError(t, err)
+	require.Equal(t, arg.Code, currency1.Code)
+	require.Equal(t, arg.MinorUnit, currency1.MinorUnit)
+	require.Equal(t, arg.Symbol, currency1.Symbol)
+	require.True(t, currency1.Enabled)
+
+	currency2, err := testQueries.UpdateCurrencyEnabled(context.Background(), UpdateCurrencyEnabledParams{
+		Code:    currency1.Code,
+		Enabled: false,
+	})
+	require.NoError(t, err)
+	require.Equal(t, currency1.Code, currency2.Code)
+	require.False(t, currency2.Enabled)
+}
@@ -7,43 +7,47 @@ package db
 
 import (
 	"context"
+	"database/sql"
+	"time"
 )
 
 const createEntry = `-- name: CreateEntry :one
-insert into entry("accountId", amount)
-values($1, $2)
-RETURNING id, "accountId", amount, "createdAt"
+insert into entry("accountId", amount, reason, "externalReference", "transferId")
+values($1, $2, $3, $4, $5)
+RETURNING id, "accountId", amount, "createdAt", reason, "externalReference", "transferId"
 `
 
 type CreateEntryParams struct {
-	AccountId int64 `json:"accountId"`
-	Amount    int64 `json:"amount"`
+	AccountId         int64         `json:"accountId"`
+	Amount            int64         `json:"amount"`
+	Reason            string        `json:"reason"`
+	ExternalReference string        `json:"externalReference"`
+	TransferId        sql.NullInt64 `json:"transferId"`
 }
 
 func (q *Queries) CreateEntry(ctx context.Context, arg CreateEntryParams) (Entry, error) {
-	row := q.db.QueryRowContext(ctx, createEntry, arg.AccountId, arg.Amount)
+	row := q.db.QueryRowContext(ctx, createEntry,
+		arg.AccountId,
+		arg.Amount,
+		arg.Reason,
+		arg.ExternalReference,
+		arg.TransferId,
+	)
 	var i Entry
 	err := row.Scan(
 		&i.ID,
 		&i.AccountId,
 		&i.Amount,
 		&i.CreatedAt,
+		&i.Reason,
+		&i.ExternalReference,
+		&i.TransferId,
 	)
 	return i, err
 }
 
-const deleteEntry = `-- name: DeleteEntry :exec
-delete from entry
-where id = $1
-`
-
-func (q *Queries) DeleteEntry(ctx context.Context, id int64) error {
-	_, err := q.db.ExecContext(ctx, deleteEntry, id)
-	return err
-}
-
 const getEntries = `-- name: GetEntries :many
-select id, "accountId", amount, "createdAt"
+select id, "accountId", amount, "createdAt", reason, "externalReference", "transferId"
 from entry
 order by id
 limit $1 offset $2
@@ -68,6 +72,9 @@ func (q *Queries) GetEntries(ctx context.Context, arg GetEntriesParams) ([]Entry
 			&i.AccountId,
 			&i.Amount,
 			&i.CreatedAt,
+			&i.Reason,
+			&i.ExternalReference,
+			&i.TransferId,
 		); err != nil {
 			return nil, err
 		}
@@ -83,7 +90,7 @@ func (q *Queries) GetEntries(ctx context.Context, arg GetEntriesParams) ([]Entry
 }
 
 const getEntry = `-- name: GetEntry :one
-select id, "accountId", amount, "createdAt"
+select id, "accountId", amount, "createdAt", reason, "externalReference", "transferId"
 from entry
 where id = $1
 limit 1
@@ -97,30 +104,87 @@ func (q *Queries) GetEntry(ctx context.Context, id int64) (Entry, error) {
 		&i.AccountId,
 		&i.Amount,
 		&i.CreatedAt,
+		&i.Reason,
+		&i.ExternalReference,
+		&i.TransferId,
 	)
 	return i, err
 }
 
-const updateEntry = `-- name: UpdateEntry :one
-update entry
-set amount = $2
-where id = $1
-RETURNING id, "accountId", amount, "createdAt"
+const listAccountEntries = `-- name: ListAccountEntries :many
+WITH ledger AS (
+  SELECT id,
+    "accountId",
+    amount,
+    "createdAt",
+    SUM(amount) OVER (ORDER BY "createdAt", id) AS cumulative,
+    SUM(amount) OVER () AS total
+  FROM entry
+  WHERE "accountId" = $1
+)
+SELECT ledger.id,
+  ledger."accountId",
+  ledger.amount,
+  ledger."createdAt",
+  (account.balance - ledger.total + ledger.cumulative)::bigint AS "runningBalance"
+FROM ledger
+JOIN account ON account.id = ledger."accountId"
+WHERE ($2::timestamptz IS NULL OR ledger."createdAt" >= $2)
+  AND ($3::timestamptz IS NULL OR ledger."createdAt" < $3)
+  AND (ledger."createdAt", ledger.id) > ($4::timestamptz, $5::bigint)
+ORDER BY ledger."createdAt", ledger.id
+LIMIT $6
 `
 
-type UpdateEntryParams struct {
-	ID     int64 `json:"id"`
-	Amount int64 `json:"amount"`
+type ListAccountEntriesParams struct {
+	AccountID      int64        `json:"account_id"`
+	FromTime       sql.NullTime `json:"from_time"`
+	ToTime         sql.NullTime `json:"to_time"`
+	AfterCreatedAt time.Time    `json:"after_created_at"`
+	AfterID        int64        `json:"after_id"`
+	RowLimit       int32        `json:"row_limit"`
 }
 
-func (q *Queries) UpdateEntry(ctx context.Context, arg UpdateEntryParams) (Entry, error) {
-	row := q.db.QueryRowContext(ctx, updateEntry, arg.ID, arg.Amount)
-	var i Entry
-	err := row.Scan(
-		&i.ID,
-		&i.AccountId,
-		&i.Amount,
-		&i.CreatedAt,
+type ListAccountEntriesRow struct {
+	ID             int64     `json:"id"`
+	AccountId      int64     `json:"accountId"`
+	Amount         int64     `json:"amount"`
+	CreatedAt      time.Time `json:"createdAt"`
+	RunningBalance int64     `json:"runningBalance"`
+}
+
+func (q *Queries) ListAccountEntries(ctx context.Context, arg ListAccountEntriesParams) ([]ListAccountEntriesRow, error) {
+	rows, err := q.db.QueryContext(ctx, listAccountEntries,
+		arg.AccountID,
+		arg.FromTime,
+		arg.ToTime,
+		arg.AfterCreatedAt,
+		arg.AfterID,
+		arg.RowLimit,
 	)
-	return i, err
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []ListAccountEntriesRow{}
+	for rows.Next() {
+		var i ListAccountEntriesRow
+		if err := rows.Scan(
+			&i.ID,
+			&i.AccountId,
+			&i.Amount,
+			&i.CreatedAt,
+			&i.RunningBalance,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
 }
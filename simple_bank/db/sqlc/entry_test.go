@@ -2,7 +2,6 @@ package db
 
 import (
 	"context"
-	"database/sql"
 	"simple_bank/util"
 	"testing"
 	"time"
@@ -15,12 +14,14 @@ func CreateRandomEntry(t *testing.T) Entry {
 	arg := CreateEntryParams{
 		AccountId:   account.ID,
 		Amount:  util.RandomMoney(), 
+		Reason:  util.RandomString(6),
 	}
 	entry, err := testQueries.CreateEntry(context.Background(), arg)
 	require.NoError(t, err)
 	require.NotEmpty(t, entry)
 	require.Equal(t, arg.AccountId, entry.AccountId)
 	require.Equal(t, arg.Amount, entry.Amount) 
+	require.Equal(t, arg.Reason, entry.Reason)
 	require.NotZero(t, entry.ID)
 	require.NotZero(t, entry.CreatedAt)
 	return entry;
@@ -43,29 +44,16 @@ func TestGetEntry(t *testing.T) {
 	require.WithinDuration(t, entry1.CreatedAt, entry2.CreatedAt, time.Second)
 }
 
-func TestUpdateAEntry(t *testing.T) {
-	account1 := CreateRandomEntry(t)
-	arg := UpdateEntryParams{
-		ID:      account1.ID,
-		Amount: util.RandomMoney(),
-	}
-	account2, err := testQueries.UpdateEntry(context.Background(), arg)
-	require.NoError(t, err)
-	require.NotEmpty(t, account2)
-	require.Equal(t, account1.ID, account2.ID)
-	require.Equal(t, account1.AccountId, account2.AccountId)
-	require.Equal(t, arg.Amount, account2.Amount) 
-	require.WithinDuration(t, account1.CreatedAt, account2.CreatedAt, time.Second)
-}
-
-func TestDeleleEntry(t *testing.T) {
+func TestEntryIsAppendOnly(t *testing.T) {
 	entry1 := CreateRandomEntry(t)
-	err := testQueries.DeleteEntry(context.Background(), entry1.ID)
-	require.NoError(t, err)
-	entry2, err := testQueries.GetEntry(context.Background(), entry1.ID)
+	_, err := testDB.ExecContext(context.Background(), `update entry set amount = amount + 1 where id = $1`, entry1.ID)
 	require.Error(t, err)
-	require.EqualError(t, err, sql.ErrNoRows.Error())
-	require.Empty(t, entry2)
+	_, err = testDB.ExecContext(context.Background(), `delete from entry where id = $1`, entry1.ID)
+	require.Error(t, err)
+
+	entry2, err := testQueries.GetEntry(context.Background(), entry1.ID)
+	require.NoError(t, err)
+	require.Equal(t, entry1.Amount, entry2.Amount)
 }
 
 func TestGetEntries(t *testing.T) {
@@ -85,3 +73,53 @@ func TestGetEntries(t *testing.T) {
 		require.NotEmpty(t, entry)
 	}
 }
+
+func TestListAccountEntries(t *testing.T) {
+	account := CreateRandomAccount(t)
+	openingBalance := account.Balance
+	n := 6
+	for i := 0; i < n; i++ {
+		entry, err := testQueries.CreateEntry(context.Background(), CreateEntryParams{
+			AccountId: account.ID,
+			Amount:    util.RandomMoney(),
+		})
+		require.NoError(t, err)
+		account, err = testQueries.AddAmountAccount(context.Background(), AddAmountAccountParams{
+			ID:     account.ID,
+			Amount: entry.Amount,
+		})
+		require.NoError(t, err)
+	}
+
+	arg := ListAccountEntriesParams{
+		AccountID: account.ID,
+		RowLimit:  int32(n / 2),
+	}
+	firstPage, err := testQueries.ListAccountEntries(context.Background(), arg)
+	require.NoError(t, err)
+	require.Len(t, firstPage, n/2)
+
+	last := firstPage[len(firstPage)-1]
+	arg.AfterCreatedAt = last.CreatedAt
+	arg.AfterID = last.ID
+	secondPage, err := testQueries.ListAccountEntries(context.Background(), arg)
+	require.NoError(t, err)
+	require.Len(t, secondPage, n/2)
+
+	runningBalance := openingBalance
+	for _, entry := range append(firstPage, secondPage...) {
+		require.Equal(t, account.ID, entry.AccountId)
+		runningBalance += entry.Amount
+		require.Equal(t, runningBalance, entry.RunningBalance)
+	}
+	require.Equal(t, account.Balance, runningBalance)
+
+	arg = ListAccountEntriesParams{
+		AccountID: account.ID,
+		ToTime:    util.TimeToSqlNullTime(time.Now().Add(-time.Hour)),
+		RowLimit:  int32(n),
+	}
+	entries, err := testQueries.ListAccountEntries(context.Background(), arg)
+	require.NoError(t, err)
+	require.Empty(t, entries)
+}
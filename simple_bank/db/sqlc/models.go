@@ -6,6 +6,7 @@ package db
 
 import (
 	"database/sql"
+	"encoding/json"
 	"time"
 
 	"github.com/google/uuid"
@@ -17,6 +18,33 @@ type Account struct {
 	Currency  string    `json:"currency"`
 	Balance   int64     `json:"balance"`
 	CreatedAt time.Time `json:"createdAt"`
+	// how far below zero the balance may go
+	OverdraftLimit int64 `json:"overdraftLimit"`
+	// active, frozen or closed, only active accounts can move money
+	Status string `json:"status"`
+}
+
+type AccountLimit struct {
+	AccountId int64 `json:"accountId"`
+	// in the account currency, null means no limit
+	MaxTransferAmount sql.NullInt64 `json:"maxTransferAmount"`
+	// outgoing total since midnight UTC
+	DailyAmount sql.NullInt64 `json:"dailyAmount"`
+	// outgoing total since the first of the month UTC
+	MonthlyAmount sql.NullInt64 `json:"monthlyAmount"`
+	// outgoing transfers in the last hour
+	HourlyTransfers sql.NullInt32 `json:"hourlyTransfers"`
+	UpdatedAt       time.Time     `json:"updatedAt"`
+}
+
+type Currency struct {
+	// ISO 4217 alphabetic code
+	Code string `json:"code"`
+	// digits after the decimal point, amounts are stored in minor units
+	MinorUnit int16     `json:"minorUnit"`
+	Symbol    string    `json:"symbol"`
+	Enabled   bool      `json:"enabled"`
+	CreatedAt time.Time `json:"createdAt"`
 }
 
 type Entry struct {
@@ -25,6 +53,53 @@ type Entry struct {
 	// can be positive or negative
 	Amount    int64     `json:"amount"`
 	CreatedAt time.Time `json:"createdAt"`
+	// why the balance changed, transfer for transfer entries
+	Reason string `json:"reason"`
+	// id of the deposit or withdrawal in the external system, empty for transfers
+	ExternalReference string `json:"externalReference"`
+	// transfer that booked the entry, null for deposits and withdrawals
+	TransferId sql.NullInt64 `json:"transferId"`
+}
+
+type IdempotencyKey struct {
+	Username    string          `json:"username"`
+	Key         string          `json:"key"`
+	RequestHash string          `json:"request_hash"`
+	Response    json.RawMessage `json:"response"`
+	CreatedAt   time.Time       `json:"createdAt"`
+}
+
+type ScheduledTransfer struct {
+	ID            int64  `json:"id"`
+	Username      string `json:"username"`
+	FromAccountId int64  `json:"fromAccountId"`
+	ToAccountId   int64  `json:"toAccountId"`
+	// in the source account currency
+	Amount int64 `json:"amount"`
+	// cron expression, empty for a one-off transfer
+	Schedule  string    `json:"schedule"`
+	NextRunAt time.Time `json:"nextRunAt"`
+	// active, paused, completed, failed or cancelled
+	Status string `json:"status"`
+	// failed attempts of the current run
+	Attempts int32 `json:"attempts"`
+	// a scheduler claimed the transfer and is running it until then
+	LockedUntil time.Time `json:"lockedUntil"`
+	CreatedAt   time.Time `json:"createdAt"`
+}
+
+type ScheduledTransferRun struct {
+	ID                  int64 `json:"id"`
+	ScheduledTransferId int64 `json:"scheduledTransferId"`
+	// when the attempt was due
+	ScheduledFor time.Time `json:"scheduledFor"`
+	Attempt      int32     `json:"attempt"`
+	// succeeded or failed
+	Status string `json:"status"`
+	// booked transfer, null when the attempt failed
+	TransferId sql.NullInt64 `json:"transferId"`
+	Error      string        `json:"error"`
+	CreatedAt  time.Time     `json:"createdAt"`
 }
 
 type Session struct {
@@ -40,13 +115,41 @@ type Session struct {
 	CreatedAt        time.Time      `json:"createdAt"`
 }
 
+type Task struct {
+	ID int64 `json:"id"`
+	// selects the handler of the task, e.g. task:send_verify_email
+	Type    string          `json:"type"`
+	Payload json.RawMessage `json:"payload"`
+	// pending, done or dead, a dead task failed maxAttempts times and is not retried
+	Status string `json:"status"`
+	// failed attempts so far
+	Attempts    int32 `json:"attempts"`
+	MaxAttempts int32 `json:"maxAttempts"`
+	// the task is not run before, pushed back after every failed attempt
+	RunAt time.Time `json:"runAt"`
+	// a processor claimed the task and is running it until then
+	LockedUntil time.Time `json:"lockedUntil"`
+	// error of the last failed attempt
+	LastError  string       `json:"lastError"`
+	CreatedAt  time.Time    `json:"createdAt"`
+	FinishedAt sql.NullTime `json:"finishedAt"`
+}
+
 type Transfer struct {
 	ID            int64 `json:"id"`
 	FromAccountId int64 `json:"fromAccountId"`
 	ToAccountId   int64 `json:"toAccountId"`
-	// must be positive
+	// must be positive, in the source account currency
 	Amount    int64     `json:"amount"`
 	CreatedAt time.Time `json:"createdAt"`
+	// must be positive, in the destination account currency
+	ToAmount int64 `json:"toAmount"`
+	// destination units per source unit, 1 for same currency transfers
+	ExchangeRate string `json:"exchangeRate"`
+	// when the applied rate was published, null for same currency transfers
+	RateTimestamp sql.NullTime `json:"rateTimestamp"`
+	// transfer this one reverses, null for regular transfers
+	ReversedTransferId sql.NullInt64 `json:"reversedTransferId"`
 }
 
 type User struct {
@@ -59,4 +162,31 @@ type User struct {
 	HashedPassword    string         `json:"hashedPassword"`
 	PasswordChangedAt time.Time      `json:"passwordChangedAt"`
 	CreatedAt         time.Time      `json:"createdAt"`
+	// depositor, banker or admin
+	Role string `json:"role"`
+	// accounts can only be created and transfers made once the email is verified
+	IsEmailVerified bool `json:"isEmailVerified"`
+}
+
+type UserLimit struct {
+	Username string `json:"username"`
+	Currency string `json:"currency"`
+	// applies to all accounts of the user in the currency, null means no limit
+	MaxTransferAmount sql.NullInt64 `json:"maxTransferAmount"`
+	DailyAmount       sql.NullInt64 `json:"dailyAmount"`
+	MonthlyAmount     sql.NullInt64 `json:"monthlyAmount"`
+	HourlyTransfers   sql.NullInt32 `json:"hourlyTransfers"`
+	UpdatedAt         time.Time     `json:"updatedAt"`
+}
+
+type VerifyEmail struct {
+	ID       int64  `json:"id"`
+	Username string `json:"username"`
+	// address the code was sent to, the code does not verify another email of the user
+	Email      string `json:"email"`
+	SecretCode string `json:"secretCode"`
+	// a code verifies the email only once
+	IsUsed    bool      `json:"isUsed"`
+	CreatedAt time.Time `json:"createdAt"`
+	ExpiredAt time.Time `json:"expiredAt"`
 }
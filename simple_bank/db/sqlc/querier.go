@@ -6,38 +6,81 @@ package db
 
 import (
 	"context"
+	"database/sql"
+	"time"
 
 	"github.com/google/uuid"
 )
 
 type Querier interface {
 	AddAmountAccount(ctx context.Context, arg AddAmountAccountParams) (Account, error)
+	// blocks the sessions of the user except the one the access token belongs to
+	BlockOtherUserSessions(ctx context.Context, arg BlockOtherUserSessionsParams) error
+	// locks the due transfers for the caller until locked_until, rows locked by another scheduler are skipped
+	ClaimDueScheduledTransfers(ctx context.Context, arg ClaimDueScheduledTransfersParams) ([]ScheduledTransfer, error)
+	// locks the due tasks for the caller until locked_until, rows locked by another processor are skipped
+	ClaimDueTasks(ctx context.Context, arg ClaimDueTasksParams) ([]Task, error)
 	CreateAccount(ctx context.Context, arg CreateAccountParams) (Account, error)
+	CreateCurrency(ctx context.Context, arg CreateCurrencyParams) (Currency, error)
 	CreateEntry(ctx context.Context, arg CreateEntryParams) (Entry, error)
+	CreateIdempotencyKey(ctx context.Context, arg CreateIdempotencyKeyParams) (IdempotencyKey, error)
+	CreateScheduledTransfer(ctx context.Context, arg CreateScheduledTransferParams) (ScheduledTransfer, error)
+	CreateScheduledTransferRun(ctx context.Context, arg CreateScheduledTransferRunParams) (ScheduledTransferRun, error)
 	CreateSession(ctx context.Context, arg CreateSessionParams) (Session, error)
+	CreateTask(ctx context.Context, arg CreateTaskParams) (Task, error)
 	CreateTransfer(ctx context.Context, arg CreateTransferParams) (Transfer, error)
 	CreateUser(ctx context.Context, arg CreateUserParams) (User, error)
-	DeleteAccount(ctx context.Context, id int64) error
-	DeleteEntry(ctx context.Context, id int64) error
+	CreateVerifyEmail(ctx context.Context, arg CreateVerifyEmailParams) (VerifyEmail, error)
 	DeleteSession(ctx context.Context, id uuid.UUID) error
-	DeleteTransfer(ctx context.Context, id int64) error
 	DeleteUser(ctx context.Context, username string) error
+	FinishScheduledTransferRun(ctx context.Context, arg FinishScheduledTransferRunParams) (ScheduledTransfer, error)
+	FinishTask(ctx context.Context, arg FinishTaskParams) (Task, error)
 	GetAccount(ctx context.Context, id int64) (Account, error)
 	GetAccountForUpdate(ctx context.Context, id int64) (Account, error)
+	GetAccountLimit(ctx context.Context, accountId int64) (AccountLimit, error)
+	// outgoing totals of the account in the limit windows, reversals are refunds and do not count
+	GetAccountTransferTotals(ctx context.Context, arg GetAccountTransferTotalsParams) (GetAccountTransferTotalsRow, error)
 	GetAccounts(ctx context.Context, arg GetAccountsParams) ([]Account, error)
+	GetCurrency(ctx context.Context, code string) (Currency, error)
 	GetEntries(ctx context.Context, arg GetEntriesParams) ([]Entry, error)
 	GetEntry(ctx context.Context, id int64) (Entry, error)
+	GetIdempotencyKey(ctx context.Context, arg GetIdempotencyKeyParams) (IdempotencyKey, error)
+	GetScheduledTransfer(ctx context.Context, id int64) (ScheduledTransfer, error)
 	GetSession(ctx context.Context, id uuid.UUID) (Session, error)
+	GetTask(ctx context.Context, id int64) (Task, error)
 	GetTransfer(ctx context.Context, id int64) (Transfer, error)
+	GetTransferReversedAmounts(ctx context.Context, reversedTransferId sql.NullInt64) (GetTransferReversedAmountsRow, error)
 	GetTransfers(ctx context.Context, arg GetTransfersParams) ([]Transfer, error)
 	GetUser(ctx context.Context, username string) (User, error)
 	GetUserByEmail(ctx context.Context, email string) (User, error)
+	GetUserLimit(ctx context.Context, arg GetUserLimitParams) (UserLimit, error)
+	GetUserPasswordChangedAt(ctx context.Context, username string) (time.Time, error)
+	// outgoing totals of all accounts of the user in the currency, like GetAccountTransferTotals
+	GetUserTransferTotals(ctx context.Context, arg GetUserTransferTotalsParams) (GetUserTransferTotalsRow, error)
 	GetUsers(ctx context.Context, arg GetUsersParams) ([]User, error)
+	GetVerifyEmail(ctx context.Context, id int64) (VerifyEmail, error)
+	ListAccountEntries(ctx context.Context, arg ListAccountEntriesParams) ([]ListAccountEntriesRow, error)
+	ListBalanceMismatches(ctx context.Context) ([]ListBalanceMismatchesRow, error)
+	ListCurrencies(ctx context.Context) ([]Currency, error)
+	ListScheduledTransferRuns(ctx context.Context, arg ListScheduledTransferRunsParams) ([]ScheduledTransferRun, error)
+	ListScheduledTransfers(ctx context.Context, arg ListScheduledTransfersParams) ([]ScheduledTransfer, error)
+	ListUnbalancedTransfers(ctx context.Context) ([]ListUnbalancedTransfersRow, error)
+	ListUserTransfers(ctx context.Context, arg ListUserTransfersParams) ([]Transfer, error)
 	UpdateAccount(ctx context.Context, arg UpdateAccountParams) (Account, error)
-	UpdateEntry(ctx context.Context, arg UpdateEntryParams) (Entry, error)
+	UpdateAccountOverdraftLimit(ctx context.Context, arg UpdateAccountOverdraftLimitParams) (Account, error)
+	UpdateAccountStatus(ctx context.Context, arg UpdateAccountStatusParams) (Account, error)
+	UpdateCurrencyEnabled(ctx context.Context, arg UpdateCurrencyEnabledParams) (Currency, error)
+	UpdateScheduledTransfer(ctx context.Context, arg UpdateScheduledTransferParams) (ScheduledTransfer, error)
 	UpdateSessionAccess(ctx context.Context, arg UpdateSessionAccessParams) (Session, error)
-	UpdateTransfer(ctx context.Context, arg UpdateTransferParams) (Transfer, error)
+	// a changed email has to be verified again
 	UpdateUser(ctx context.Context, arg UpdateUserParams) (User, error)
+	UpdateUserRole(ctx context.Context, arg UpdateUserRoleParams) (User, error)
+	UpsertAccountLimit(ctx context.Context, arg UpsertAccountLimitParams) (AccountLimit, error)
+	UpsertUserLimit(ctx context.Context, arg UpsertUserLimitParams) (UserLimit, error)
+	// marks the code as used, there is no row when the code is wrong, used or expired
+	UseVerifyEmail(ctx context.Context, arg UseVerifyEmailParams) (VerifyEmail, error)
+	// only verifies the email the code was sent to, not one the user changed to since
+	VerifyUserEmail(ctx context.Context, arg VerifyUserEmailParams) (User, error)
 }
 
 var _ Querier = (*Queries)(nil)
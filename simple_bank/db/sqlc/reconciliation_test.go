@@ -0,0 +1,56 @@
+package db
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestListBalanceMismatches(t *testing.T) {
+	account := CreateRandomAccount(t)
+	// the opening balance of random accounts is not backed by entries
+	mismatches, err := testQueries.ListBalanceMismatches(context.Background())
+	require.NoError(t, err)
+	require.Contains(t, mismatches, ListBalanceMismatchesRow{
+		ID:       account.ID,
+		Currency: account.Currency,
+		Balance:  account.Balance,
+	})
+
+	_, err = testQueries.CreateEntry(context.Background(), CreateEntryParams{
+		AccountId: account.ID,
+		Amount:    account.Balance,
+		Reason:    EntryReasonDeposit,
+	})
+	require.NoError(t, err)
+	mismatches, err = testQueries.ListBalanceMismatches(context.Background())
+	require.NoError(t, err)
+	for _, mismatch := range mismatches {
+		require.NotEqual(t, account.ID, mismatch.ID)
+	}
+}
+
+func TestListUnbalancedTransfers(t *testing.T) {
+	store := NewStore(testDB)
+	account1 := CreateRandomAccount(t)
+	account2 := CreateRandomAccountWithCurrency(t, account1.Currency)
+	result, err := store.TransferTx(context.Background(), TransferTxParams{
+		FromAccountID: account1.ID,
+		ToAccountID:   account2.ID,
+		Amount:        10,
+	})
+	require.NoError(t, err)
+
+	// a transfer without entries
+	transfer := CreateRandomTransfer(t)
+
+	unbalanced, err := testQueries.ListUnbalancedTransfers(context.Background())
+	require.NoError(t, err)
+	ids := make([]int64, len(unbalanced))
+	for i, row := range unbalanced {
+		ids[i] = row.ID
+	}
+	require.NotContains(t, ids, result.Transfer.ID)
+	require.Contains(t, ids, transfer.ID)
+}
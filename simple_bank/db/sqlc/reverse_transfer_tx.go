@@ -0,0 +1,117 @@
+package db
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"math/big"
+	"simple_bank/exchange"
+)
+
+const EntryReasonReversal = "reversal"
+
+// ErrTransferAlreadyReversed is returned when the whole amount of the transfer was already given back
+var ErrTransferAlreadyReversed = errors.New("transfer was already reversed")
+
+// ErrTransferNotReversible is returned when reversing a transfer that is itself a reversal
+var ErrTransferNotReversible = errors.New("a reversal cannot be reversed")
+
+// ErrReversalExceedsTransfer is returned when a partial refund is larger than what is left to refund
+var ErrReversalExceedsTransfer = errors.New("reversal exceeds the amount left to refund")
+
+type ReverseTransferTxParams struct {
+	TransferID int64 `json:"transferId"`
+	// optional, part of the original amount to refund in the source account currency, 0 refunds what is left
+	Amount int64 `json:"amount"`
+}
+
+// ReverseTransferTx corrects a transfer without touching it: it books the opposite transfer, linked to
+// the original by reversedTransferId, with compensating entries on both accounts. The money goes back at
+// the rate of the original transfer. A transfer can be refunded in several parts, as long as the refunds
+// do not add up to more than the original amount.
+func (store *SqlStore) ReverseTransferTx(ctx context.Context, arg ReverseTransferTxParams) (TransferTxResult, error) {
+	var result TransferTxResult
+	retries, err := store.execTx(ctx, sql.LevelSerializable, func(q *Queries) error {
+		result = TransferTxResult{}
+		original, err := q.GetTransfer(ctx, arg.TransferID)
+		if err != nil {
+			return err
+		}
+		if original.ReversedTransferId.Valid {
+			return fmt.Errorf("%w: transfer [%d] reverses transfer [%d]", ErrTransferNotReversible, original.ID, original.ReversedTransferId.Int64)
+		}
+		reversed, err := q.GetTransferReversedAmounts(ctx, sql.NullInt64{Int64: original.ID, Valid: true})
+		if err != nil {
+			return err
+		}
+		amount, toAmount, err := reversalAmounts(original, reversed, arg.Amount)
+		if err != nil {
+			return err
+		}
+
+		// the original recipient pays the money back
+		fromAccount, toAccount, err := getAccountsForUpdate(ctx, q, original.ToAccountId, original.FromAccountId)
+		if err != nil {
+			return err
+		}
+		if err := checkAccountsActive(fromAccount, toAccount); err != nil {
+			return err
+		}
+		if fromAccount.Balance-amount < -fromAccount.OverdraftLimit {
+			return fmt.Errorf("%w: account [%d] has %d available, %d requested",
+				ErrInsufficientFunds, fromAccount.ID, fromAccount.Balance+fromAccount.OverdraftLimit, amount)
+		}
+		exchangeRate := "1"
+		if fromAccount.Currency != toAccount.Currency {
+			exchangeRate, err = exchange.InverseRate(original.ExchangeRate)
+			if err != nil {
+				return err
+			}
+		}
+
+		result, err = bookTransfer(ctx, q, CreateTransferParams{
+			FromAccountId:      original.ToAccountId,
+			ToAccountId:        original.FromAccountId,
+			Amount:             amount,
+			ToAmount:           toAmount,
+			ExchangeRate:       exchangeRate,
+			RateTimestamp:      original.RateTimestamp,
+			ReversedTransferId: sql.NullInt64{Int64: original.ID, Valid: true},
+		}, EntryReasonReversal)
+		return err
+	})
+	result.Retries = retries
+	return result, err
+}
+
+// reversalAmounts works out what the original recipient pays back (amount, in the destination currency of the
+// original transfer) and what the original sender gets back (toAmount, in its source currency). A partial refund
+// takes its share of the original toAmount, the refund that completes the reversal takes whatever is left
+// so rounding never leaves a remainder behind.
+func reversalAmounts(original Transfer, reversed GetTransferReversedAmountsRow, refund int64) (amount int64, toAmount int64, err error) {
+	remaining := original.Amount - reversed.ReversedAmount
+	remainingTo := original.ToAmount - reversed.ReversedToAmount
+	if remaining <= 0 {
+		return 0, 0, fmt.Errorf("%w: transfer [%d]", ErrTransferAlreadyReversed, original.ID)
+	}
+	if refund <= 0 || refund == remaining {
+		return remainingTo, remaining, nil
+	}
+	if refund > remaining {
+		return 0, 0, fmt.Errorf("%w: %d requested, %d left on transfer [%d]", ErrReversalExceedsTransfer, refund, remaining, original.ID)
+	}
+	// refund * toAmount / amount, rounded half up
+	share := new(big.Int).Mul(big.NewInt(refund), big.NewInt(original.ToAmount))
+	share.Mul(share, big.NewInt(2))
+	share.Add(share, big.NewInt(original.Amount))
+	share.Quo(share, big.NewInt(2*original.Amount))
+	amount = share.Int64()
+	if amount > remainingTo {
+		amount = remainingTo
+	}
+	if amount <= 0 {
+		return 0, 0, fmt.Errorf("%w: refund of %d", exchange.ErrAmountTooSmall, refund)
+	}
+	return amount, refund, nil
+}
@@ -0,0 +1,128 @@
+package db
+
+import (
+	"context"
+	"simple_bank/exchange"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestReverseTransferTx(t *testing.T) {
+	store := NewStore(testDB)
+	account1 := CreateRandomAccount(t)
+	account2 := CreateRandomAccountWithCurrency(t, account1.Currency)
+
+	original, err := store.TransferTx(context.Background(), TransferTxParams{
+		FromAccountID: account1.ID,
+		ToAccountID:   account2.ID,
+		Amount:        10,
+	})
+	require.NoError(t, err)
+
+	result, err := store.ReverseTransferTx(context.Background(), ReverseTransferTxParams{TransferID: original.Transfer.ID})
+	require.NoError(t, err)
+	reversal := result.Transfer
+	require.Equal(t, original.Transfer.ID, reversal.ReversedTransferId.Int64)
+	require.Equal(t, account2.ID, reversal.FromAccountId)
+	require.Equal(t, account1.ID, reversal.ToAccountId)
+	require.Equal(t, original.Transfer.ToAmount, reversal.Amount)
+	require.Equal(t, original.Transfer.Amount, reversal.ToAmount)
+
+	require.Equal(t, -reversal.Amount, result.FromEntry.Amount)
+	require.Equal(t, reversal.ToAmount, result.ToEntry.Amount)
+	require.Equal(t, EntryReasonReversal, result.FromEntry.Reason)
+	require.Equal(t, reversal.ID, result.FromEntry.TransferId.Int64)
+	require.Equal(t, reversal.ID, result.ToEntry.TransferId.Int64)
+
+	// both accounts are back to their opening balance
+	require.Equal(t, account1.Balance, result.ToAccount.Balance)
+	require.Equal(t, account2.Balance, result.FromAccount.Balance)
+
+	// the original transfer is untouched
+	transfer, err := testQueries.GetTransfer(context.Background(), original.Transfer.ID)
+	require.NoError(t, err)
+	require.Equal(t, original.Transfer.Amount, transfer.Amount)
+	require.False(t, transfer.ReversedTransferId.Valid)
+
+	_, err = store.ReverseTransferTx(context.Background(), ReverseTransferTxParams{TransferID: original.Transfer.ID})
+	require.ErrorIs(t, err, ErrTransferAlreadyReversed)
+	_, err = store.ReverseTransferTx(context.Background(), ReverseTransferTxParams{TransferID: reversal.ID})
+	require.ErrorIs(t, err, ErrTransferNotReversible)
+}
+
+func TestReverseTransferTxExchangeRate(t *testing.T) {
+	store := NewStore(testDB)
+	account1 := CreateRandomAccountWithCurrency(t, "USD")
+	account2 := CreateRandomAccountWithCurrency(t, "EUR")
+
+	original, err := store.TransferTx(context.Background(), TransferTxParams{
+		FromAccountID: account1.ID,
+		ToAccountID:   account2.ID,
+		Amount:        50,
+		ExchangeRate:  &exchange.Rate{From: "USD", To: "EUR", Rate: "0.8", UpdatedAt: time.Now()},
+	})
+	require.NoError(t, err)
+
+	result, err := store.ReverseTransferTx(context.Background(), ReverseTransferTxParams{TransferID: original.Transfer.ID})
+	require.NoError(t, err)
+	require.Equal(t, int64(40), result.Transfer.Amount)
+	require.Equal(t, int64(50), result.Transfer.ToAmount)
+	require.Equal(t, "1.2500000000", result.Transfer.ExchangeRate)
+	require.Equal(t, account1.Balance, result.ToAccount.Balance)
+	require.Equal(t, account2.Balance, result.FromAccount.Balance)
+}
+
+func TestReverseTransferTxPartial(t *testing.T) {
+	store := NewStore(testDB)
+	account1 := CreateRandomAccountWithCurrency(t, "USD")
+	account2 := CreateRandomAccountWithCurrency(t, "EUR")
+
+	original, err := store.TransferTx(context.Background(), TransferTxParams{
+		FromAccountID: account1.ID,
+		ToAccountID:   account2.ID,
+		Amount:        100,
+		ExchangeRate:  &exchange.Rate{From: "USD", To: "EUR", Rate: "0.8", UpdatedAt: time.Now()},
+	})
+	require.NoError(t, err)
+
+	result, err := store.ReverseTransferTx(context.Background(), ReverseTransferTxParams{TransferID: original.Transfer.ID, Amount: 30})
+	require.NoError(t, err)
+	require.Equal(t, int64(24), result.Transfer.Amount)
+	require.Equal(t, int64(30), result.Transfer.ToAmount)
+
+	_, err = store.ReverseTransferTx(context.Background(), ReverseTransferTxParams{TransferID: original.Transfer.ID, Amount: 71})
+	require.ErrorIs(t, err, ErrReversalExceedsTransfer)
+
+	// no amount refunds what is left
+	result, err = store.ReverseTransferTx(context.Background(), ReverseTransferTxParams{TransferID: original.Transfer.ID})
+	require.NoError(t, err)
+	require.Equal(t, int64(56), result.Transfer.Amount)
+	require.Equal(t, int64(70), result.Transfer.ToAmount)
+	require.Equal(t, account1.Balance, result.ToAccount.Balance)
+	require.Equal(t, account2.Balance, result.FromAccount.Balance)
+
+	_, err = store.ReverseTransferTx(context.Background(), ReverseTransferTxParams{TransferID: original.Transfer.ID, Amount: 1})
+	require.ErrorIs(t, err, ErrTransferAlreadyReversed)
+}
+
+func TestReversalAmounts(t *testing.T) {
+	original := Transfer{ID: 1, Amount: 3, ToAmount: 10}
+
+	amount, toAmount, err := reversalAmounts(original, GetTransferReversedAmountsRow{}, 1)
+	require.NoError(t, err)
+	require.Equal(t, int64(3), amount)
+	require.Equal(t, int64(1), toAmount)
+
+	// the last refund takes the remainder left by rounding
+	amount, toAmount, err = reversalAmounts(original, GetTransferReversedAmountsRow{ReversedAmount: 2, ReversedToAmount: 6}, 0)
+	require.NoError(t, err)
+	require.Equal(t, int64(4), amount)
+	require.Equal(t, int64(1), toAmount)
+
+	_, _, err = reversalAmounts(original, GetTransferReversedAmountsRow{ReversedAmount: 2, ReversedToAmount: 6}, 2)
+	require.ErrorIs(t, err, ErrReversalExceedsTransfer)
+	_, _, err = reversalAmounts(original, GetTransferReversedAmountsRow{ReversedAmount: 3, ReversedToAmount: 10}, 0)
+	require.ErrorIs(t, err, ErrTransferAlreadyReversed)
+}
@@ -0,0 +1,161 @@
+package db
+
+import (
+	"context"
+	"database/sql"
+	"simple_bank/util"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func CreateRandomScheduledTransfer(t *testing.T, nextRunAt time.Time) ScheduledTransfer {
+	account1 := CreateRandomAccount(t)
+	account2 := CreateRandomAccountWithCurrency(t, account1.Currency)
+	arg := CreateScheduledTransferParams{
+		Username:      account1.Username,
+		FromAccountId: account1.ID,
+		ToAccountId:   account2.ID,
+		Amount:        util.RandomMoney(),
+		Schedule:      "@daily",
+		NextRunAt:     nextRunAt,
+	}
+	scheduledTransfer, err := testQueries.CreateScheduledTransfer(context.Background(), arg)
+	require.NoError(t, err)
+	require.NotZero(t, scheduledTransfer.ID)
+	require.Equal(t, arg.Username, scheduledTransfer.Username)
+	require.Equal(t, arg.FromAccountId, scheduledTransfer.FromAccountId)
+	require.Equal(t, arg.ToAccountId, scheduledTransfer.ToAccountId)
+	require.Equal(t, arg.Amount, scheduledTransfer.Amount)
+	require.Equal(t, arg.Schedule, scheduledTransfer.Schedule)
+	require.WithinDuration(t, arg.NextRunAt, scheduledTransfer.NextRunAt, time.Second)
+	require.Equal(t, ScheduledTransferStatusActive, scheduledTransfer.Status)
+	require.Zero(t, scheduledTransfer.Attempts)
+	return scheduledTransfer
+}
+
+func TestCreateScheduledTransfer(t *testing.T) {
+	CreateRandomScheduledTransfer(t, time.Now().Add(time.Hour))
+}
+
+func TestGetScheduledTransfer(t *testing.T) {
+	scheduledTransfer1 := CreateRandomScheduledTransfer(t, time.Now().Add(time.Hour))
+	scheduledTransfer2, err := testQueries.GetScheduledTransfer(context.Background(), scheduledTransfer1.ID)
+	require.NoError(t, err)
+	require.Equal(t, scheduledTransfer1.ID, scheduledTransfer2.ID)
+	require.Equal(t, scheduledTransfer1.Amount, scheduledTransfer2.Amount)
+	require.WithinDuration(t, scheduledTransfer1.NextRunAt, scheduledTransfer2.NextRunAt, time.Second)
+}
+
+func TestListScheduledTransfers(t *testing.T) {
+	scheduledTransfer := CreateRandomScheduledTransfer(t, time.Now().Add(time.Hour))
+	scheduledTransfers, err := testQueries.ListScheduledTransfers(context.Background(), ListScheduledTransfersParams{
+		Username: scheduledTransfer.Username,
+		Limit:    5,
+		Offset:   0,
+	})
+	require.NoError(t, err)
+	require.Len(t, scheduledTransfers, 1)
+	require.Equal(t, scheduledTransfer.ID, scheduledTransfers[0].ID)
+}
+
+func TestUpdateScheduledTransfer(t *testing.T) {
+	scheduledTransfer1 := CreateRandomScheduledTransfer(t, time.Now().Add(time.Hour))
+	scheduledTransfer2, err := testQueries.UpdateScheduledTransfer(context.Background(), UpdateScheduledTransferParams{
+		ID:     scheduledTransfer1.ID,
+		Status: sql.NullString{String: ScheduledTransferStatusPaused, Valid: true},
+	})
+	require.NoError(t, err)
+	require.Equal(t, ScheduledTransferStatusPaused, scheduledTransfer2.Status)
+	require.Equal(t, scheduledTransfer1.Amount, scheduledTransfer2.Amount)
+	require.Equal(t, scheduledTransfer1.Schedule, scheduledTransfer2.Schedule)
+
+	scheduledTransfer3, err := testQueries.UpdateScheduledTransfer(context.Background(), UpdateScheduledTransferParams{
+		ID:       scheduledTransfer1.ID,
+		Amount:   sql.NullInt64{Int64: scheduledTransfer1.Amount + 1, Valid: true},
+		Schedule: sql.NullString{String: "@weekly", Valid: true},
+	})
+	require.NoError(t, err)
+	require.Equal(t, ScheduledTransferStatusPaused, scheduledTransfer3.Status)
+	require.Equal(t, scheduledTransfer1.Amount+1, scheduledTransfer3.Amount)
+	require.Equal(t, "@weekly", scheduledTransfer3.Schedule)
+}
+
+func TestClaimDueScheduledTransfers(t *testing.T) {
+	now := time.Now()
+	due := CreateRandomScheduledTransfer(t, now.Add(-time.Minute))
+	notDue := CreateRandomScheduledTransfer(t, now.Add(time.Hour))
+
+	arg := ClaimDueScheduledTransfersParams{
+		LockedUntil: now.Add(time.Minute),
+		Now:         now,
+		RowLimit:    1000,
+	}
+	claimed, err := testQueries.ClaimDueScheduledTransfers(context.Background(), arg)
+	require.NoError(t, err)
+	ids := make([]int64, len(claimed))
+	for i, scheduledTransfer := range claimed {
+		ids[i] = scheduledTransfer.ID
+	}
+	require.Contains(t, ids, due.ID)
+	require.NotContains(t, ids, notDue.ID)
+
+	// a claimed transfer is not claimed again until its lock expires
+	claimed, err = testQueries.ClaimDueScheduledTransfers(context.Background(), arg)
+	require.NoError(t, err)
+	for _, scheduledTransfer := range claimed {
+		require.NotEqual(t, due.ID, scheduledTransfer.ID)
+	}
+}
+
+func TestRecordScheduledTransferRunTx(t *testing.T) {
+	store := NewStore(testDB)
+	scheduledTransfer := CreateRandomScheduledTransfer(t, time.Now().Add(-time.Minute))
+	nextRunAt := time.Now().Add(24 * time.Hour)
+
+	result, err := store.RecordScheduledTransferRunTx(context.Background(), RecordScheduledTransferRunTxParams{
+		Run: CreateScheduledTransferRunParams{
+			ScheduledTransferId: scheduledTransfer.ID,
+			ScheduledFor:        scheduledTransfer.NextRunAt,
+			Attempt:             1,
+			Status:              ScheduledTransferRunFailed,
+			Error:               ErrInsufficientFunds.Error(),
+		},
+		Next: FinishScheduledTransferRunParams{
+			ID:        scheduledTransfer.ID,
+			NextRunAt: nextRunAt,
+			Status:    ScheduledTransferStatusActive,
+			Attempts:  1,
+		},
+	})
+	require.NoError(t, err)
+	require.Equal(t, scheduledTransfer.ID, result.Run.ScheduledTransferId)
+	require.Equal(t, ScheduledTransferRunFailed, result.Run.Status)
+	require.False(t, result.Run.TransferId.Valid)
+	require.WithinDuration(t, nextRunAt, result.ScheduledTransfer.NextRunAt, time.Second)
+	require.Equal(t, int32(1), result.ScheduledTransfer.Attempts)
+
+	runs, err := testQueries.ListScheduledTransferRuns(context.Background(), ListScheduledTransferRunsParams{
+		ScheduledTransferId: scheduledTransfer.ID,
+		Limit:               5,
+		Offset:              0,
+	})
+	require.NoError(t, err)
+	require.Len(t, runs, 1)
+	require.Equal(t, result.Run.ID, runs[0].ID)
+
+	// pausing the transfer while it runs is not undone when the run is recorded
+	_, err = testQueries.UpdateScheduledTransfer(context.Background(), UpdateScheduledTransferParams{
+		ID:     scheduledTransfer.ID,
+		Status: sql.NullString{String: ScheduledTransferStatusPaused, Valid: true},
+	})
+	require.NoError(t, err)
+	finished, err := testQueries.FinishScheduledTransferRun(context.Background(), FinishScheduledTransferRunParams{
+		ID:        scheduledTransfer.ID,
+		NextRunAt: nextRunAt,
+		Status:    ScheduledTransferStatusActive,
+	})
+	require.NoError(t, err)
+	require.Equal(t, ScheduledTransferStatusPaused, finished.Status)
+}
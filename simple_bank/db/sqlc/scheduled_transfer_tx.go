@@ -0,0 +1,47 @@
+package db
+
+import (
+	"context"
+	"database/sql"
+)
+
+const (
+	ScheduledTransferStatusActive    = "active"
+	ScheduledTransferStatusPaused    = "paused"
+	ScheduledTransferStatusCompleted = "completed"
+	ScheduledTransferStatusFailed    = "failed"
+	ScheduledTransferStatusCancelled = "cancelled"
+)
+
+const (
+	ScheduledTransferRunSucceeded = "succeeded"
+	ScheduledTransferRunFailed    = "failed"
+)
+
+type RecordScheduledTransferRunTxParams struct {
+	// outcome of the attempt
+	Run CreateScheduledTransferRunParams `json:"run"`
+	// when and in which status the scheduled transfer runs next
+	Next FinishScheduledTransferRunParams `json:"next"`
+}
+
+type RecordScheduledTransferRunTxResult struct {
+	ScheduledTransfer ScheduledTransfer    `json:"scheduledTransfer"`
+	Run               ScheduledTransferRun `json:"run"`
+}
+
+// RecordScheduledTransferRunTx stores the outcome of a scheduled transfer attempt and releases the
+// transfer for its next run within a transaction, so a recorded attempt is never run again.
+func (store *SqlStore) RecordScheduledTransferRunTx(ctx context.Context, arg RecordScheduledTransferRunTxParams) (RecordScheduledTransferRunTxResult, error) {
+	var result RecordScheduledTransferRunTxResult
+	_, err := store.execTx(ctx, sql.LevelReadCommitted, func(q *Queries) error {
+		var err error
+		result.Run, err = q.CreateScheduledTransferRun(ctx, arg.Run)
+		if err != nil {
+			return err
+		}
+		result.ScheduledTransfer, err = q.FinishScheduledTransferRun(ctx, arg.Next)
+		return err
+	})
+	return result, err
+}
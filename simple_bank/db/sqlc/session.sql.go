@@ -13,6 +13,23 @@ import (
 	"github.com/google/uuid"
 )
 
+const blockOtherUserSessions = `-- name: BlockOtherUserSessions :exec
+UPDATE "session"
+SET is_blocked = true
+WHERE username = $1 AND access_token <> $2
+`
+
+type BlockOtherUserSessionsParams struct {
+	Username    string `json:"username"`
+	AccessToken string `json:"access_token"`
+}
+
+// blocks the sessions of the user except the one the access token belongs to
+func (q *Queries) BlockOtherUserSessions(ctx context.Context, arg BlockOtherUserSessionsParams) error {
+	_, err := q.db.ExecContext(ctx, blockOtherUserSessions, arg.Username, arg.AccessToken)
+	return err
+}
+
 const createSession = `-- name: CreateSession :one
 INSERT INTO "session" (
     id,
@@ -2,13 +2,32 @@ package db
 
 import (
 	"context"
+	"crypto/sha256"
 	"database/sql"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
 	"fmt"
+	"log"
+	"math/rand"
+	"simple_bank/exchange"
+	"time"
+
+	"github.com/lib/pq"
 )
 
 type Store interface {
 	Querier
 	TransferTx(ctx context.Context, arg TransferTxParams) (TransferTxResult, error)
+	DepositTx(ctx context.Context, arg DepositTxParams) (BalanceTxResult, error)
+	WithdrawTx(ctx context.Context, arg WithdrawTxParams) (BalanceTxResult, error)
+	ReverseTransferTx(ctx context.Context, arg ReverseTransferTxParams) (TransferTxResult, error)
+	RecordScheduledTransferRunTx(ctx context.Context, arg RecordScheduledTransferRunTxParams) (RecordScheduledTransferRunTxResult, error)
+	UpdateAccountStatusTx(ctx context.Context, arg UpdateAccountStatusTxParams) (Account, error)
+	UpdateUserTx(ctx context.Context, arg UpdateUserTxParams) (UpdateUserTxResult, error)
+	CreateUserTx(ctx context.Context, arg CreateUserTxParams) (CreateUserTxResult, error)
+	VerifyEmailTx(ctx context.Context, arg VerifyEmailTxParams) (VerifyEmailTxResult, error)
+	ResendVerifyEmailTx(ctx context.Context, arg ResendVerifyEmailTxParams) (ResendVerifyEmailTxResult, error)
 }
 
 type SqlStore struct{
@@ -23,9 +42,48 @@ func NewStore(db *sql.DB) Store {
 	}
 }
 
-// executes a function within a transaction.
-func (store *SqlStore) execTx(ctx context.Context, fn func(*Queries) error) error {
-	tx, err := store.db.BeginTx(ctx, nil)
+const (
+	// serialization failures and deadlocks only mean the transaction lost a race, running it again is safe
+	serializationFailureCode pq.ErrorCode = "40001"
+	deadlockDetectedCode     pq.ErrorCode = "40P01"
+
+	maxTxRetries     = 10
+	txRetryBaseDelay = 5 * time.Millisecond
+	txRetryMaxDelay  = 500 * time.Millisecond
+)
+
+// executes a function within a transaction at the given isolation level.
+// Serialization failures and deadlocks are retried with exponential backoff, so fn must be safe to run again.
+// It returns how many retries the transaction needed, every transaction that needed some is logged with the count.
+func (store *SqlStore) execTx(ctx context.Context, isolation sql.IsolationLevel, fn func(*Queries) error) (int, error) {
+	for retries := 0; ; retries++ {
+		err := store.runTx(ctx, isolation, fn)
+		if err == nil || retries == maxTxRetries || !isRetryableTxError(err) {
+			logTxRetries(isolation, retries, err)
+			return retries, err
+		}
+		select {
+		case <-ctx.Done():
+			logTxRetries(isolation, retries, err)
+			return retries, err
+		case <-time.After(txRetryDelay(retries)):
+		}
+	}
+}
+
+func logTxRetries(isolation sql.IsolationLevel, retries int, err error) {
+	if retries == 0 {
+		return
+	}
+	if err != nil {
+		log.Printf("%s transaction failed after %d retries: %v", isolation, retries, err)
+		return
+	}
+	log.Printf("%s transaction committed after %d retries", isolation, retries)
+}
+
+func (store *SqlStore) runTx(ctx context.Context, isolation sql.IsolationLevel, fn func(*Queries) error) error {
+	tx, err := store.db.BeginTx(ctx, &sql.TxOptions{Isolation: isolation})
 	if err != nil {
 		return err
 	}
@@ -34,17 +92,44 @@ func (store *SqlStore) execTx(ctx context.Context, fn func(*Queries) error) erro
 	if err != nil {
 		rbErr := tx.Rollback()
 		if rbErr != nil {
-			return fmt.Errorf("tx err: %v, rb err: %v", err, rbErr)
+			return fmt.Errorf("tx err: %w, rb err: %v", err, rbErr)
 		}
 		return err
 	}
 	return tx.Commit()
 }
 
+func isRetryableTxError(err error) bool {
+	var pqErr *pq.Error
+	if errors.As(err, &pqErr) {
+		return pqErr.Code == serializationFailureCode || pqErr.Code == deadlockDetectedCode
+	}
+	return false
+}
+
+// txRetryDelay doubles the delay on every retry up to txRetryMaxDelay, with jitter
+// so that the transactions that collided do not collide again.
+func txRetryDelay(retries int) time.Duration {
+	delay := txRetryBaseDelay
+	for i := 0; i < retries && delay < txRetryMaxDelay; i++ {
+		delay *= 2
+	}
+	if delay > txRetryMaxDelay {
+		delay = txRetryMaxDelay
+	}
+	return delay/2 + time.Duration(rand.Int63n(int64(delay/2)+1))
+}
+
 type TransferTxParams struct {
 	FromAccountID int64 `json:"fromAccountId"`
 	ToAccountID   int64 `json:"toAccountId"`
 	Amount        int64 `json:"amount"`
+	// optional, a retry with the same key and params returns the original result instead of moving money again
+	IdempotencyKey string `json:"-"`
+	// owner of the idempotency key, keys of different users never collide
+	Username string `json:"-"`
+	// required when the accounts hold different currencies, Amount is then in the source account currency
+	ExchangeRate *exchange.Rate `json:"-"`
 }
 type TransferTxResult struct {
 	Transfer    Transfer `json:"transfer"`
@@ -52,56 +137,246 @@ type TransferTxResult struct {
 	ToAccount   Account  `json:"toAccount"`
 	FromEntry   Entry    `json:"fromEntry"`
 	ToEntry     Entry    `json:"toEntry"`
+	// how many times the transaction was retried after a serialization failure or deadlock
+	Retries int `json:"-"`
 }
 
 var txKey = struct{}{}
 
+const accountBalanceConstraint = "account_balance_check"
+
+var ErrIdempotencyKeyConflict = errors.New("idempotency key was already used with a different request")
+
+// ErrInsufficientFunds is returned when a transfer would take the source account below its overdraft limit
+var ErrInsufficientFunds = errors.New("insufficient funds")
+
+// ErrCurrencyMismatch is returned when the accounts hold different currencies and no matching exchange rate was given
+var ErrCurrencyMismatch = errors.New("currency mismatch")
+
+// errIdempotencyKeyTaken aborts a transfer whose key was stored first by a concurrent transaction
+var errIdempotencyKeyTaken = errors.New("idempotency key taken by a concurrent request")
+
 // creates a transfer record, account entries and updates accounts' balance within a transaction.
 func (store *SqlStore) TransferTx(ctx context.Context, arg TransferTxParams) (TransferTxResult, error) {
 	var result TransferTxResult
 
-	err := store.execTx(ctx, func(q *Queries) error {
+	requestHash, err := arg.requestHash()
+	if err != nil {
+		return result, err
+	}
+
+	retries, err := store.execTx(ctx, sql.LevelSerializable, func(q *Queries) error {
 		var err error
-		fmt.Print(ctx.Value(txKey))
-		result.Transfer, err = q.CreateTransfer(ctx, CreateTransferParams{
-			FromAccountId: arg.FromAccountID,
-			ToAccountId:   arg.ToAccountID,
-			Amount:        arg.Amount,
-		})
+		result = TransferTxResult{}
+		if arg.IdempotencyKey != "" {
+			replayed, err := replayTransferTx(ctx, q, arg, requestHash, &result)
+			if err != nil || replayed {
+				return err
+			}
+		}
+		fromAccount, toAccount, err := getAccountsForUpdate(ctx, q, arg.FromAccountID, arg.ToAccountID)
 		if err != nil {
 			return err
 		}
-
-		result.FromEntry, err = q.CreateEntry(ctx, CreateEntryParams{
-			AccountId: arg.FromAccountID,
-			Amount:    -arg.Amount,
-		})
+		if err := checkAccountsActive(fromAccount, toAccount); err != nil {
+			return err
+		}
+		if err := checkEmailVerified(ctx, q, fromAccount.Username); err != nil {
+			return err
+		}
+		if err := checkTransferLimits(ctx, q, fromAccount, arg.Amount, time.Now()); err != nil {
+			return err
+		}
+		conversion, err := transferConversion(ctx, q, arg, fromAccount, toAccount)
 		if err != nil {
 			return err
 		}
-		result.ToEntry, err = q.CreateEntry(ctx, CreateEntryParams{
-			AccountId: arg.ToAccountID,
-			Amount:    arg.Amount,
-		})
+		if fromAccount.Balance-arg.Amount < -fromAccount.OverdraftLimit {
+			return fmt.Errorf("%w: account [%d] has %d available, %d requested",
+				ErrInsufficientFunds, fromAccount.ID, fromAccount.Balance+fromAccount.OverdraftLimit, arg.Amount)
+		}
+
+		result, err = bookTransfer(ctx, q, CreateTransferParams{
+			FromAccountId: arg.FromAccountID,
+			ToAccountId:   arg.ToAccountID,
+			Amount:        arg.Amount,
+			ToAmount:      conversion.ToAmount,
+			ExchangeRate:  conversion.ExchangeRate,
+			RateTimestamp: conversion.RateTimestamp,
+		}, EntryReasonTransfer)
 		if err != nil {
 			return err
 		}
-		//TODO: update accounts' balance
-		if arg.FromAccountID < arg.ToAccountID {
-			result.FromAccount, result.ToAccount, _ = AddAmount(ctx, q, arg.FromAccountID, arg.ToAccountID, arg.Amount)
-		} else {
-			result.ToAccount, result.FromAccount, _ = AddAmount(ctx, q, arg.ToAccountID, arg.FromAccountID, -arg.Amount)
+		if arg.IdempotencyKey != "" {
+			return saveTransferTxResult(ctx, q, arg, requestHash, result)
 		}
 		return nil
 	})
+	if errors.Is(err, errIdempotencyKeyTaken) {
+		result = TransferTxResult{}
+		_, err = replayTransferTx(ctx, store.Queries, arg, requestHash, &result)
+	}
+	result.Retries = retries
 	return result, err
 }
 
+// bookTransfer writes the transfer, its two entries and updates both balances, the caller must hold the account locks
+func bookTransfer(ctx context.Context, q *Queries, arg CreateTransferParams, reason string) (TransferTxResult, error) {
+	var result TransferTxResult
+	var err error
+	result.Transfer, err = q.CreateTransfer(ctx, arg)
+	if err != nil {
+		return result, err
+	}
+	transferId := sql.NullInt64{Int64: result.Transfer.ID, Valid: true}
+
+	result.FromEntry, err = q.CreateEntry(ctx, CreateEntryParams{
+		AccountId:  arg.FromAccountId,
+		Amount:     -arg.Amount,
+		Reason:     reason,
+		TransferId: transferId,
+	})
+	if err != nil {
+		return result, err
+	}
+	result.ToEntry, err = q.CreateEntry(ctx, CreateEntryParams{
+		AccountId:  arg.ToAccountId,
+		Amount:     arg.ToAmount,
+		Reason:     reason,
+		TransferId: transferId,
+	})
+	if err != nil {
+		return result, err
+	}
+	if arg.FromAccountId < arg.ToAccountId {
+		result.FromAccount, result.ToAccount, err = AddAmount(ctx, q, arg.FromAccountId, -arg.Amount, arg.ToAccountId, arg.ToAmount)
+	} else {
+		result.ToAccount, result.FromAccount, err = AddAmount(ctx, q, arg.ToAccountId, arg.ToAmount, arg.FromAccountId, -arg.Amount)
+	}
+	if err != nil {
+		return result, translateBalanceError(err)
+	}
+	return result, nil
+}
+
+type transferConversionResult struct {
+	ToAmount      int64
+	ExchangeRate  string
+	RateTimestamp sql.NullTime
+}
+
+// transferConversion works out the amount credited to the destination account, converting it
+// with the given exchange rate when the accounts hold different currencies. The minor units of both
+// currencies come from the currency table, so amounts are rescaled between e.g. USD cents and whole JPY.
+func transferConversion(ctx context.Context, q *Queries, arg TransferTxParams, fromAccount Account, toAccount Account) (transferConversionResult, error) {
+	if fromAccount.Currency == toAccount.Currency {
+		return transferConversionResult{
+			ToAmount:     arg.Amount,
+			ExchangeRate: "1",
+		}, nil
+	}
+	rate := arg.ExchangeRate
+	if rate == nil || rate.From != fromAccount.Currency || rate.To != toAccount.Currency {
+		return transferConversionResult{}, fmt.Errorf("%w: no exchange rate from %s to %s",
+			ErrCurrencyMismatch, fromAccount.Currency, toAccount.Currency)
+	}
+	fromCurrency, err := q.GetCurrency(ctx, fromAccount.Currency)
+	if err != nil {
+		return transferConversionResult{}, err
+	}
+	toCurrency, err := q.GetCurrency(ctx, toAccount.Currency)
+	if err != nil {
+		return transferConversionResult{}, err
+	}
+	toAmount, err := exchange.Convert(arg.Amount, rate.Rate, int(fromCurrency.MinorUnit), int(toCurrency.MinorUnit))
+	if err != nil {
+		return transferConversionResult{}, err
+	}
+	return transferConversionResult{
+		ToAmount:      toAmount,
+		ExchangeRate:  rate.Rate,
+		RateTimestamp: sql.NullTime{Time: rate.UpdatedAt, Valid: true},
+	}, nil
+}
+
+// getAccountsForUpdate locks both accounts in ascending id order, so concurrent transfers
+// between the same pair of accounts in opposite directions cannot deadlock.
+func getAccountsForUpdate(ctx context.Context, q *Queries, accountId1 int64, accountId2 int64) (account1 Account, account2 Account, err error) {
+	if accountId1 > accountId2 {
+		account2, account1, err = getAccountsForUpdate(ctx, q, accountId2, accountId1)
+		return
+	}
+	account1, err = q.GetAccountForUpdate(ctx, accountId1)
+	if err != nil {
+		return
+	}
+	account2, err = q.GetAccountForUpdate(ctx, accountId2)
+	return
+}
+
+// translateBalanceError reports a violation of the account balance CHECK constraint as ErrInsufficientFunds
+func translateBalanceError(err error) error {
+	var pqErr *pq.Error
+	if errors.As(err, &pqErr) && pqErr.Constraint == accountBalanceConstraint {
+		return fmt.Errorf("%w: %v", ErrInsufficientFunds, pqErr.Message)
+	}
+	return err
+}
+
+// requestHash fingerprints the transfer so a reused idempotency key can be told apart from a retry
+func (arg TransferTxParams) requestHash() (string, error) {
+	data, err := json.Marshal(arg)
+	if err != nil {
+		return "", err
+	}
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:]), nil
+}
+
+// replayTransferTx loads the stored result of a previous transfer with the same idempotency key.
+// It reports false when the key was never used.
+func replayTransferTx(ctx context.Context, q *Queries, arg TransferTxParams, requestHash string, result *TransferTxResult) (bool, error) {
+	key, err := q.GetIdempotencyKey(ctx, GetIdempotencyKeyParams{
+		Username: arg.Username,
+		Key:      arg.IdempotencyKey,
+	})
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return false, nil
+		}
+		return false, err
+	}
+	if key.RequestHash != requestHash {
+		return false, ErrIdempotencyKeyConflict
+	}
+	return true, json.Unmarshal(key.Response, result)
+}
+
+func saveTransferTxResult(ctx context.Context, q *Queries, arg TransferTxParams, requestHash string, result TransferTxResult) error {
+	response, err := json.Marshal(result)
+	if err != nil {
+		return err
+	}
+	_, err = q.CreateIdempotencyKey(ctx, CreateIdempotencyKeyParams{
+		Username:    arg.Username,
+		Key:         arg.IdempotencyKey,
+		RequestHash: requestHash,
+		Response:    response,
+	})
+	if err == sql.ErrNoRows {
+		// the insert did nothing, a concurrent request with the same key committed first
+		return errIdempotencyKeyTaken
+	}
+	return err
+}
+
+// AddAmount adds each amount to its account, callers pass the accounts in ascending id order
 func AddAmount(ctx context.Context,
 	q *Queries,
 	accountId1 int64,
+	amount1 int64,
 	accountId2 int64,
-	amount int64,
+	amount2 int64,
 ) (
 	account1 Account,
 	account2 Account,
@@ -109,14 +384,14 @@ func AddAmount(ctx context.Context,
 ) {
 	account1, err = q.AddAmountAccount(ctx, AddAmountAccountParams{
 		ID:     accountId1,
-		Amount: -amount,
+		Amount: amount1,
 	})
 	if err != nil {
 		return
 	}
 	account2, err = q.AddAmountAccount(ctx, AddAmountAccountParams{
 		ID:     accountId2,
-		Amount: amount,
+		Amount: amount2,
 	})
 	return
 
@@ -1,17 +1,25 @@
 package db
 
 import (
+	"bytes"
 	"context"
+	"database/sql"
 	"fmt"
+	"log"
+	"os"
+	"simple_bank/exchange"
+	"simple_bank/util"
 	"testing"
+	"time"
 
+	"github.com/lib/pq"
 	"github.com/stretchr/testify/require"
 )
 
 func TestTransferTx(t *testing.T) {
 	store := NewStore(testDB)
 	account1 := CreateRandomAccount(t)
-	account2 := CreateRandomAccount(t)
+	account2 := CreateRandomAccountWithCurrency(t, account1.Currency)
 	fmt.Println(">> before:", account1.Balance, account2.Balance)
 
 	n := 5
@@ -109,7 +117,7 @@ func TestTransferTx(t *testing.T) {
 func TestTransferTxDeadlock(t *testing.T) {
 	store := NewStore(testDB)
 	account1 := CreateRandomAccount(t)
-	account2 := CreateRandomAccount(t)
+	account2 := CreateRandomAccountWithCurrency(t, account1.Currency)
 	fmt.Println(">> before:", account1.Balance, account2.Balance)
 
 	n := 10
@@ -151,3 +159,223 @@ func TestTransferTxDeadlock(t *testing.T) {
 	require.Equal(t, account2.Balance, updatedAccount2.Balance)
 	fmt.Println(">> After:", updatedAccount1.Balance, updatedAccount2.Balance)
 }
+
+func TestTransferTxIdempotency(t *testing.T) {
+	store := NewStore(testDB)
+	account1 := CreateRandomAccount(t)
+	account2 := CreateRandomAccountWithCurrency(t, account1.Currency)
+
+	n := 5
+	arg := TransferTxParams{
+		FromAccountID:  account1.ID,
+		ToAccountID:    account2.ID,
+		Amount:         10,
+		IdempotencyKey: util.RandomString(16),
+		Username:       account1.Username,
+	}
+
+	errs := make(chan error)
+	results := make(chan TransferTxResult)
+
+	// run n concurrent retries of the same transfer
+	for i := 0; i < n; i++ {
+		go func() {
+			result, err := store.TransferTx(context.Background(), arg)
+			errs <- err
+			results <- result
+		}()
+	}
+
+	var transferID int64
+	for i := 0; i < n; i++ {
+		err := <-errs
+		require.NoError(t, err)
+		result := <-results
+		require.NotZero(t, result.Transfer.ID)
+		if transferID == 0 {
+			transferID = result.Transfer.ID
+		}
+		require.Equal(t, transferID, result.Transfer.ID)
+	}
+
+	// money moved only once
+	updatedAccount1, err := testQueries.GetAccount(context.Background(), account1.ID)
+	require.NoError(t, err)
+	updatedAccount2, err := testQueries.GetAccount(context.Background(), account2.ID)
+	require.NoError(t, err)
+	require.Equal(t, account1.Balance-arg.Amount, updatedAccount1.Balance)
+	require.Equal(t, account2.Balance+arg.Amount, updatedAccount2.Balance)
+
+	// same key with a different request is rejected
+	arg.Amount = 20
+	_, err = store.TransferTx(context.Background(), arg)
+	require.ErrorIs(t, err, ErrIdempotencyKeyConflict)
+}
+
+func TestTransferTxInsufficientFunds(t *testing.T) {
+	store := NewStore(testDB)
+	account1 := CreateRandomAccount(t)
+	account2 := CreateRandomAccountWithCurrency(t, account1.Currency)
+
+	_, err := store.TransferTx(context.Background(), TransferTxParams{
+		FromAccountID: account1.ID,
+		ToAccountID:   account2.ID,
+		Amount:        account1.Balance + 1,
+	})
+	require.ErrorIs(t, err, ErrInsufficientFunds)
+
+	// nothing was written
+	updatedAccount1, err := testQueries.GetAccount(context.Background(), account1.ID)
+	require.NoError(t, err)
+	require.Equal(t, account1.Balance, updatedAccount1.Balance)
+
+	// a direct update is still stopped by the CHECK constraint
+	_, err = testQueries.AddAmountAccount(context.Background(), AddAmountAccountParams{
+		ID:     account1.ID,
+		Amount: -account1.Balance - 1,
+	})
+	require.ErrorIs(t, translateBalanceError(err), ErrInsufficientFunds)
+}
+
+func TestTransferTxOverdraft(t *testing.T) {
+	store := NewStore(testDB)
+	account1 := CreateRandomAccount(t)
+	account2 := CreateRandomAccountWithCurrency(t, account1.Currency)
+	overdraftLimit := int64(100)
+	account1, err := testQueries.UpdateAccountOverdraftLimit(context.Background(), UpdateAccountOverdraftLimitParams{
+		ID:             account1.ID,
+		OverdraftLimit: overdraftLimit,
+	})
+	require.NoError(t, err)
+
+	result, err := store.TransferTx(context.Background(), TransferTxParams{
+		FromAccountID: account1.ID,
+		ToAccountID:   account2.ID,
+		Amount:        account1.Balance + overdraftLimit,
+	})
+	require.NoError(t, err)
+	require.Equal(t, -overdraftLimit, result.FromAccount.Balance)
+
+	_, err = store.TransferTx(context.Background(), TransferTxParams{
+		FromAccountID: account1.ID,
+		ToAccountID:   account2.ID,
+		Amount:        1,
+	})
+	require.ErrorIs(t, err, ErrInsufficientFunds)
+}
+
+func TestExecTxRetry(t *testing.T) {
+	store := NewStore(testDB).(*SqlStore)
+
+	attempts := 0
+	retries, err := store.execTx(context.Background(), sql.LevelSerializable, func(q *Queries) error {
+		attempts++
+		if attempts < 3 {
+			return &pq.Error{Code: serializationFailureCode}
+		}
+		return nil
+	})
+	require.NoError(t, err)
+	require.Equal(t, 2, retries)
+
+	// errors that are not transient are returned right away
+	attempts = 0
+	retries, err = store.execTx(context.Background(), sql.LevelReadCommitted, func(q *Queries) error {
+		attempts++
+		return sql.ErrNoRows
+	})
+	require.ErrorIs(t, err, sql.ErrNoRows)
+	require.Zero(t, retries)
+	require.Equal(t, 1, attempts)
+
+	// retries are bounded
+	retries, err = store.execTx(context.Background(), sql.LevelSerializable, func(q *Queries) error {
+		return &pq.Error{Code: deadlockDetectedCode}
+	})
+	require.Error(t, err)
+	require.Equal(t, maxTxRetries, retries)
+
+	// and stop once the context is done
+	ctx, cancel := context.WithCancel(context.Background())
+	retries, err = store.execTx(ctx, sql.LevelSerializable, func(q *Queries) error {
+		cancel()
+		return &pq.Error{Code: serializationFailureCode}
+	})
+	require.Error(t, err)
+	require.Zero(t, retries)
+}
+
+func TestLogTxRetries(t *testing.T) {
+	var output bytes.Buffer
+	log.SetOutput(&output)
+	defer log.SetOutput(os.Stderr)
+
+	logTxRetries(sql.LevelSerializable, 0, nil)
+	require.Empty(t, output.String())
+
+	logTxRetries(sql.LevelSerializable, 2, nil)
+	require.Contains(t, output.String(), "Serializable transaction committed after 2 retries")
+
+	output.Reset()
+	logTxRetries(sql.LevelSerializable, maxTxRetries, ErrInsufficientFunds)
+	require.Contains(t, output.String(), fmt.Sprintf("failed after %d retries", maxTxRetries))
+}
+
+func TestTxRetryDelay(t *testing.T) {
+	for retries := 0; retries <= maxTxRetries; retries++ {
+		delay := txRetryDelay(retries)
+		require.Positive(t, delay)
+		require.LessOrEqual(t, delay, txRetryMaxDelay)
+	}
+}
+
+func TestTransferTxExchangeRate(t *testing.T) {
+	store := NewStore(testDB)
+	account1 := CreateRandomAccountWithCurrency(t, util.GetCurrency(0))
+	account2 := CreateRandomAccountWithCurrency(t, util.GetCurrency(1))
+	amount := int64(50)
+
+	// different currencies need a rate
+	_, err := store.TransferTx(context.Background(), TransferTxParams{
+		FromAccountID: account1.ID,
+		ToAccountID:   account2.ID,
+		Amount:        amount,
+	})
+	require.ErrorIs(t, err, ErrCurrencyMismatch)
+
+	rate := &exchange.Rate{
+		From:      account1.Currency,
+		To:        account2.Currency,
+		Rate:      "0.9",
+		UpdatedAt: time.Now().Add(-time.Hour).Truncate(time.Microsecond),
+	}
+	result, err := store.TransferTx(context.Background(), TransferTxParams{
+		FromAccountID: account1.ID,
+		ToAccountID:   account2.ID,
+		Amount:        amount,
+		ExchangeRate:  rate,
+	})
+	require.NoError(t, err)
+
+	transfer := result.Transfer
+	require.Equal(t, amount, transfer.Amount)
+	require.Equal(t, int64(45), transfer.ToAmount)
+	require.Equal(t, "0.9", transfer.ExchangeRate)
+	require.True(t, transfer.RateTimestamp.Valid)
+	require.WithinDuration(t, rate.UpdatedAt, transfer.RateTimestamp.Time, time.Second)
+
+	require.Equal(t, -amount, result.FromEntry.Amount)
+	require.Equal(t, transfer.ToAmount, result.ToEntry.Amount)
+	require.Equal(t, account1.Balance-amount, result.FromAccount.Balance)
+	require.Equal(t, account2.Balance+transfer.ToAmount, result.ToAccount.Balance)
+
+	// a rate for another currency pair is not applied
+	rate.From, rate.To = rate.To, rate.From
+	_, err = store.TransferTx(context.Background(), TransferTxParams{
+		FromAccountID: account1.ID,
+		ToAccountID:   account2.ID,
+		Amount:        amount,
+		ExchangeRate:  rate,
+	})
+	require.ErrorIs(t, err, ErrCurrencyMismatch)
+}
@@ -0,0 +1,99 @@
+package db
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func CreateRandomTask(t *testing.T) Task {
+	arg := CreateTaskParams{
+		Type:        "task:test",
+		Payload:     []byte(`{"key": "value"}`),
+		MaxAttempts: 3,
+	}
+	task, err := testQueries.CreateTask(context.Background(), arg)
+	require.NoError(t, err)
+	require.NotZero(t, task.ID)
+	require.Equal(t, arg.Type, task.Type)
+	require.JSONEq(t, string(arg.Payload), string(task.Payload))
+	require.Equal(t, arg.MaxAttempts, task.MaxAttempts)
+	require.Equal(t, "pending", task.Status)
+	require.Zero(t, task.Attempts)
+	require.WithinDuration(t, time.Now(), task.RunAt, time.Second)
+	require.False(t, task.FinishedAt.Valid)
+	return task
+}
+
+func TestCreateTask(t *testing.T) {
+	CreateRandomTask(t)
+}
+
+func TestGetTask(t *testing.T) {
+	task1 := CreateRandomTask(t)
+	task2, err := testQueries.GetTask(context.Background(), task1.ID)
+	require.NoError(t, err)
+	require.Equal(t, task1.ID, task2.ID)
+	require.Equal(t, task1.Type, task2.Type)
+	require.JSONEq(t, string(task1.Payload), string(task2.Payload))
+	require.WithinDuration(t, task1.RunAt, task2.RunAt, time.Second)
+}
+
+func TestClaimDueTasks(t *testing.T) {
+	now := time.Now()
+	due := CreateRandomTask(t)
+	notDue := CreateRandomTask(t)
+	_, err := testQueries.FinishTask(context.Background(), FinishTaskParams{
+		ID:        notDue.ID,
+		Status:    "pending",
+		Attempts:  1,
+		RunAt:     now.Add(time.Hour),
+		LastError: "failed",
+	})
+	require.NoError(t, err)
+
+	arg := ClaimDueTasksParams{
+		LockedUntil: now.Add(time.Minute),
+		Now:         now.Add(time.Second),
+		RowLimit:    1000,
+	}
+	claimed, err := testQueries.ClaimDueTasks(context.Background(), arg)
+	require.NoError(t, err)
+	ids := make([]int64, len(claimed))
+	for i, task := range claimed {
+		ids[i] = task.ID
+	}
+	require.Contains(t, ids, due.ID)
+	require.NotContains(t, ids, notDue.ID)
+
+	// a claimed task is not claimed again until its lock expires
+	claimed, err = testQueries.ClaimDueTasks(context.Background(), arg)
+	require.NoError(t, err)
+	for _, task := range claimed {
+		require.NotEqual(t, due.ID, task.ID)
+	}
+}
+
+func TestFinishTask(t *testing.T) {
+	task := CreateRandomTask(t)
+	finishedAt := time.Now()
+	arg := FinishTaskParams{
+		ID:         task.ID,
+		Status:     "dead",
+		Attempts:   3,
+		RunAt:      task.RunAt,
+		LastError:  "failed",
+		FinishedAt: sql.NullTime{Time: finishedAt, Valid: true},
+	}
+	finished, err := testQueries.FinishTask(context.Background(), arg)
+	require.NoError(t, err)
+	require.Equal(t, arg.Status, finished.Status)
+	require.Equal(t, arg.Attempts, finished.Attempts)
+	require.Equal(t, arg.LastError, finished.LastError)
+	require.True(t, finished.FinishedAt.Valid)
+	require.WithinDuration(t, finishedAt, finished.FinishedAt.Time, time.Second)
+	require.True(t, finished.LockedUntil.Before(time.Now()))
+}
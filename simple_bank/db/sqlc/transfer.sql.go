@@ -7,22 +7,36 @@ package db
 
 import (
 	"context"
+	"database/sql"
+	"time"
 )
 
 const createTransfer = `-- name: CreateTransfer :one
-insert into transfer("fromAccountId", "toAccountId", "amount")
-values($1, $2, $3)
-RETURNING id, "fromAccountId", "toAccountId", amount, "createdAt"
+insert into transfer("fromAccountId", "toAccountId", "amount", "toAmount", "exchangeRate", "rateTimestamp", "reversedTransferId")
+values($1, $2, $3, $4, $5, $6, $7)
+RETURNING id, "fromAccountId", "toAccountId", amount, "createdAt", "toAmount", "exchangeRate", "rateTimestamp", "reversedTransferId"
 `
 
 type CreateTransferParams struct {
-	FromAccountId int64 `json:"fromAccountId"`
-	ToAccountId   int64 `json:"toAccountId"`
-	Amount        int64 `json:"amount"`
+	FromAccountId      int64         `json:"fromAccountId"`
+	ToAccountId        int64         `json:"toAccountId"`
+	Amount             int64         `json:"amount"`
+	ToAmount           int64         `json:"toAmount"`
+	ExchangeRate       string        `json:"exchangeRate"`
+	RateTimestamp      sql.NullTime  `json:"rateTimestamp"`
+	ReversedTransferId sql.NullInt64 `json:"reversedTransferId"`
 }
 
 func (q *Queries) CreateTransfer(ctx context.Context, arg CreateTransferParams) (Transfer, error) {
-	row := q.db.QueryRowContext(ctx, createTransfer, arg.FromAccountId, arg.ToAccountId, arg.Amount)
+	row := q.db.QueryRowContext(ctx, createTransfer,
+		arg.FromAccountId,
+		arg.ToAccountId,
+		arg.Amount,
+		arg.ToAmount,
+		arg.ExchangeRate,
+		arg.RateTimestamp,
+		arg.ReversedTransferId,
+	)
 	var i Transfer
 	err := row.Scan(
 		&i.ID,
@@ -30,22 +44,16 @@ func (q *Queries) CreateTransfer(ctx context.Context, arg CreateTransferParams)
 		&i.ToAccountId,
 		&i.Amount,
 		&i.CreatedAt,
+		&i.ToAmount,
+		&i.ExchangeRate,
+		&i.RateTimestamp,
+		&i.ReversedTransferId,
 	)
 	return i, err
 }
 
-const deleteTransfer = `-- name: DeleteTransfer :exec
-delete from transfer
-where id = $1
-`
-
-func (q *Queries) DeleteTransfer(ctx context.Context, id int64) error {
-	_, err := q.db.ExecContext(ctx, deleteTransfer, id)
-	return err
-}
-
 const getTransfer = `-- name: GetTransfer :one
-select id, "fromAccountId", "toAccountId", amount, "createdAt"
+select id, "fromAccountId", "toAccountId", amount, "createdAt", "toAmount", "exchangeRate", "rateTimestamp", "reversedTransferId"
 from transfer
 where id = $1
 limit 1
@@ -60,12 +68,38 @@ func (q *Queries) GetTransfer(ctx context.Context, id int64) (Transfer, error) {
 		&i.ToAccountId,
 		&i.Amount,
 		&i.CreatedAt,
+		&i.ToAmount,
+		&i.ExchangeRate,
+		&i.RateTimestamp,
+		&i.ReversedTransferId,
+	)
+	return i, err
+}
+
+const getTransferReversedAmounts = `-- name: GetTransferReversedAmounts :one
+select COALESCE(SUM("toAmount"), 0)::bigint AS "reversedAmount",
+  COALESCE(SUM(amount), 0)::bigint AS "reversedToAmount"
+from transfer
+where "reversedTransferId" = $1
+`
+
+type GetTransferReversedAmountsRow struct {
+	ReversedAmount   int64 `json:"reversedAmount"`
+	ReversedToAmount int64 `json:"reversedToAmount"`
+}
+
+func (q *Queries) GetTransferReversedAmounts(ctx context.Context, reversedTransferId sql.NullInt64) (GetTransferReversedAmountsRow, error) {
+	row := q.db.QueryRowContext(ctx, getTransferReversedAmounts, reversedTransferId)
+	var i GetTransferReversedAmountsRow
+	err := row.Scan(
+		&i.ReversedAmount,
+		&i.ReversedToAmount,
 	)
 	return i, err
 }
 
 const getTransfers = `-- name: GetTransfers :many
-select id, "fromAccountId", "toAccountId", amount, "createdAt"
+select id, "fromAccountId", "toAccountId", amount, "createdAt", "toAmount", "exchangeRate", "rateTimestamp", "reversedTransferId"
 from transfer
 order by id
 limit $1 offset $2
@@ -91,6 +125,10 @@ func (q *Queries) GetTransfers(ctx context.Context, arg GetTransfersParams) ([]T
 			&i.ToAccountId,
 			&i.Amount,
 			&i.CreatedAt,
+			&i.ToAmount,
+			&i.ExchangeRate,
+			&i.RateTimestamp,
+			&i.ReversedTransferId,
 		); err != nil {
 			return nil, err
 		}
@@ -105,27 +143,94 @@ func (q *Queries) GetTransfers(ctx context.Context, arg GetTransfersParams) ([]T
 	return items, nil
 }
 
-const updateTransfer = `-- name: UpdateTransfer :one
-update transfer
-set amount = $2
-where id = $1
-RETURNING id, "fromAccountId", "toAccountId", amount, "createdAt"
+const listUserTransfers = `-- name: ListUserTransfers :many
+select id, "fromAccountId", "toAccountId", amount, "createdAt", "toAmount", "exchangeRate", "rateTimestamp", "reversedTransferId"
+from transfer
+where id in (
+    -- incoming transfers, served by the ("toAccountId", "createdAt") index
+    select t.id
+    from transfer t
+      join account a on a.id = t."toAccountId"
+    where a.username = $1
+      and $2::text <> 'out'
+      and ($3::bigint is null or t."toAccountId" = $3)
+      and ($4::bigint is null or t."fromAccountId" = $4)
+      and ($5::timestamptz is null or t."createdAt" >= $5)
+      and ($6::timestamptz is null or t."createdAt" < $6)
+    union
+    -- outgoing transfers
+    select t.id
+    from transfer t
+      join account a on a.id = t."fromAccountId"
+    where a.username = $1
+      and $2::text <> 'in'
+      and ($3::bigint is null or t."fromAccountId" = $3)
+      and ($4::bigint is null or t."toAccountId" = $4)
+      and ($5::timestamptz is null or t."createdAt" >= $5)
+      and ($6::timestamptz is null or t."createdAt" < $6)
+  )
+  and ($7::bigint is null or amount >= $7)
+  and ($8::bigint is null or amount <= $8)
+  and ("createdAt", id) > ($9::timestamptz, $10::bigint)
+order by "createdAt", id
+limit $11
 `
 
-type UpdateTransferParams struct {
-	ID     int64 `json:"id"`
-	Amount int64 `json:"amount"`
+type ListUserTransfersParams struct {
+	Username       string        `json:"username"`
+	Direction      string        `json:"direction"`
+	AccountID      sql.NullInt64 `json:"account_id"`
+	CounterpartyID sql.NullInt64 `json:"counterparty_id"`
+	FromTime       sql.NullTime  `json:"from_time"`
+	ToTime         sql.NullTime  `json:"to_time"`
+	MinAmount      sql.NullInt64 `json:"min_amount"`
+	MaxAmount      sql.NullInt64 `json:"max_amount"`
+	AfterCreatedAt time.Time     `json:"after_created_at"`
+	AfterID        int64         `json:"after_id"`
+	RowLimit       int32         `json:"row_limit"`
 }
 
-func (q *Queries) UpdateTransfer(ctx context.Context, arg UpdateTransferParams) (Transfer, error) {
-	row := q.db.QueryRowContext(ctx, updateTransfer, arg.ID, arg.Amount)
-	var i Transfer
-	err := row.Scan(
-		&i.ID,
-		&i.FromAccountId,
-		&i.ToAccountId,
-		&i.Amount,
-		&i.CreatedAt,
+func (q *Queries) ListUserTransfers(ctx context.Context, arg ListUserTransfersParams) ([]Transfer, error) {
+	rows, err := q.db.QueryContext(ctx, listUserTransfers,
+		arg.Username,
+		arg.Direction,
+		arg.AccountID,
+		arg.CounterpartyID,
+		arg.FromTime,
+		arg.ToTime,
+		arg.MinAmount,
+		arg.MaxAmount,
+		arg.AfterCreatedAt,
+		arg.AfterID,
+		arg.RowLimit,
 	)
-	return i, err
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []Transfer{}
+	for rows.Next() {
+		var i Transfer
+		if err := rows.Scan(
+			&i.ID,
+			&i.FromAccountId,
+			&i.ToAccountId,
+			&i.Amount,
+			&i.CreatedAt,
+			&i.ToAmount,
+			&i.ExchangeRate,
+			&i.RateTimestamp,
+			&i.ReversedTransferId,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
 }
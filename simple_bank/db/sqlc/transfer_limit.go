@@ -0,0 +1,142 @@
+package db
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+)
+
+// names of the transfer limits, as used by LimitExceededError
+const (
+	LimitMaxTransferAmount = "maxTransferAmount"
+	LimitDailyAmount       = "dailyAmount"
+	LimitMonthlyAmount     = "monthlyAmount"
+	LimitHourlyTransfers   = "hourlyTransfers"
+)
+
+// scopes of the transfer limits, an account limit or a user limit in the account currency
+const (
+	LimitScopeAccount = "account"
+	LimitScopeUser    = "user"
+)
+
+// ErrLimitExceeded matches every LimitExceededError with errors.Is
+var ErrLimitExceeded = errors.New("transfer limit exceeded")
+
+// LimitExceededError names the limit a transfer would exceed
+type LimitExceededError struct {
+	Scope string `json:"scope"`
+	Limit string `json:"limit"`
+	Max   int64  `json:"max"`
+	// used in the window of the limit before the transfer, an amount or a number of transfers
+	Used int64 `json:"used"`
+	// the transfer amount, or 1 for the number of transfers
+	Requested int64 `json:"requested"`
+}
+
+func (err *LimitExceededError) Error() string {
+	return fmt.Sprintf("%s: %s %s limit is %d, %d used, %d requested",
+		ErrLimitExceeded, err.Scope, err.Limit, err.Max, err.Used, err.Requested)
+}
+
+func (err *LimitExceededError) Is(target error) bool {
+	return target == ErrLimitExceeded
+}
+
+// transferLimits are the limits of one scope, null fields are not limited
+type transferLimits struct {
+	maxTransferAmount sql.NullInt64
+	dailyAmount       sql.NullInt64
+	monthlyAmount     sql.NullInt64
+	hourlyTransfers   sql.NullInt32
+}
+
+// limitWindows returns the start of the day and the month in UTC and of the last hour
+func limitWindows(now time.Time) (dayStart time.Time, monthStart time.Time, hourStart time.Time) {
+	now = now.UTC()
+	dayStart = time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.UTC)
+	monthStart = time.Date(now.Year(), now.Month(), 1, 0, 0, 0, 0, time.UTC)
+	hourStart = now.Add(-time.Hour)
+	return
+}
+
+// checkTransferLimits fails with a LimitExceededError when the transfer would exceed a limit of the
+// source account or of its owner. The totals are read from the transfers in the same serializable
+// transaction that books the transfer, so concurrent transfers cannot exceed a limit together.
+func checkTransferLimits(ctx context.Context, q *Queries, fromAccount Account, amount int64, now time.Time) error {
+	dayStart, monthStart, hourStart := limitWindows(now)
+
+	accountLimit, err := q.GetAccountLimit(ctx, fromAccount.ID)
+	if err != nil && err != sql.ErrNoRows {
+		return err
+	}
+	if err == nil {
+		totals, err := q.GetAccountTransferTotals(ctx, GetAccountTransferTotalsParams{
+			DayStart:   dayStart,
+			MonthStart: monthStart,
+			HourStart:  hourStart,
+			AccountID:  fromAccount.ID,
+		})
+		if err != nil {
+			return err
+		}
+		err = exceededLimit(LimitScopeAccount, transferLimits{
+			maxTransferAmount: accountLimit.MaxTransferAmount,
+			dailyAmount:       accountLimit.DailyAmount,
+			monthlyAmount:     accountLimit.MonthlyAmount,
+			hourlyTransfers:   accountLimit.HourlyTransfers,
+		}, totals, amount)
+		if err != nil {
+			return err
+		}
+	}
+
+	userLimit, err := q.GetUserLimit(ctx, GetUserLimitParams{
+		Username: fromAccount.Username,
+		Currency: fromAccount.Currency,
+	})
+	if err == sql.ErrNoRows {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	totals, err := q.GetUserTransferTotals(ctx, GetUserTransferTotalsParams{
+		DayStart:   dayStart,
+		MonthStart: monthStart,
+		HourStart:  hourStart,
+		Username:   fromAccount.Username,
+		Currency:   fromAccount.Currency,
+	})
+	if err != nil {
+		return err
+	}
+	return exceededLimit(LimitScopeUser, transferLimits{
+		maxTransferAmount: userLimit.MaxTransferAmount,
+		dailyAmount:       userLimit.DailyAmount,
+		monthlyAmount:     userLimit.MonthlyAmount,
+		hourlyTransfers:   userLimit.HourlyTransfers,
+	}, GetAccountTransferTotalsRow(totals), amount)
+}
+
+// exceededLimit returns the first limit the transfer would exceed, or nil. The user totals have the
+// same shape as the account totals.
+func exceededLimit(scope string, limits transferLimits, totals GetAccountTransferTotalsRow, amount int64) error {
+	switch {
+	case limits.maxTransferAmount.Valid && amount > limits.maxTransferAmount.Int64:
+		return &LimitExceededError{Scope: scope, Limit: LimitMaxTransferAmount,
+			Max: limits.maxTransferAmount.Int64, Requested: amount}
+	case limits.dailyAmount.Valid && totals.DailyAmount+amount > limits.dailyAmount.Int64:
+		return &LimitExceededError{Scope: scope, Limit: LimitDailyAmount,
+			Max: limits.dailyAmount.Int64, Used: totals.DailyAmount, Requested: amount}
+	case limits.monthlyAmount.Valid && totals.MonthlyAmount+amount > limits.monthlyAmount.Int64:
+		return &LimitExceededError{Scope: scope, Limit: LimitMonthlyAmount,
+			Max: limits.monthlyAmount.Int64, Used: totals.MonthlyAmount, Requested: amount}
+	case limits.hourlyTransfers.Valid && totals.HourlyTransfers+1 > int64(limits.hourlyTransfers.Int32):
+		return &LimitExceededError{Scope: scope, Limit: LimitHourlyTransfers,
+			Max: int64(limits.hourlyTransfers.Int32), Used: totals.HourlyTransfers, Requested: 1}
+	}
+	return nil
+}
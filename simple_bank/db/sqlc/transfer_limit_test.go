@@ -0,0 +1,101 @@
+package db
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestExceededLimit(t *testing.T) {
+	limits := transferLimits{
+		maxTransferAmount: sql.NullInt64{Int64: 500, Valid: true},
+		dailyAmount:       sql.NullInt64{Int64: 1000, Valid: true},
+		monthlyAmount:     sql.NullInt64{Int64: 5000, Valid: true},
+		hourlyTransfers:   sql.NullInt32{Int32: 3, Valid: true},
+	}
+	testCases := []struct {
+		name   string
+		totals GetAccountTransferTotalsRow
+		amount int64
+		limit  string
+	}{
+		{name: "WithinLimits", totals: GetAccountTransferTotalsRow{DailyAmount: 500, MonthlyAmount: 500, HourlyTransfers: 2}, amount: 500},
+		{name: "MaxTransferAmount", amount: 501, limit: LimitMaxTransferAmount},
+		{name: "DailyAmount", totals: GetAccountTransferTotalsRow{DailyAmount: 900, MonthlyAmount: 900}, amount: 101, limit: LimitDailyAmount},
+		{name: "MonthlyAmount", totals: GetAccountTransferTotalsRow{MonthlyAmount: 4900}, amount: 101, limit: LimitMonthlyAmount},
+		{name: "HourlyTransfers", totals: GetAccountTransferTotalsRow{HourlyTransfers: 3}, amount: 1, limit: LimitHourlyTransfers},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			err := exceededLimit(LimitScopeAccount, limits, tc.totals, tc.amount)
+			if tc.limit == "" {
+				require.NoError(t, err)
+				return
+			}
+			require.ErrorIs(t, err, ErrLimitExceeded)
+			limitErr, ok := err.(*LimitExceededError)
+			require.True(t, ok)
+			require.Equal(t, tc.limit, limitErr.Limit)
+			require.Equal(t, LimitScopeAccount, limitErr.Scope)
+		})
+	}
+
+	// null limits never apply
+	err := exceededLimit(LimitScopeUser, transferLimits{}, GetAccountTransferTotalsRow{DailyAmount: 1e12}, 1e12)
+	require.NoError(t, err)
+}
+
+func TestLimitWindows(t *testing.T) {
+	now := time.Date(2024, 3, 1, 0, 30, 0, 0, time.FixedZone("CET", 3600))
+	dayStart, monthStart, hourStart := limitWindows(now)
+	require.Equal(t, time.Date(2024, 2, 29, 0, 0, 0, 0, time.UTC), dayStart)
+	require.Equal(t, time.Date(2024, 2, 1, 0, 0, 0, 0, time.UTC), monthStart)
+	require.Equal(t, time.Date(2024, 2, 29, 22, 30, 0, 0, time.UTC), hourStart)
+}
+
+func TestTransferTxLimits(t *testing.T) {
+	store := NewStore(testDB)
+	account1 := CreateRandomAccountWithCurrency(t, "USD")
+	account2 := CreateRandomAccountWithCurrency(t, "USD")
+
+	_, err := testQueries.UpsertAccountLimit(context.Background(), UpsertAccountLimitParams{
+		AccountId:   account1.ID,
+		DailyAmount: sql.NullInt64{Int64: 15, Valid: true},
+	})
+	require.NoError(t, err)
+	arg := TransferTxParams{
+		FromAccountID: account1.ID,
+		ToAccountID:   account2.ID,
+		Amount:        10,
+	}
+	_, err = store.TransferTx(context.Background(), arg)
+	require.NoError(t, err)
+	_, err = store.TransferTx(context.Background(), arg)
+	require.ErrorIs(t, err, ErrLimitExceeded)
+	var limitErr *LimitExceededError
+	require.ErrorAs(t, err, &limitErr)
+	require.Equal(t, LimitDailyAmount, limitErr.Limit)
+	require.Equal(t, int64(10), limitErr.Used)
+
+	// the user limit counts the transfers of all accounts of the user in the currency
+	_, err = testQueries.UpsertUserLimit(context.Background(), UpsertUserLimitParams{
+		Username:        account2.Username,
+		Currency:        account2.Currency,
+		HourlyTransfers: sql.NullInt32{Int32: 1, Valid: true},
+	})
+	require.NoError(t, err)
+	arg = TransferTxParams{
+		FromAccountID: account2.ID,
+		ToAccountID:   account1.ID,
+		Amount:        1,
+	}
+	_, err = store.TransferTx(context.Background(), arg)
+	require.NoError(t, err)
+	_, err = store.TransferTx(context.Background(), arg)
+	require.ErrorAs(t, err, &limitErr)
+	require.Equal(t, LimitScopeUser, limitErr.Scope)
+	require.Equal(t, LimitHourlyTransfers, limitErr.Limit)
+}
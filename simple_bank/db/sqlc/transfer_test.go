@@ -13,10 +13,13 @@ import (
 func CreateRandomTransfer(t *testing.T) Transfer {
 	account1 := CreateRandomAccount(t)
 	account2 := CreateRandomAccount(t)
+	amount := util.RandomMoney()
 	arg := CreateTransferParams{
 		FromAccountId: account1.ID,
 		ToAccountId:   account2.ID,
-		Amount:        util.RandomMoney(),
+		Amount:        amount,
+		ToAmount:      amount,
+		ExchangeRate:  "1",
 	}
 
 	transfer, err := testQueries.CreateTransfer(context.Background(), arg)
@@ -25,6 +28,8 @@ func CreateRandomTransfer(t *testing.T) Transfer {
 	require.Equal(t, arg.Amount, transfer.Amount)
 	require.Equal(t, arg.FromAccountId, transfer.FromAccountId)
 	require.Equal(t, arg.ToAccountId, transfer.ToAccountId)
+	require.Equal(t, arg.ToAmount, transfer.ToAmount)
+	require.False(t, transfer.RateTimestamp.Valid)
 	require.NotZero(t, transfer.ID)
 	require.NotZero(t, transfer.CreatedAt)
 	return transfer
@@ -46,30 +51,16 @@ func TestGetTransfer(t *testing.T) {
 	require.WithinDuration(t, transfer1.CreatedAt, transfer2.CreatedAt, time.Second)
 }
 
-func TestUpdateTransfer(t *testing.T) {
+func TestTransferIsAppendOnly(t *testing.T) {
 	transfer1 := CreateRandomTransfer(t)
-	arg := UpdateTransferParams{
-		ID:     transfer1.ID,
-		Amount: util.RandomMoney(),
-	}
-	account2, err := testQueries.UpdateTransfer(context.Background(), arg)
-	require.NoError(t, err)
-	require.NotEmpty(t, account2)
-	require.Equal(t, transfer1.ID, account2.ID)
-	require.Equal(t, transfer1.FromAccountId, account2.FromAccountId)
-	require.Equal(t, transfer1.ToAccountId, account2.ToAccountId)
-	require.Equal(t, arg.Amount, account2.Amount)
-	require.WithinDuration(t, transfer1.CreatedAt, account2.CreatedAt, time.Second)
-}
+	_, err := testDB.ExecContext(context.Background(), `update transfer set amount = amount + 1 where id = $1`, transfer1.ID)
+	require.Error(t, err)
+	_, err = testDB.ExecContext(context.Background(), `delete from transfer where id = $1`, transfer1.ID)
+	require.Error(t, err)
 
-func TestDeleleTransfer(t *testing.T) {
-	transfer1 := CreateRandomTransfer(t)
-	err := testQueries.DeleteTransfer(context.Background(), transfer1.ID)
-	require.NoError(t, err)
 	transfer2, err := testQueries.GetTransfer(context.Background(), transfer1.ID)
-	require.Error(t, err)
-	require.EqualError(t, err, sql.ErrNoRows.Error())
-	require.Empty(t, transfer2)
+	require.NoError(t, err)
+	require.Equal(t, transfer1.Amount, transfer2.Amount)
 }
 
 func TestGetTransfers(t *testing.T) {
@@ -89,3 +80,62 @@ func TestGetTransfers(t *testing.T) {
 		require.NotEmpty(t, transfer)
 	}
 }
+
+func TestListUserTransfers(t *testing.T) {
+	account1 := CreateRandomAccount(t)
+	account2 := CreateRandomAccount(t)
+	for i := 0; i < 3; i++ {
+		_, err := testQueries.CreateTransfer(context.Background(), CreateTransferParams{
+			FromAccountId: account1.ID,
+			ToAccountId:   account2.ID,
+			Amount:        10,
+			ToAmount:      10,
+			ExchangeRate:  "1",
+		})
+		require.NoError(t, err)
+		_, err = testQueries.CreateTransfer(context.Background(), CreateTransferParams{
+			FromAccountId: account2.ID,
+			ToAccountId:   account1.ID,
+			Amount:        20,
+			ToAmount:      20,
+			ExchangeRate:  "1",
+		})
+		require.NoError(t, err)
+	}
+
+	arg := ListUserTransfersParams{
+		Username: account1.Username,
+		RowLimit: 10,
+	}
+	transfers, err := testQueries.ListUserTransfers(context.Background(), arg)
+	require.NoError(t, err)
+	require.Len(t, transfers, 6)
+
+	arg.Direction = "in"
+	transfers, err = testQueries.ListUserTransfers(context.Background(), arg)
+	require.NoError(t, err)
+	require.Len(t, transfers, 3)
+	for _, transfer := range transfers {
+		require.Equal(t, account1.ID, transfer.ToAccountId)
+	}
+
+	arg.Direction = "out"
+	arg.MinAmount = sql.NullInt64{Int64: 15, Valid: true}
+	transfers, err = testQueries.ListUserTransfers(context.Background(), arg)
+	require.NoError(t, err)
+	require.Empty(t, transfers)
+
+	arg = ListUserTransfersParams{
+		Username:       account2.Username,
+		CounterpartyID: sql.NullInt64{Int64: account1.ID, Valid: true},
+		RowLimit:       4,
+	}
+	firstPage, err := testQueries.ListUserTransfers(context.Background(), arg)
+	require.NoError(t, err)
+	require.Len(t, firstPage, 4)
+	arg.AfterCreatedAt = firstPage[3].CreatedAt
+	arg.AfterID = firstPage[3].ID
+	secondPage, err := testQueries.ListUserTransfers(context.Background(), arg)
+	require.NoError(t, err)
+	require.Len(t, secondPage, 2)
+}
@@ -22,7 +22,7 @@ INSERT INTO "user" (
     "hashedPassword"
   )
 VALUES($1, $2, $3, $4, $5, $6, $7)
-RETURNING username, name1, name2, lastname1, lastname2, email, "hashedPassword", "passwordChangedAt", "createdAt"
+RETURNING username, name1, name2, lastname1, lastname2, email, "hashedPassword", "passwordChangedAt", "createdAt", role, "isEmailVerified"
 `
 
 type CreateUserParams struct {
@@ -56,6 +56,8 @@ func (q *Queries) CreateUser(ctx context.Context, arg CreateUserParams) (User, e
 		&i.HashedPassword,
 		&i.PasswordChangedAt,
 		&i.CreatedAt,
+		&i.Role,
+		&i.IsEmailVerified,
 	)
 	return i, err
 }
@@ -79,7 +81,9 @@ SELECT username,
   email,
   "hashedPassword",
   "passwordChangedAt",
-  "createdAt"
+  "createdAt",
+  role,
+  "isEmailVerified"
 FROM "user"
 WHERE username = $1 LIMIT 1
 `
@@ -97,6 +101,8 @@ func (q *Queries) GetUser(ctx context.Context, username string) (User, error) {
 		&i.HashedPassword,
 		&i.PasswordChangedAt,
 		&i.CreatedAt,
+		&i.Role,
+		&i.IsEmailVerified,
 	)
 	return i, err
 }
@@ -110,7 +116,9 @@ SELECT username,
   email,
   "hashedPassword",
   "passwordChangedAt",
-  "createdAt"
+  "createdAt",
+  role,
+  "isEmailVerified"
 FROM "user"
 WHERE email = $1 LIMIT 1
 `
@@ -128,10 +136,24 @@ func (q *Queries) GetUserByEmail(ctx context.Context, email string) (User, error
 		&i.HashedPassword,
 		&i.PasswordChangedAt,
 		&i.CreatedAt,
+		&i.Role,
+		&i.IsEmailVerified,
 	)
 	return i, err
 }
 
+const getUserPasswordChangedAt = `-- name: GetUserPasswordChangedAt :one
+SELECT "passwordChangedAt" FROM "user"
+WHERE username = $1 LIMIT 1
+`
+
+func (q *Queries) GetUserPasswordChangedAt(ctx context.Context, username string) (time.Time, error) {
+	row := q.db.QueryRowContext(ctx, getUserPasswordChangedAt, username)
+	var passwordChangedAt time.Time
+	err := row.Scan(&passwordChangedAt)
+	return passwordChangedAt, err
+}
+
 const getUsers = `-- name: GetUsers :many
 SELECT username,
   name1,
@@ -141,7 +163,9 @@ SELECT username,
   email,
   "hashedPassword",
   "passwordChangedAt",
-  "createdAt"
+  "createdAt",
+  role,
+  "isEmailVerified"
 FROM "user"
 ORDER BY username
 LIMIT $1 OFFSET $2
@@ -171,6 +195,8 @@ func (q *Queries) GetUsers(ctx context.Context, arg GetUsersParams) ([]User, err
 			&i.HashedPassword,
 			&i.PasswordChangedAt,
 			&i.CreatedAt,
+			&i.Role,
+			&i.IsEmailVerified,
 		); err != nil {
 			return nil, err
 		}
@@ -194,9 +220,10 @@ SET
   lastname2 = $5,
   email = $6,
   "hashedPassword" = $7,
-  "passwordChangedAt" = $8
+  "passwordChangedAt" = $8,
+  "isEmailVerified" = "isEmailVerified" AND email = $6
 WHERE username = $1
-RETURNING username, name1, name2, lastname1, lastname2, email, "hashedPassword", "passwordChangedAt", "createdAt"
+RETURNING username, name1, name2, lastname1, lastname2, email, "hashedPassword", "passwordChangedAt", "createdAt", role, "isEmailVerified"
 `
 
 type UpdateUserParams struct {
@@ -210,6 +237,7 @@ type UpdateUserParams struct {
 	PasswordChangedAt time.Time      `json:"passwordChangedAt"`
 }
 
+// a changed email has to be verified again
 func (q *Queries) UpdateUser(ctx context.Context, arg UpdateUserParams) (User, error) {
 	row := q.db.QueryRowContext(ctx, updateUser,
 		arg.Username,
@@ -232,6 +260,71 @@ func (q *Queries) UpdateUser(ctx context.Context, arg UpdateUserParams) (User, e
 		&i.HashedPassword,
 		&i.PasswordChangedAt,
 		&i.CreatedAt,
+		&i.Role,
+		&i.IsEmailVerified,
+	)
+	return i, err
+}
+
+const updateUserRole = `-- name: UpdateUserRole :one
+UPDATE "user"
+SET role = $2
+WHERE username = $1
+RETURNING username, name1, name2, lastname1, lastname2, email, "hashedPassword", "passwordChangedAt", "createdAt", role, "isEmailVerified"
+`
+
+type UpdateUserRoleParams struct {
+	Username string `json:"username"`
+	Role     string `json:"role"`
+}
+
+func (q *Queries) UpdateUserRole(ctx context.Context, arg UpdateUserRoleParams) (User, error) {
+	row := q.db.QueryRowContext(ctx, updateUserRole, arg.Username, arg.Role)
+	var i User
+	err := row.Scan(
+		&i.Username,
+		&i.Name1,
+		&i.Name2,
+		&i.Lastname1,
+		&i.Lastname2,
+		&i.Email,
+		&i.HashedPassword,
+		&i.PasswordChangedAt,
+		&i.CreatedAt,
+		&i.Role,
+		&i.IsEmailVerified,
+	)
+	return i, err
+}
+
+const verifyUserEmail = `-- name: VerifyUserEmail :one
+UPDATE "user"
+SET "isEmailVerified" = true
+WHERE username = $1 AND email = $2
+RETURNING username, name1, name2, lastname1, lastname2, email, "hashedPassword", "passwordChangedAt", "createdAt", role, "isEmailVerified"
+`
+
+type VerifyUserEmailParams struct {
+	Username string `json:"username"`
+	Email    string `json:"email"`
+}
+
+// only verifies the email the code was sent to, not one the user changed to since
+func (q *Queries) VerifyUserEmail(ctx context.Context, arg VerifyUserEmailParams) (User, error) {
+	row := q.db.QueryRowContext(ctx, verifyUserEmail, arg.Username, arg.Email)
+	var i User
+	err := row.Scan(
+		&i.Username,
+		&i.Name1,
+		&i.Name2,
+		&i.Lastname1,
+		&i.Lastname2,
+		&i.Email,
+		&i.HashedPassword,
+		&i.PasswordChangedAt,
+		&i.CreatedAt,
+		&i.Role,
+		&i.IsEmailVerified,
 	)
 	return i, err
 }
@@ -35,6 +35,8 @@ func CreateRandomUser(t *testing.T) User {
 	require.Equal(t, arg.HashedPassword, user.HashedPassword)
 	require.NotZero(t, user.PasswordChangedAt)
 	require.NotZero(t, user.CreatedAt)
+	require.Equal(t, util.DepositorRole, user.Role)
+	require.False(t, user.IsEmailVerified)
 	return user
 }
 
@@ -120,6 +122,16 @@ func TestGetUserByEmail(t *testing.T) {
 	require.WithinDuration(t, user1.CreatedAt, user2.CreatedAt, time.Second)
 }
 
+func TestGetUserPasswordChangedAt(t *testing.T) {
+	user1 := CreateRandomUser(t)
+	passwordChangedAt, err := testQueries.GetUserPasswordChangedAt(context.Background(), user1.Username)
+	require.NoError(t, err)
+	require.WithinDuration(t, user1.PasswordChangedAt, passwordChangedAt, time.Second)
+
+	_, err = testQueries.GetUserPasswordChangedAt(context.Background(), util.RandomUsername())
+	require.ErrorIs(t, err, sql.ErrNoRows)
+}
+
 func TestUpdateUser(t *testing.T) {
 	user1 := CreateRandomUser(t)
 	arg := UpdateUserParams{
@@ -145,6 +157,23 @@ func TestUpdateUser(t *testing.T) {
 	require.WithinDuration(t, user1.CreatedAt, user2.CreatedAt, time.Second)
 }
 
+func TestUpdateUserRole(t *testing.T) {
+	user1 := CreateRandomUser(t)
+	user2, err := testQueries.UpdateUserRole(context.Background(), UpdateUserRoleParams{
+		Username: user1.Username,
+		Role:     util.BankerRole,
+	})
+	require.NoError(t, err)
+	require.Equal(t, user1.Username, user2.Username)
+	require.Equal(t, util.BankerRole, user2.Role)
+
+	_, err = testQueries.UpdateUserRole(context.Background(), UpdateUserRoleParams{
+		Username: user1.Username,
+		Role:     "owner",
+	})
+	require.Error(t, err)
+}
+
 func TestDeleleUser(t *testing.T) {
 	user1 := CreateRandomUser(t)
 	err := testQueries.DeleteUser(context.Background(), user1.Username)
@@ -0,0 +1,209 @@
+package db
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+)
+
+// ErrEmailNotVerified is returned when a user whose email is not verified yet moves money
+var ErrEmailNotVerified = errors.New("email is not verified")
+
+// ErrInvalidVerifyEmail is returned when a verification code is wrong, used, expired or was sent to
+// an email the user changed since
+var ErrInvalidVerifyEmail = errors.New("invalid verification code")
+
+// ErrEmailAlreadyVerified is returned when a new verification code is requested for a verified email
+var ErrEmailAlreadyVerified = errors.New("email is already verified")
+
+type CreateUserTxParams struct {
+	CreateUserParams
+	// secret code of the verification created for the email of the user
+	SecretCode string `json:"secretCode"`
+	// runs in the transaction once the user is created, e.g. to enqueue the task that sends the
+	// verification email through q. An error rolls the user back.
+	AfterCreate func(q Querier, user User, verifyEmail VerifyEmail) error `json:"-"`
+}
+
+type CreateUserTxResult struct {
+	User        User        `json:"user"`
+	VerifyEmail VerifyEmail `json:"verifyEmail"`
+}
+
+// CreateUserTx creates the user together with the code that verifies its email within a transaction
+func (store *SqlStore) CreateUserTx(ctx context.Context, arg CreateUserTxParams) (CreateUserTxResult, error) {
+	var result CreateUserTxResult
+	_, err := store.execTx(ctx, sql.LevelReadCommitted, func(q *Queries) error {
+		var err error
+		result.User, err = q.CreateUser(ctx, arg.CreateUserParams)
+		if err != nil {
+			return err
+		}
+		result.VerifyEmail, err = q.CreateVerifyEmail(ctx, CreateVerifyEmailParams{
+			Username:   result.User.Username,
+			Email:      result.User.Email,
+			SecretCode: arg.SecretCode,
+		})
+		if err != nil {
+			return err
+		}
+		if arg.AfterCreate == nil {
+			return nil
+		}
+		return arg.AfterCreate(q, result.User, result.VerifyEmail)
+	})
+	return result, err
+}
+
+type VerifyEmailTxParams struct {
+	EmailId    int64  `json:"emailId"`
+	SecretCode string `json:"secretCode"`
+}
+
+type VerifyEmailTxResult struct {
+	User        User        `json:"user"`
+	VerifyEmail VerifyEmail `json:"verifyEmail"`
+}
+
+// VerifyEmailTx uses the verification code and marks the email of the user as verified within a
+// transaction, it fails with ErrInvalidVerifyEmail when the code cannot be used
+func (store *SqlStore) VerifyEmailTx(ctx context.Context, arg VerifyEmailTxParams) (VerifyEmailTxResult, error) {
+	var result VerifyEmailTxResult
+	_, err := store.execTx(ctx, sql.LevelReadCommitted, func(q *Queries) error {
+		var err error
+		result.VerifyEmail, err = q.UseVerifyEmail(ctx, UseVerifyEmailParams{
+			ID:         arg.EmailId,
+			SecretCode: arg.SecretCode,
+		})
+		if err == sql.ErrNoRows {
+			return ErrInvalidVerifyEmail
+		}
+		if err != nil {
+			return err
+		}
+		result.User, err = q.VerifyUserEmail(ctx, VerifyUserEmailParams{
+			Username: result.VerifyEmail.Username,
+			Email:    result.VerifyEmail.Email,
+		})
+		if err == sql.ErrNoRows {
+			return ErrInvalidVerifyEmail
+		}
+		return err
+	})
+	return result, err
+}
+
+type UpdateUserTxParams struct {
+	User UpdateUserParams `json:"user"`
+	// set when the password changes, the sessions of the user other than the one of AccessToken are blocked
+	BlockOtherSessions bool `json:"blockOtherSessions"`
+	// access token of the session that made the change
+	AccessToken string `json:"accessToken"`
+	// set when the email changes, a verification for the new email is created with this secret code
+	SecretCode string `json:"secretCode"`
+	// runs in the transaction once the verification of the new email is created, like
+	// CreateUserTxParams.AfterCreate. An error rolls the update back.
+	AfterEmailChange func(q Querier, user User, verifyEmail VerifyEmail) error `json:"-"`
+}
+
+type UpdateUserTxResult struct {
+	User User `json:"user"`
+	// verification of the new email, empty unless SecretCode was set
+	VerifyEmail VerifyEmail `json:"verifyEmail"`
+}
+
+// UpdateUserTx updates the user within a transaction. After a password change the other sessions of
+// the user are blocked in the same transaction, so their refresh tokens stop working together with
+// the old password. After an email change a verification for the new email is created with it.
+func (store *SqlStore) UpdateUserTx(ctx context.Context, arg UpdateUserTxParams) (UpdateUserTxResult, error) {
+	var result UpdateUserTxResult
+	_, err := store.execTx(ctx, sql.LevelReadCommitted, func(q *Queries) error {
+		var err error
+		result.User, err = q.UpdateUser(ctx, arg.User)
+		if err != nil {
+			return err
+		}
+		if arg.BlockOtherSessions {
+			err = q.BlockOtherUserSessions(ctx, BlockOtherUserSessionsParams{
+				Username:    arg.User.Username,
+				AccessToken: arg.AccessToken,
+			})
+			if err != nil {
+				return err
+			}
+		}
+		if arg.SecretCode == "" {
+			return nil
+		}
+		result.VerifyEmail, err = q.CreateVerifyEmail(ctx, CreateVerifyEmailParams{
+			Username:   result.User.Username,
+			Email:      result.User.Email,
+			SecretCode: arg.SecretCode,
+		})
+		if err != nil {
+			return err
+		}
+		if arg.AfterEmailChange == nil {
+			return nil
+		}
+		return arg.AfterEmailChange(q, result.User, result.VerifyEmail)
+	})
+	return result, err
+}
+
+type ResendVerifyEmailTxParams struct {
+	Username string `json:"username"`
+	// secret code of the new verification
+	SecretCode string `json:"secretCode"`
+	// runs in the transaction once the verification is created, like CreateUserTxParams.AfterCreate.
+	// An error rolls the verification back.
+	AfterCreate func(q Querier, user User, verifyEmail VerifyEmail) error `json:"-"`
+}
+
+type ResendVerifyEmailTxResult struct {
+	User        User        `json:"user"`
+	VerifyEmail VerifyEmail `json:"verifyEmail"`
+}
+
+// ResendVerifyEmailTx creates a new code that verifies the current email of the user within a
+// transaction, e.g. after the previous one expired. It fails with ErrEmailAlreadyVerified when
+// there is nothing to verify.
+func (store *SqlStore) ResendVerifyEmailTx(ctx context.Context, arg ResendVerifyEmailTxParams) (ResendVerifyEmailTxResult, error) {
+	var result ResendVerifyEmailTxResult
+	_, err := store.execTx(ctx, sql.LevelReadCommitted, func(q *Queries) error {
+		var err error
+		result.User, err = q.GetUser(ctx, arg.Username)
+		if err != nil {
+			return err
+		}
+		if result.User.IsEmailVerified {
+			return ErrEmailAlreadyVerified
+		}
+		result.VerifyEmail, err = q.CreateVerifyEmail(ctx, CreateVerifyEmailParams{
+			Username:   result.User.Username,
+			Email:      result.User.Email,
+			SecretCode: arg.SecretCode,
+		})
+		if err != nil {
+			return err
+		}
+		if arg.AfterCreate == nil {
+			return nil
+		}
+		return arg.AfterCreate(q, result.User, result.VerifyEmail)
+	})
+	return result, err
+}
+
+// checkEmailVerified fails with ErrEmailNotVerified unless the email of the user is verified
+func checkEmailVerified(ctx context.Context, q *Queries, username string) error {
+	user, err := q.GetUser(ctx, username)
+	if err != nil {
+		return err
+	}
+	if !user.IsEmailVerified {
+		return fmt.Errorf("%w: user %s", ErrEmailNotVerified, username)
+	}
+	return nil
+}
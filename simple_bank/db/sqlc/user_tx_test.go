@@ -0,0 +1,309 @@
+package db
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"simple_bank/util"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/require"
+)
+
+func createRandomSession(t *testing.T, username string) Session {
+	session, err := testQueries.CreateSession(context.Background(), CreateSessionParams{
+		ID:               uuid.New(),
+		Username:         username,
+		AccessToken:      util.RandomString(32),
+		AccessExpiresAt:  time.Now().Add(time.Minute),
+		RefreshToken:     util.RandomString(32),
+		RefreshExpiresAt: time.Now().Add(time.Hour),
+	})
+	require.NoError(t, err)
+	require.False(t, session.IsBlocked)
+	return session
+}
+
+func TestUpdateUserTx(t *testing.T) {
+	store := NewStore(testDB)
+	user := CreateRandomUser(t)
+	session1 := createRandomSession(t, user.Username)
+	session2 := createRandomSession(t, user.Username)
+
+	// a profile change keeps every session
+	arg := UpdateUserParams{
+		Username:          user.Username,
+		Name1:             util.RandomUsername(),
+		Name2:             user.Name2,
+		Lastname1:         user.Lastname1,
+		Lastname2:         user.Lastname2,
+		Email:             user.Email,
+		HashedPassword:    user.HashedPassword,
+		PasswordChangedAt: user.PasswordChangedAt,
+	}
+	result, err := store.UpdateUserTx(context.Background(), UpdateUserTxParams{User: arg})
+	require.NoError(t, err)
+	require.Equal(t, arg.Name1, result.User.Name1)
+	require.Empty(t, result.VerifyEmail)
+	session, err := testQueries.GetSession(context.Background(), session2.ID)
+	require.NoError(t, err)
+	require.False(t, session.IsBlocked)
+
+	// a password change blocks the other sessions
+	hashedPassword, err := util.HashPassword(util.RandomString(10))
+	require.NoError(t, err)
+	arg.HashedPassword = hashedPassword
+	arg.PasswordChangedAt = time.Now()
+	result, err = store.UpdateUserTx(context.Background(), UpdateUserTxParams{
+		User:               arg,
+		BlockOtherSessions: true,
+		AccessToken:        session1.AccessToken,
+	})
+	require.NoError(t, err)
+	require.Equal(t, hashedPassword, result.User.HashedPassword)
+	require.WithinDuration(t, arg.PasswordChangedAt, result.User.PasswordChangedAt, time.Second)
+
+	session, err = testQueries.GetSession(context.Background(), session1.ID)
+	require.NoError(t, err)
+	require.False(t, session.IsBlocked)
+	session, err = testQueries.GetSession(context.Background(), session2.ID)
+	require.NoError(t, err)
+	require.True(t, session.IsBlocked)
+}
+
+func TestUpdateUserTxEmailChange(t *testing.T) {
+	store := NewStore(testDB)
+	user := CreateRandomUser(t)
+
+	var sent VerifyEmail
+	arg := UpdateUserTxParams{
+		User: UpdateUserParams{
+			Username:          user.Username,
+			Name1:             user.Name1,
+			Name2:             user.Name2,
+			Lastname1:         user.Lastname1,
+			Lastname2:         user.Lastname2,
+			Email:             util.RandomEmail(),
+			HashedPassword:    user.HashedPassword,
+			PasswordChangedAt: user.PasswordChangedAt,
+		},
+		SecretCode: util.RandomString(32),
+		AfterEmailChange: func(q Querier, user User, verifyEmail VerifyEmail) error {
+			sent = verifyEmail
+			return nil
+		},
+	}
+	result, err := store.UpdateUserTx(context.Background(), arg)
+	require.NoError(t, err)
+	require.Equal(t, arg.User.Email, result.User.Email)
+	require.False(t, result.User.IsEmailVerified)
+	require.Equal(t, result.VerifyEmail, sent)
+	require.Equal(t, user.Username, sent.Username)
+	require.Equal(t, arg.User.Email, sent.Email)
+	require.Equal(t, arg.SecretCode, sent.SecretCode)
+
+	// the new email can be verified with the code
+	verified, err := store.VerifyEmailTx(context.Background(), VerifyEmailTxParams{
+		EmailId:    sent.ID,
+		SecretCode: sent.SecretCode,
+	})
+	require.NoError(t, err)
+	require.True(t, verified.User.IsEmailVerified)
+}
+
+func TestCreateUserTx(t *testing.T) {
+	store := NewStore(testDB)
+	arg := CreateUserTxParams{
+		CreateUserParams: CreateUserParams{
+			Username:       util.RandomUsername(),
+			Name1:          util.RandomUsername(),
+			Lastname1:      util.RandomUsername(),
+			Email:          util.RandomEmail(),
+			HashedPassword: util.RandomString(32),
+		},
+		SecretCode: util.RandomString(32),
+	}
+
+	// a failing AfterCreate rolls back the user and the tasks it enqueued
+	var task Task
+	arg.AfterCreate = func(q Querier, user User, verifyEmail VerifyEmail) error {
+		var err error
+		task, err = q.CreateTask(context.Background(), CreateTaskParams{
+			Type:        "task:test",
+			Payload:     []byte(`{}`),
+			MaxAttempts: 1,
+		})
+		require.NoError(t, err)
+		return errors.New("cannot enqueue task")
+	}
+	_, err := store.CreateUserTx(context.Background(), arg)
+	require.Error(t, err)
+	_, err = testQueries.GetUser(context.Background(), arg.Username)
+	require.ErrorIs(t, err, sql.ErrNoRows)
+	_, err = testQueries.GetTask(context.Background(), task.ID)
+	require.ErrorIs(t, err, sql.ErrNoRows)
+
+	var sent VerifyEmail
+	arg.AfterCreate = func(q Querier, user User, verifyEmail VerifyEmail) error {
+		sent = verifyEmail
+		return nil
+	}
+	result, err := store.CreateUserTx(context.Background(), arg)
+	require.NoError(t, err)
+	require.Equal(t, arg.Username, result.User.Username)
+	require.False(t, result.User.IsEmailVerified)
+	require.Equal(t, result.VerifyEmail, sent)
+	require.Equal(t, arg.Username, sent.Username)
+	require.Equal(t, arg.Email, sent.Email)
+	require.Equal(t, arg.SecretCode, sent.SecretCode)
+	require.False(t, sent.IsUsed)
+	require.True(t, sent.ExpiredAt.After(sent.CreatedAt))
+}
+
+func TestVerifyEmailTx(t *testing.T) {
+	store := NewStore(testDB)
+	user := CreateRandomUser(t)
+	verifyEmail, err := testQueries.CreateVerifyEmail(context.Background(), CreateVerifyEmailParams{
+		Username:   user.Username,
+		Email:      user.Email,
+		SecretCode: util.RandomString(32),
+	})
+	require.NoError(t, err)
+
+	_, err = store.VerifyEmailTx(context.Background(), VerifyEmailTxParams{
+		EmailId:    verifyEmail.ID,
+		SecretCode: util.RandomString(32),
+	})
+	require.ErrorIs(t, err, ErrInvalidVerifyEmail)
+
+	result, err := store.VerifyEmailTx(context.Background(), VerifyEmailTxParams{
+		EmailId:    verifyEmail.ID,
+		SecretCode: verifyEmail.SecretCode,
+	})
+	require.NoError(t, err)
+	require.True(t, result.User.IsEmailVerified)
+	require.True(t, result.VerifyEmail.IsUsed)
+
+	// a code is used only once
+	_, err = store.VerifyEmailTx(context.Background(), VerifyEmailTxParams{
+		EmailId:    verifyEmail.ID,
+		SecretCode: verifyEmail.SecretCode,
+	})
+	require.ErrorIs(t, err, ErrInvalidVerifyEmail)
+}
+
+func TestVerifyEmailTxChangedEmail(t *testing.T) {
+	store := NewStore(testDB)
+	user := CreateRandomUser(t)
+	verifyEmail, err := testQueries.CreateVerifyEmail(context.Background(), CreateVerifyEmailParams{
+		Username:   user.Username,
+		Email:      user.Email,
+		SecretCode: util.RandomString(32),
+	})
+	require.NoError(t, err)
+
+	_, err = testQueries.UpdateUser(context.Background(), UpdateUserParams{
+		Username:          user.Username,
+		Name1:             user.Name1,
+		Name2:             user.Name2,
+		Lastname1:         user.Lastname1,
+		Lastname2:         user.Lastname2,
+		Email:             util.RandomEmail(),
+		HashedPassword:    user.HashedPassword,
+		PasswordChangedAt: user.PasswordChangedAt,
+	})
+	require.NoError(t, err)
+
+	// the code was sent to the old email
+	_, err = store.VerifyEmailTx(context.Background(), VerifyEmailTxParams{
+		EmailId:    verifyEmail.ID,
+		SecretCode: verifyEmail.SecretCode,
+	})
+	require.ErrorIs(t, err, ErrInvalidVerifyEmail)
+	user, err = testQueries.GetUser(context.Background(), user.Username)
+	require.NoError(t, err)
+	require.False(t, user.IsEmailVerified)
+}
+
+func TestResendVerifyEmailTx(t *testing.T) {
+	store := NewStore(testDB)
+	arg := CreateUserTxParams{
+		CreateUserParams: CreateUserParams{
+			Username:       util.RandomUsername(),
+			Name1:          util.RandomUsername(),
+			Lastname1:      util.RandomUsername(),
+			Email:          util.RandomEmail(),
+			HashedPassword: util.RandomString(32),
+		},
+		SecretCode: util.RandomString(32),
+	}
+	created, err := store.CreateUserTx(context.Background(), arg)
+	require.NoError(t, err)
+
+	var sent VerifyEmail
+	result, err := store.ResendVerifyEmailTx(context.Background(), ResendVerifyEmailTxParams{
+		Username:   arg.Username,
+		SecretCode: util.RandomString(32),
+		AfterCreate: func(q Querier, user User, verifyEmail VerifyEmail) error {
+			sent = verifyEmail
+			return nil
+		},
+	})
+	require.NoError(t, err)
+	require.Equal(t, arg.Username, result.User.Username)
+	require.Equal(t, result.VerifyEmail, sent)
+	require.NotEqual(t, created.VerifyEmail.ID, result.VerifyEmail.ID)
+	require.Equal(t, arg.Email, result.VerifyEmail.Email)
+	verifyEmail := result.VerifyEmail
+
+	_, err = store.VerifyEmailTx(context.Background(), VerifyEmailTxParams{
+		EmailId:    verifyEmail.ID,
+		SecretCode: verifyEmail.SecretCode,
+	})
+	require.NoError(t, err)
+
+	// a verified email needs no new code
+	_, err = store.ResendVerifyEmailTx(context.Background(), ResendVerifyEmailTxParams{
+		Username:   arg.Username,
+		SecretCode: util.RandomString(32),
+	})
+	require.ErrorIs(t, err, ErrEmailAlreadyVerified)
+}
+
+func TestTransferTxEmailNotVerified(t *testing.T) {
+	store := NewStore(testDB)
+	account1 := CreateRandomAccount(t)
+	account2 := CreateRandomAccount(t)
+
+	// changing the email resets its verification
+	user, err := testQueries.GetUser(context.Background(), account1.Username)
+	require.NoError(t, err)
+	_, err = testQueries.UpdateUser(context.Background(), UpdateUserParams{
+		Username:          user.Username,
+		Name1:             user.Name1,
+		Name2:             user.Name2,
+		Lastname1:         user.Lastname1,
+		Lastname2:         user.Lastname2,
+		Email:             util.RandomEmail(),
+		HashedPassword:    user.HashedPassword,
+		PasswordChangedAt: user.PasswordChangedAt,
+	})
+	require.NoError(t, err)
+
+	_, err = store.TransferTx(context.Background(), TransferTxParams{
+		FromAccountID: account1.ID,
+		ToAccountID:   account2.ID,
+		Amount:        1,
+	})
+	require.ErrorIs(t, err, ErrEmailNotVerified)
+
+	// receiving money does not need a verified email
+	_, err = store.TransferTx(context.Background(), TransferTxParams{
+		FromAccountID: account2.ID,
+		ToAccountID:   account1.ID,
+		Amount:        1,
+	})
+	require.NoError(t, err)
+}
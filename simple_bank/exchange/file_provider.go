@@ -0,0 +1,65 @@
+package exchange
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+)
+
+type currencyPair struct {
+	from string
+	to   string
+}
+
+// FileProvider serves exchange rates loaded from a JSON file, so transfers work without any external service
+type FileProvider struct {
+	rates map[currencyPair]Rate
+}
+
+// NewFileProvider loads a JSON array of rates. An empty path yields a provider without any rate.
+func NewFileProvider(path string) (ExchangeRateProvider, error) {
+	var rates []Rate
+	if path != "" {
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return nil, fmt.Errorf("cannot read exchange rates file: %w", err)
+		}
+		err = json.Unmarshal(data, &rates)
+		if err != nil {
+			return nil, fmt.Errorf("cannot parse exchange rates file: %w", err)
+		}
+	}
+	return NewStaticProvider(rates...)
+}
+
+// NewStaticProvider serves a fixed set of rates
+func NewStaticProvider(rates ...Rate) (ExchangeRateProvider, error) {
+	provider := &FileProvider{
+		rates: make(map[currencyPair]Rate, len(rates)),
+	}
+	for _, rate := range rates {
+		if _, err := parseRate(rate.Rate); err != nil {
+			return nil, fmt.Errorf("rate %s to %s: %w", rate.From, rate.To, err)
+		}
+		provider.rates[currencyPair{rate.From, rate.To}] = rate
+	}
+	return provider, nil
+}
+
+// GetRate falls back to the inverse of the opposite rate when only that one is configured
+func (provider *FileProvider) GetRate(ctx context.Context, from string, to string) (Rate, error) {
+	if rate, ok := provider.rates[currencyPair{from, to}]; ok {
+		return rate, nil
+	}
+	if rate, ok := provider.rates[currencyPair{to, from}]; ok {
+		inverse, _ := InverseRate(rate.Rate)
+		return Rate{
+			From:      from,
+			To:        to,
+			Rate:      inverse,
+			UpdatedAt: rate.UpdatedAt,
+		}, nil
+	}
+	return Rate{}, fmt.Errorf("%w: %s to %s", ErrRateNotFound, from, to)
+}
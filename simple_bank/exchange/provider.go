@@ -0,0 +1,80 @@
+package exchange
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/big"
+	"time"
+)
+
+var (
+	ErrRateNotFound   = errors.New("exchange rate not found")
+	ErrInvalidRate    = errors.New("exchange rate must be a positive decimal number")
+	ErrAmountTooSmall = errors.New("amount is too small to be converted")
+)
+
+// Rate is how many units of the To currency one unit of the From currency buys, as a decimal string
+type Rate struct {
+	From      string    `json:"from"`
+	To        string    `json:"to"`
+	Rate      string    `json:"rate"`
+	UpdatedAt time.Time `json:"updatedAt"`
+}
+
+// ExchangeRateProvider is an interface that looks up the rate to convert between two currencies
+type ExchangeRateProvider interface {
+	// GetRate returns the current rate from one currency to another, or ErrRateNotFound
+	GetRate(ctx context.Context, from string, to string) (Rate, error)
+}
+
+// Convert applies the rate to an amount in minor units of the source currency and returns it in minor units
+// of the target currency, rounding half away from zero. Rates are quoted per major unit, so the result is
+// rescaled by the difference between the minor unit exponents of both currencies.
+func Convert(amount int64, rate string, fromMinorUnit int, toMinorUnit int) (int64, error) {
+	r, err := parseRate(rate)
+	if err != nil {
+		return 0, err
+	}
+	converted := new(big.Rat).Mul(new(big.Rat).SetInt64(amount), r)
+	if toMinorUnit != fromMinorUnit {
+		exponent := toMinorUnit - fromMinorUnit
+		if exponent < 0 {
+			exponent = -exponent
+		}
+		scale := new(big.Rat).SetInt(new(big.Int).Exp(big.NewInt(10), big.NewInt(int64(exponent)), nil))
+		if toMinorUnit > fromMinorUnit {
+			converted.Mul(converted, scale)
+		} else {
+			converted.Quo(converted, scale)
+		}
+	}
+	// round half away from zero: trunc(x + sign(x)/2)
+	half := big.NewRat(int64(converted.Sign()), 2)
+	converted.Add(converted, half)
+	result := new(big.Int).Quo(converted.Num(), converted.Denom())
+	if !result.IsInt64() {
+		return 0, fmt.Errorf("converted amount overflows: %s", result)
+	}
+	if amount != 0 && result.Sign() == 0 {
+		return 0, ErrAmountTooSmall
+	}
+	return result.Int64(), nil
+}
+
+// InverseRate returns the rate converting back from the To currency to the From currency
+func InverseRate(rate string) (string, error) {
+	r, err := parseRate(rate)
+	if err != nil {
+		return "", err
+	}
+	return r.Inv(r).FloatString(10), nil
+}
+
+func parseRate(rate string) (*big.Rat, error) {
+	r, ok := new(big.Rat).SetString(rate)
+	if !ok || r.Sign() <= 0 {
+		return nil, ErrInvalidRate
+	}
+	return r, nil
+}
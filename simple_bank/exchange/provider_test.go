@@ -0,0 +1,86 @@
+package exchange
+
+import (
+	"context"
+	"math"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestConvert(t *testing.T) {
+	testCases := []struct {
+		amount        int64
+		rate          string
+		fromMinorUnit int
+		toMinorUnit   int
+		expected      int64
+	}{
+		{amount: 100, rate: "0.92", fromMinorUnit: 2, toMinorUnit: 2, expected: 92},
+		{amount: 1, rate: "1.5", fromMinorUnit: 2, toMinorUnit: 2, expected: 2},
+		{amount: -1, rate: "1.5", fromMinorUnit: 2, toMinorUnit: 2, expected: -2},
+		{amount: 333, rate: "0.3333333333", fromMinorUnit: 2, toMinorUnit: 2, expected: 111},
+		{amount: 1000, rate: "149.5", fromMinorUnit: 0, toMinorUnit: 0, expected: 149500},
+		{amount: 1000, rate: "149.5", fromMinorUnit: 2, toMinorUnit: 0, expected: 1495},
+		{amount: 1495, rate: "0.0066889632", fromMinorUnit: 0, toMinorUnit: 2, expected: 1000},
+	}
+	for _, tc := range testCases {
+		converted, err := Convert(tc.amount, tc.rate, tc.fromMinorUnit, tc.toMinorUnit)
+		require.NoError(t, err)
+		require.Equal(t, tc.expected, converted)
+	}
+
+	_, err := Convert(1, "0.1", 2, 2)
+	require.ErrorIs(t, err, ErrAmountTooSmall)
+	_, err = Convert(1, "0.95", 2, 0)
+	require.ErrorIs(t, err, ErrAmountTooSmall)
+	_, err = Convert(1, "-1", 2, 2)
+	require.ErrorIs(t, err, ErrInvalidRate)
+	_, err = Convert(1, "abc", 2, 2)
+	require.ErrorIs(t, err, ErrInvalidRate)
+	_, err = Convert(math.MaxInt64, "2", 2, 2)
+	require.Error(t, err)
+}
+
+func TestInverseRate(t *testing.T) {
+	inverse, err := InverseRate("0.8")
+	require.NoError(t, err)
+	require.Equal(t, "1.2500000000", inverse)
+
+	_, err = InverseRate("0")
+	require.ErrorIs(t, err, ErrInvalidRate)
+}
+
+func TestFileProvider(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "rates.json")
+	err := os.WriteFile(path, []byte(`[{"from":"USD","to":"EUR","rate":"0.8","updatedAt":"2023-10-01T00:00:00Z"}]`), 0o600)
+	require.NoError(t, err)
+
+	provider, err := NewFileProvider(path)
+	require.NoError(t, err)
+
+	rate, err := provider.GetRate(context.Background(), "USD", "EUR")
+	require.NoError(t, err)
+	require.Equal(t, "0.8", rate.Rate)
+	require.Equal(t, time.Date(2023, 10, 1, 0, 0, 0, 0, time.UTC), rate.UpdatedAt)
+
+	rate, err = provider.GetRate(context.Background(), "EUR", "USD")
+	require.NoError(t, err)
+	require.Equal(t, "EUR", rate.From)
+	require.Equal(t, "USD", rate.To)
+	require.Equal(t, "1.2500000000", rate.Rate)
+
+	_, err = provider.GetRate(context.Background(), "USD", "CAD")
+	require.ErrorIs(t, err, ErrRateNotFound)
+
+	_, err = NewStaticProvider(Rate{From: "USD", To: "EUR", Rate: "0"})
+	require.ErrorIs(t, err, ErrInvalidRate)
+
+	provider, err = NewFileProvider("")
+	require.NoError(t, err)
+	_, err = provider.GetRate(context.Background(), "USD", "EUR")
+	require.ErrorIs(t, err, ErrRateNotFound)
+}
@@ -0,0 +1,114 @@
+package grpcapi
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	db "simple_bank/db/sqlc"
+	"simple_bank/token"
+	"strings"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+const (
+	authorizationHeaderKey  = "authorization"
+	authorizationTypeBearer = "bearer"
+)
+
+type authorizationPayloadKey struct{}
+
+// authenticate verifies the bearer access token sent in the request metadata. Like the HTTP auth
+// middleware, it rejects tokens issued before the last password change of the user.
+func (server *Server) authenticate(ctx context.Context) (*token.Payload, error) {
+	accessToken, err := extractAccessToken(ctx)
+	if err != nil {
+		return nil, err
+	}
+	payload, err := server.tokenMaker.VerifyToken(accessToken)
+	if err != nil {
+		return nil, fmt.Errorf("invalid access token: %w", err)
+	}
+	passwordChangedAt, err := server.store.GetUserPasswordChangedAt(ctx, payload.Username)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, fmt.Errorf("user of the access token not found")
+		}
+		return nil, fmt.Errorf("failed to check access token: %w", err)
+	}
+	if payload.IssuedAt.Before(passwordChangedAt) {
+		return nil, fmt.Errorf("access token was issued before the last password change")
+	}
+	return payload, nil
+}
+
+// extractAccessToken returns the bearer access token sent in the request metadata, without verifying it
+func extractAccessToken(ctx context.Context) (string, error) {
+	md, ok := metadata.FromIncomingContext(ctx)
+	if !ok {
+		return "", fmt.Errorf("missing metadata")
+	}
+	values := md.Get(authorizationHeaderKey)
+	if len(values) == 0 {
+		return "", fmt.Errorf("missing authorization header")
+	}
+	fields := strings.Fields(values[0])
+	if len(fields) < 2 {
+		return "", fmt.Errorf("invalid authorization header format")
+	}
+	authorizationType := strings.ToLower(fields[0])
+	if authorizationType != authorizationTypeBearer {
+		return "", fmt.Errorf("unsupported authorization type: %s", authorizationType)
+	}
+	return fields[1], nil
+}
+
+// authorizeUser returns the payload the auth interceptor stored in the context.
+// Calls that do not go through the interceptors, like the ones coming from the
+// in-process HTTP gateway, get their access token verified here instead.
+func (server *Server) authorizeUser(ctx context.Context) (*token.Payload, error) {
+	if payload, ok := ctx.Value(authorizationPayloadKey{}).(*token.Payload); ok && payload != nil {
+		return payload, nil
+	}
+	return server.authenticate(ctx)
+}
+
+// authorizeRoles authorizes the user like authorizeUser and checks the user has one of the roles.
+// It returns the status error to send back.
+func (server *Server) authorizeRoles(ctx context.Context, roles ...string) (*token.Payload, error) {
+	payload, err := server.authorizeUser(ctx)
+	if err != nil {
+		return nil, unauthenticatedError(err)
+	}
+	if !hasRole(payload, roles...) {
+		return nil, status.Errorf(codes.PermissionDenied, "role %s is not allowed to call this method", payload.Role)
+	}
+	return payload, nil
+}
+
+func hasRole(payload *token.Payload, roles ...string) bool {
+	for _, role := range roles {
+		if payload.Role == role {
+			return true
+		}
+	}
+	return false
+}
+
+// authorizedAccount gets the account and checks the authenticated user can access it: owners can
+// access their accounts, users with one of the staff roles any account
+func (server *Server) authorizedAccount(ctx context.Context, accountID int64, authPayload *token.Payload, staffRoles ...string) (db.Account, error) {
+	account, err := server.store.GetAccount(ctx, accountID)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return account, status.Errorf(codes.NotFound, "account not found: %v", err)
+		}
+		return account, status.Errorf(codes.Internal, "failed to get account: %v", err)
+	}
+	if account.Username != authPayload.Username && !hasRole(authPayload, staffRoles...) {
+		return account, status.Errorf(codes.PermissionDenied, "account doesn't belong to the authenticated user")
+	}
+	return account, nil
+}
@@ -1,9 +1,11 @@
 package grpcapi
 
 import (
+	"database/sql"
 	db "simple_bank/db/sqlc"
 	"simple_bank/pb"
 	util "simple_bank/util"
+	"time"
 
 	"google.golang.org/protobuf/types/known/timestamppb"
 )
@@ -16,7 +18,155 @@ func convertUser(user db.User) *pb.User {
 		Lastname1:         user.Lastname1,
 		Lastname2:         util.SqlNullStringToString(user.Lastname2),
 		Email:             user.Email,
+		Role:              user.Role,
+		IsEmailVerified:   user.IsEmailVerified,
 		PasswordChangedAt: timestamppb.New(user.PasswordChangedAt),
 		CreatedAt:         timestamppb.New(user.CreatedAt),
 	}
 }
+
+func convertCurrency(currency db.Currency) *pb.Currency {
+	return &pb.Currency{
+		Code:      currency.Code,
+		MinorUnit: int32(currency.MinorUnit),
+		Symbol:    currency.Symbol,
+		Enabled:   currency.Enabled,
+		CreatedAt: timestamppb.New(currency.CreatedAt),
+	}
+}
+
+func convertAccount(account db.Account) *pb.Account {
+	return &pb.Account{
+		Id:               account.ID,
+		Username:         account.Username,
+		Currency:         account.Currency,
+		Balance:          account.Balance,
+		CreatedAt:        timestamppb.New(account.CreatedAt),
+		FormattedBalance: util.FormatAmount(account.Balance, account.Currency),
+		Status:           account.Status,
+	}
+}
+
+func convertEntry(entry db.Entry) *pb.Entry {
+	return &pb.Entry{
+		Id:                entry.ID,
+		AccountId:         entry.AccountId,
+		Amount:            entry.Amount,
+		CreatedAt:         timestamppb.New(entry.CreatedAt),
+		Reason:            entry.Reason,
+		ExternalReference: entry.ExternalReference,
+		TransferId:        entry.TransferId.Int64,
+	}
+}
+
+func convertTransfer(transfer db.Transfer) *pb.Transfer {
+	pbTransfer := &pb.Transfer{
+		Id:                 transfer.ID,
+		FromAccountId:      transfer.FromAccountId,
+		ToAccountId:        transfer.ToAccountId,
+		Amount:             transfer.Amount,
+		CreatedAt:          timestamppb.New(transfer.CreatedAt),
+		ToAmount:           transfer.ToAmount,
+		ExchangeRate:       transfer.ExchangeRate,
+		ReversedTransferId: transfer.ReversedTransferId.Int64,
+	}
+	if transfer.RateTimestamp.Valid {
+		pbTransfer.RateTimestamp = timestamppb.New(transfer.RateTimestamp.Time)
+	}
+	return pbTransfer
+}
+
+func convertScheduledTransfer(scheduledTransfer db.ScheduledTransfer) *pb.ScheduledTransfer {
+	return &pb.ScheduledTransfer{
+		Id:            scheduledTransfer.ID,
+		Username:      scheduledTransfer.Username,
+		FromAccountId: scheduledTransfer.FromAccountId,
+		ToAccountId:   scheduledTransfer.ToAccountId,
+		Amount:        scheduledTransfer.Amount,
+		Schedule:      scheduledTransfer.Schedule,
+		NextRunAt:     timestamppb.New(scheduledTransfer.NextRunAt),
+		Status:        scheduledTransfer.Status,
+		Attempts:      scheduledTransfer.Attempts,
+		CreatedAt:     timestamppb.New(scheduledTransfer.CreatedAt),
+	}
+}
+
+func convertScheduledTransferRun(run db.ScheduledTransferRun) *pb.ScheduledTransferRun {
+	return &pb.ScheduledTransferRun{
+		Id:                  run.ID,
+		ScheduledTransferId: run.ScheduledTransferId,
+		ScheduledFor:        timestamppb.New(run.ScheduledFor),
+		Attempt:             run.Attempt,
+		Status:              run.Status,
+		TransferId:          run.TransferId.Int64,
+		Error:               run.Error,
+		CreatedAt:           timestamppb.New(run.CreatedAt),
+	}
+}
+
+func convertStatementEntry(entry db.ListAccountEntriesRow) *pb.StatementEntry {
+	return &pb.StatementEntry{
+		Id:             entry.ID,
+		AccountId:      entry.AccountId,
+		Amount:         entry.Amount,
+		RunningBalance: entry.RunningBalance,
+		CreatedAt:      timestamppb.New(entry.CreatedAt),
+	}
+}
+
+func convertAccountLimit(limit db.AccountLimit) *pb.TransferLimits {
+	return &pb.TransferLimits{
+		MaxTransferAmount: convertNullInt64(limit.MaxTransferAmount),
+		DailyAmount:       convertNullInt64(limit.DailyAmount),
+		MonthlyAmount:     convertNullInt64(limit.MonthlyAmount),
+		HourlyTransfers:   convertNullInt32(limit.HourlyTransfers),
+		UpdatedAt:         timestamppb.New(limit.UpdatedAt),
+	}
+}
+
+func convertUserLimit(limit db.UserLimit) *pb.TransferLimits {
+	return &pb.TransferLimits{
+		MaxTransferAmount: convertNullInt64(limit.MaxTransferAmount),
+		DailyAmount:       convertNullInt64(limit.DailyAmount),
+		MonthlyAmount:     convertNullInt64(limit.MonthlyAmount),
+		HourlyTransfers:   convertNullInt32(limit.HourlyTransfers),
+		UpdatedAt:         timestamppb.New(limit.UpdatedAt),
+	}
+}
+
+func convertNullInt64(value sql.NullInt64) *int64 {
+	if !value.Valid {
+		return nil
+	}
+	return &value.Int64
+}
+
+func convertNullInt32(value sql.NullInt32) *int32 {
+	if !value.Valid {
+		return nil
+	}
+	return &value.Int32
+}
+
+// convertOptionalInt64 maps an unset optional field to null
+func convertOptionalInt64(value *int64) sql.NullInt64 {
+	if value == nil {
+		return sql.NullInt64{}
+	}
+	return sql.NullInt64{Int64: *value, Valid: true}
+}
+
+func convertOptionalInt32(value *int32) sql.NullInt32 {
+	if value == nil {
+		return sql.NullInt32{}
+	}
+	return sql.NullInt32{Int32: *value, Valid: true}
+}
+
+// convertOptionalTimestamp maps an unset timestamp to the zero time instead of the unix epoch
+func convertOptionalTimestamp(timestamp *timestamppb.Timestamp) time.Time {
+	if timestamp == nil {
+		return time.Time{}
+	}
+	return timestamp.AsTime()
+}
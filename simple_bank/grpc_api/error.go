@@ -1,6 +1,9 @@
 package grpcapi
 
 import (
+	"fmt"
+	db "simple_bank/db/sqlc"
+
 	"google.golang.org/genproto/googleapis/rpc/errdetails"
 	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/status"
@@ -22,3 +25,28 @@ func invalidArgumentError(violations []*errdetails.BadRequest_FieldViolation) er
 	}
 	return details.Err()
 }
+
+func unauthenticatedError(err error) error {
+	return status.Errorf(codes.Unauthenticated, "unauthorized: %s", err)
+}
+
+// limitExceededError names the exceeded transfer limit in the error details
+func limitExceededError(err *db.LimitExceededError) error {
+	errorInfo := &errdetails.ErrorInfo{
+		Reason: "TRANSFER_LIMIT_EXCEEDED",
+		Domain: "simple_bank",
+		Metadata: map[string]string{
+			"scope":     err.Scope,
+			"limit":     err.Limit,
+			"max":       fmt.Sprint(err.Max),
+			"used":      fmt.Sprint(err.Used),
+			"requested": fmt.Sprint(err.Requested),
+		},
+	}
+	status := status.New(codes.ResourceExhausted, err.Error())
+	details, detailsErr := status.WithDetails(errorInfo)
+	if detailsErr != nil {
+		return status.Err()
+	}
+	return details.Err()
+}
@@ -0,0 +1,69 @@
+package grpcapi
+
+import (
+	"context"
+	"strings"
+
+	"google.golang.org/grpc"
+)
+
+// publicMethods lists the RPCs that can be called without an access token
+var publicMethods = map[string]bool{
+	"/pb.SimpleBank/LoginUser":        true,
+	"/pb.SimpleBank/CreateUser":       true,
+	"/pb.SimpleBank/RenewAccessToken": true,
+	"/pb.SimpleBank/VerifyEmail":      true,
+}
+
+// requiresAuthentication tells whether an access token is needed to call the method. Only the
+// SimpleBank service is protected, so services like server reflection stay open to tools like evans.
+func requiresAuthentication(fullMethod string) bool {
+	return strings.HasPrefix(fullMethod, "/pb.SimpleBank/") && !publicMethods[fullMethod]
+}
+
+// UnaryAuthInterceptor verifies the access token of every non public SimpleBank unary RPC
+// and stores its payload in the context passed to the handler.
+func (server *Server) UnaryAuthInterceptor(
+	ctx context.Context,
+	req interface{},
+	info *grpc.UnaryServerInfo,
+	handler grpc.UnaryHandler,
+) (interface{}, error) {
+	if !requiresAuthentication(info.FullMethod) {
+		return handler(ctx, req)
+	}
+	payload, err := server.authenticate(ctx)
+	if err != nil {
+		return nil, unauthenticatedError(err)
+	}
+	return handler(context.WithValue(ctx, authorizationPayloadKey{}, payload), req)
+}
+
+// StreamAuthInterceptor does the same as UnaryAuthInterceptor for streaming RPCs.
+func (server *Server) StreamAuthInterceptor(
+	srv interface{},
+	stream grpc.ServerStream,
+	info *grpc.StreamServerInfo,
+	handler grpc.StreamHandler,
+) error {
+	if !requiresAuthentication(info.FullMethod) {
+		return handler(srv, stream)
+	}
+	payload, err := server.authenticate(stream.Context())
+	if err != nil {
+		return unauthenticatedError(err)
+	}
+	return handler(srv, &authenticatedStream{
+		ServerStream: stream,
+		ctx:          context.WithValue(stream.Context(), authorizationPayloadKey{}, payload),
+	})
+}
+
+type authenticatedStream struct {
+	grpc.ServerStream
+	ctx context.Context
+}
+
+func (stream *authenticatedStream) Context() context.Context {
+	return stream.ctx
+}
@@ -0,0 +1,222 @@
+package grpcapi
+
+import (
+	"context"
+	"database/sql"
+	mockdb "simple_bank/db/mock"
+	"simple_bank/token"
+	"simple_bank/util"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+)
+
+func TestUnaryAuthInterceptor(t *testing.T) {
+	testCases := []struct {
+		name          string
+		method        string
+		setupContext  func(t *testing.T, tokenMaker token.Maker) context.Context
+		buildStubs    func(store *mockdb.MockStore)
+		checkResponse func(t *testing.T, payload *token.Payload, err error)
+	}{
+		{
+			name:   "OK",
+			method: "/pb.SimpleBank/GetAccount",
+			setupContext: func(t *testing.T, tokenMaker token.Maker) context.Context {
+				return newContextWithBearerToken(t, tokenMaker, "username", util.DepositorRole, time.Minute)
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					GetUserPasswordChangedAt(gomock.Any(), gomock.Eq("username")).
+					Times(1).
+					Return(time.Now().Add(-time.Hour), nil)
+			},
+			checkResponse: func(t *testing.T, payload *token.Payload, err error) {
+				require.NoError(t, err)
+				require.NotNil(t, payload)
+				require.Equal(t, "username", payload.Username)
+				require.Equal(t, util.DepositorRole, payload.Role)
+			},
+		},
+		{
+			name:   "PublicMethod",
+			method: "/pb.SimpleBank/LoginUser",
+			setupContext: func(t *testing.T, tokenMaker token.Maker) context.Context {
+				return context.Background()
+			},
+			buildStubs: func(store *mockdb.MockStore) {},
+			checkResponse: func(t *testing.T, payload *token.Payload, err error) {
+				require.NoError(t, err)
+				require.Nil(t, payload)
+			},
+		},
+		{
+			name:   "NoMetadata",
+			method: "/pb.SimpleBank/GetAccount",
+			setupContext: func(t *testing.T, tokenMaker token.Maker) context.Context {
+				return context.Background()
+			},
+			buildStubs: func(store *mockdb.MockStore) {},
+			checkResponse: func(t *testing.T, payload *token.Payload, err error) {
+				requireStatusCode(t, err, codes.Unauthenticated)
+			},
+		},
+		{
+			name:   "NoAuthorization",
+			method: "/pb.SimpleBank/GetAccount",
+			setupContext: func(t *testing.T, tokenMaker token.Maker) context.Context {
+				return metadata.NewIncomingContext(context.Background(), metadata.MD{})
+			},
+			buildStubs: func(store *mockdb.MockStore) {},
+			checkResponse: func(t *testing.T, payload *token.Payload, err error) {
+				requireStatusCode(t, err, codes.Unauthenticated)
+			},
+		},
+		{
+			name:   "InvalidAuthorizationFormat",
+			method: "/pb.SimpleBank/GetAccount",
+			setupContext: func(t *testing.T, tokenMaker token.Maker) context.Context {
+				md := metadata.Pairs(authorizationHeaderKey, "bearer")
+				return metadata.NewIncomingContext(context.Background(), md)
+			},
+			buildStubs: func(store *mockdb.MockStore) {},
+			checkResponse: func(t *testing.T, payload *token.Payload, err error) {
+				requireStatusCode(t, err, codes.Unauthenticated)
+			},
+		},
+		{
+			name:   "UnsupportedAuthorization",
+			method: "/pb.SimpleBank/GetAccount",
+			setupContext: func(t *testing.T, tokenMaker token.Maker) context.Context {
+				accessToken, _, err := tokenMaker.CreateToken("username", util.DepositorRole, time.Minute)
+				require.NoError(t, err)
+				md := metadata.Pairs(authorizationHeaderKey, "basic "+accessToken)
+				return metadata.NewIncomingContext(context.Background(), md)
+			},
+			buildStubs: func(store *mockdb.MockStore) {},
+			checkResponse: func(t *testing.T, payload *token.Payload, err error) {
+				requireStatusCode(t, err, codes.Unauthenticated)
+			},
+		},
+		{
+			name:   "MalformedToken",
+			method: "/pb.SimpleBank/GetAccount",
+			setupContext: func(t *testing.T, tokenMaker token.Maker) context.Context {
+				md := metadata.Pairs(authorizationHeaderKey, "bearer not-a-token")
+				return metadata.NewIncomingContext(context.Background(), md)
+			},
+			buildStubs: func(store *mockdb.MockStore) {},
+			checkResponse: func(t *testing.T, payload *token.Payload, err error) {
+				requireStatusCode(t, err, codes.Unauthenticated)
+			},
+		},
+		{
+			name:   "ExpiredToken",
+			method: "/pb.SimpleBank/GetAccount",
+			setupContext: func(t *testing.T, tokenMaker token.Maker) context.Context {
+				return newContextWithBearerToken(t, tokenMaker, "username", util.DepositorRole, -time.Minute)
+			},
+			buildStubs: func(store *mockdb.MockStore) {},
+			checkResponse: func(t *testing.T, payload *token.Payload, err error) {
+				requireStatusCode(t, err, codes.Unauthenticated)
+			},
+		},
+		{
+			name:   "PasswordChangedAfterToken",
+			method: "/pb.SimpleBank/GetAccount",
+			setupContext: func(t *testing.T, tokenMaker token.Maker) context.Context {
+				return newContextWithBearerToken(t, tokenMaker, "username", util.DepositorRole, time.Minute)
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					GetUserPasswordChangedAt(gomock.Any(), gomock.Eq("username")).
+					Times(1).
+					Return(time.Now().Add(time.Second), nil)
+			},
+			checkResponse: func(t *testing.T, payload *token.Payload, err error) {
+				requireStatusCode(t, err, codes.Unauthenticated)
+			},
+		},
+		{
+			name:   "UserNotFound",
+			method: "/pb.SimpleBank/GetAccount",
+			setupContext: func(t *testing.T, tokenMaker token.Maker) context.Context {
+				return newContextWithBearerToken(t, tokenMaker, "username", util.DepositorRole, time.Minute)
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					GetUserPasswordChangedAt(gomock.Any(), gomock.Eq("username")).
+					Times(1).
+					Return(time.Time{}, sql.ErrNoRows)
+			},
+			checkResponse: func(t *testing.T, payload *token.Payload, err error) {
+				requireStatusCode(t, err, codes.Unauthenticated)
+			},
+		},
+	}
+
+	for i := range testCases {
+		tc := testCases[i]
+
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			store := mockdb.NewMockStore(ctrl)
+			tc.buildStubs(store)
+
+			server := newTestServer(t, store)
+			ctx := tc.setupContext(t, server.tokenMaker)
+			var payload *token.Payload
+			called := false
+			handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+				called = true
+				payload, _ = ctx.Value(authorizationPayloadKey{}).(*token.Payload)
+				return nil, nil
+			}
+			_, err := server.UnaryAuthInterceptor(ctx, nil, &grpc.UnaryServerInfo{FullMethod: tc.method}, handler)
+			require.Equal(t, err == nil, called)
+			tc.checkResponse(t, payload, err)
+		})
+	}
+}
+
+type testServerStream struct {
+	grpc.ServerStream
+	ctx context.Context
+}
+
+func (stream *testServerStream) Context() context.Context {
+	return stream.ctx
+}
+
+func TestStreamAuthInterceptor(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	server := newTestServer(t, mockdb.NewMockStore(ctrl))
+	info := &grpc.StreamServerInfo{FullMethod: "/pb.SimpleBank/ListAccountEntries"}
+
+	var payload *token.Payload
+	handler := func(srv interface{}, stream grpc.ServerStream) error {
+		payload, _ = stream.Context().Value(authorizationPayloadKey{}).(*token.Payload)
+		return nil
+	}
+	ctx := newContextWithBearerToken(t, server.tokenMaker, "username", util.DepositorRole, time.Minute)
+	err := server.StreamAuthInterceptor(nil, &testServerStream{ctx: ctx}, info, handler)
+	require.NoError(t, err)
+	require.NotNil(t, payload)
+	require.Equal(t, "username", payload.Username)
+
+	ctx = newContextWithBearerToken(t, server.tokenMaker, "username", util.DepositorRole, -time.Minute)
+	err = server.StreamAuthInterceptor(nil, &testServerStream{ctx: ctx}, info, handler)
+	requireStatusCode(t, err, codes.Unauthenticated)
+
+	// server reflection lives outside the SimpleBank service and needs no token
+	payload = nil
+	info = &grpc.StreamServerInfo{FullMethod: "/grpc.reflection.v1alpha.ServerReflection/ServerReflectionInfo"}
+	err = server.StreamAuthInterceptor(nil, &testServerStream{ctx: context.Background()}, info, handler)
+	require.NoError(t, err)
+	require.Nil(t, payload)
+}
@@ -0,0 +1,65 @@
+package grpcapi
+
+import (
+	"context"
+	"fmt"
+	mockdb "simple_bank/db/mock"
+	db "simple_bank/db/sqlc"
+	"simple_bank/token"
+	"simple_bank/util"
+	"simple_bank/worker"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+func newTestServer(t *testing.T, store db.Store) *Server {
+	config := util.Config{
+		TokenKey:            util.RandomString(32),
+		AccessTokenDuration: time.Minute,
+	}
+	if mockStore, ok := store.(*mockdb.MockStore); ok {
+		// authenticate checks every access token against the last password change, tests that
+		// care about it stub GetUserPasswordChangedAt before this one
+		mockStore.EXPECT().
+			GetUserPasswordChangedAt(gomock.Any(), gomock.Any()).
+			AnyTimes().
+			Return(time.Time{}, nil)
+	}
+	server, err := NewServer(config, store, worker.NewPGTaskDistributor(1))
+	require.NoError(t, err)
+	return server
+}
+
+// newContextWithBearerToken returns an incoming context carrying an access token and its payload,
+// like the ones the auth interceptors hand to the handlers
+func newContextWithBearerToken(t *testing.T, tokenMaker token.Maker, username string, role string, duration time.Duration) context.Context {
+	accessToken, payload, err := tokenMaker.CreateToken(username, role, duration)
+	require.NoError(t, err)
+	md := metadata.MD{
+		authorizationHeaderKey: []string{fmt.Sprintf("%s %s", authorizationTypeBearer, accessToken)},
+	}
+	ctx := metadata.NewIncomingContext(context.Background(), md)
+	return context.WithValue(ctx, authorizationPayloadKey{}, payload)
+}
+
+func requireStatusCode(t *testing.T, err error, code codes.Code) {
+	st, ok := status.FromError(err)
+	require.True(t, ok)
+	require.Equal(t, code, st.Code())
+}
+
+func randomAccount(username string) db.Account {
+	return db.Account{
+		ID:       util.RandomInt(1, 1000),
+		Username: username,
+		Balance:  util.RandomMoney(),
+		Currency: util.RandomCurrency(),
+		Status:   db.AccountStatusActive,
+	}
+}
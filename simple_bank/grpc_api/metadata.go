@@ -11,6 +11,7 @@ const (
 	gatewayUserAgentHeaderKey     = "grpcgateway-user-agent"
 	grpcAgentHeaderKey            = "user-agent"
 	gatewayXForwardedForHeaderKey = "x-forwarded-for"
+	idempotencyKeyHeaderKey       = "idempotency-key"
 )
 
 type Metadata struct {
@@ -36,3 +37,13 @@ func (server *Server) extractMetadata(ctx context.Context) *Metadata {
 	}
 	return meta
 }
+
+// extractIdempotencyKey returns the idempotency key sent by the client, or an empty string
+func (server *Server) extractIdempotencyKey(ctx context.Context) string {
+	if md, ok := metadata.FromIncomingContext(ctx); ok {
+		if keys := md.Get(idempotencyKeyHeaderKey); len(keys) > 0 {
+			return keys[0]
+		}
+	}
+	return ""
+}
@@ -0,0 +1,60 @@
+package grpcapi
+
+import (
+	"context"
+	db "simple_bank/db/sqlc"
+	"simple_bank/pb"
+	"simple_bank/validator"
+
+	"github.com/lib/pq"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func (server *Server) CreateAccount(ctx context.Context, req *pb.CreateAccountRequest) (*pb.CreateAccountResponse, error) {
+	authPayload, err := server.authorizeUser(ctx)
+	if err != nil {
+		return nil, unauthenticatedError(err)
+	}
+	violations := validateCreateAccountRequest(req)
+	if violations != nil {
+		return nil, invalidArgumentError(violations)
+	}
+
+	user, err := server.store.GetUser(ctx, authPayload.Username)
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to get user: %v", err)
+	}
+	if !user.IsEmailVerified {
+		return nil, status.Errorf(codes.PermissionDenied, "%v", db.ErrEmailNotVerified)
+	}
+	arg := db.CreateAccountParams{
+		Username: authPayload.Username,
+		Balance:  0,
+		Currency: req.GetCurrency(),
+	}
+	account, err := server.store.CreateAccount(ctx, arg)
+	if err != nil {
+		if pqErr, value := err.(*pq.Error); value {
+			switch pqErr.Code.Name() {
+			case "unique_violation":
+				return nil, status.Errorf(codes.AlreadyExists, "account in %s already exists", req.GetCurrency())
+			case "foreign_key_violation":
+				return nil, status.Errorf(codes.PermissionDenied, "user cannot own accounts: %v", err)
+			}
+		}
+		return nil, status.Errorf(codes.Internal, "failed to create account: %v", err)
+	}
+	response := &pb.CreateAccountResponse{
+		Account: convertAccount(account),
+	}
+	return response, nil
+}
+
+func validateCreateAccountRequest(req *pb.CreateAccountRequest) (violations []*errdetails.BadRequest_FieldViolation) {
+	if err := validator.ValidateCurrency(req.GetCurrency()); err != nil {
+		violations = append(violations, fieldViolation("currency", err))
+	}
+	return violations
+}
@@ -0,0 +1,53 @@
+package grpcapi
+
+import (
+	"context"
+	db "simple_bank/db/sqlc"
+	"simple_bank/pb"
+	"simple_bank/util"
+	"simple_bank/validator"
+
+	"github.com/lib/pq"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// CreateCurrency adds a currency to the registry. The servers pick it up with their next currency refresh.
+func (server *Server) CreateCurrency(ctx context.Context, req *pb.CreateCurrencyRequest) (*pb.CreateCurrencyResponse, error) {
+	_, err := server.authorizeRoles(ctx, util.AdminRole)
+	if err != nil {
+		return nil, err
+	}
+	violations := validateCreateCurrencyRequest(req)
+	if violations != nil {
+		return nil, invalidArgumentError(violations)
+	}
+
+	currency, err := server.store.CreateCurrency(ctx, db.CreateCurrencyParams{
+		Code:      req.GetCode(),
+		MinorUnit: int16(req.GetMinorUnit()),
+		Symbol:    req.GetSymbol(),
+		Enabled:   req.GetEnabled(),
+	})
+	if err != nil {
+		if pqErr, ok := err.(*pq.Error); ok && pqErr.Code.Name() == "unique_violation" {
+			return nil, status.Errorf(codes.AlreadyExists, "currency %s already exists", req.GetCode())
+		}
+		return nil, status.Errorf(codes.Internal, "failed to create currency: %v", err)
+	}
+	return &pb.CreateCurrencyResponse{Currency: convertCurrency(currency)}, nil
+}
+
+func validateCreateCurrencyRequest(req *pb.CreateCurrencyRequest) (violations []*errdetails.BadRequest_FieldViolation) {
+	if err := validator.ValidateCurrencyCode(req.GetCode()); err != nil {
+		violations = append(violations, fieldViolation("code", err))
+	}
+	if err := validator.ValidateMinorUnit(req.GetMinorUnit()); err != nil {
+		violations = append(violations, fieldViolation("minorUnit", err))
+	}
+	if err := validator.ValidateCurrencySymbol(req.GetSymbol()); err != nil {
+		violations = append(violations, fieldViolation("symbol", err))
+	}
+	return violations
+}
@@ -0,0 +1,104 @@
+package grpcapi
+
+import (
+	"context"
+	mockdb "simple_bank/db/mock"
+	db "simple_bank/db/sqlc"
+	"simple_bank/pb"
+	"simple_bank/token"
+	"simple_bank/util"
+	"testing"
+	"time"
+
+	"github.com/lib/pq"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+	"google.golang.org/grpc/codes"
+)
+
+func TestCreateCurrencyRPC(t *testing.T) {
+	currency := db.Currency{Code: "GBP", MinorUnit: 2, Symbol: "£", Enabled: true, CreatedAt: time.Now()}
+	req := &pb.CreateCurrencyRequest{Code: currency.Code, MinorUnit: 2, Symbol: currency.Symbol, Enabled: true}
+
+	testCases := []struct {
+		name          string
+		req           *pb.CreateCurrencyRequest
+		buildStubs    func(store *mockdb.MockStore)
+		buildContext  func(t *testing.T, tokenMaker token.Maker) context.Context
+		checkResponse func(t *testing.T, res *pb.CreateCurrencyResponse, err error)
+	}{
+		{
+			name: "OK",
+			req:  req,
+			buildStubs: func(store *mockdb.MockStore) {
+				arg := db.CreateCurrencyParams{Code: currency.Code, MinorUnit: 2, Symbol: currency.Symbol, Enabled: true}
+				store.EXPECT().CreateCurrency(gomock.Any(), gomock.Eq(arg)).Times(1).Return(currency, nil)
+			},
+			buildContext: func(t *testing.T, tokenMaker token.Maker) context.Context {
+				return newContextWithBearerToken(t, tokenMaker, "admin", util.AdminRole, time.Minute)
+			},
+			checkResponse: func(t *testing.T, res *pb.CreateCurrencyResponse, err error) {
+				require.NoError(t, err)
+				require.Equal(t, currency.Code, res.GetCurrency().GetCode())
+				require.Equal(t, int32(2), res.GetCurrency().GetMinorUnit())
+				require.True(t, res.GetCurrency().GetEnabled())
+			},
+		},
+		{
+			name: "AlreadyExists",
+			req:  req,
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().CreateCurrency(gomock.Any(), gomock.Any()).Times(1).Return(db.Currency{}, &pq.Error{Code: "23505"})
+			},
+			buildContext: func(t *testing.T, tokenMaker token.Maker) context.Context {
+				return newContextWithBearerToken(t, tokenMaker, "admin", util.AdminRole, time.Minute)
+			},
+			checkResponse: func(t *testing.T, res *pb.CreateCurrencyResponse, err error) {
+				requireStatusCode(t, err, codes.AlreadyExists)
+			},
+		},
+		{
+			name: "InvalidMinorUnit",
+			req:  &pb.CreateCurrencyRequest{Code: currency.Code, MinorUnit: 5, Symbol: currency.Symbol},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().CreateCurrency(gomock.Any(), gomock.Any()).Times(0)
+			},
+			buildContext: func(t *testing.T, tokenMaker token.Maker) context.Context {
+				return newContextWithBearerToken(t, tokenMaker, "admin", util.AdminRole, time.Minute)
+			},
+			checkResponse: func(t *testing.T, res *pb.CreateCurrencyResponse, err error) {
+				requireStatusCode(t, err, codes.InvalidArgument)
+			},
+		},
+		{
+			name: "BankerNotAllowed",
+			req:  req,
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().CreateCurrency(gomock.Any(), gomock.Any()).Times(0)
+			},
+			buildContext: func(t *testing.T, tokenMaker token.Maker) context.Context {
+				return newContextWithBearerToken(t, tokenMaker, "banker", util.BankerRole, time.Minute)
+			},
+			checkResponse: func(t *testing.T, res *pb.CreateCurrencyResponse, err error) {
+				requireStatusCode(t, err, codes.PermissionDenied)
+			},
+		},
+	}
+
+	for i := range testCases {
+		tc := testCases[i]
+
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			store := mockdb.NewMockStore(ctrl)
+			tc.buildStubs(store)
+
+			server := newTestServer(t, store)
+			ctx := tc.buildContext(t, server.tokenMaker)
+			res, err := server.CreateCurrency(ctx, tc.req)
+			tc.checkResponse(t, res, err)
+		})
+	}
+}
@@ -0,0 +1,117 @@
+package grpcapi
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	db "simple_bank/db/sqlc"
+	"simple_bank/pb"
+	"simple_bank/util"
+	"simple_bank/validator"
+	"time"
+
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func (server *Server) CreateScheduledTransfer(ctx context.Context, req *pb.CreateScheduledTransferRequest) (*pb.CreateScheduledTransferResponse, error) {
+	authPayload, err := server.authorizeUser(ctx)
+	if err != nil {
+		return nil, unauthenticatedError(err)
+	}
+	violations := validateCreateScheduledTransferRequest(req)
+	amount, err := requestAmount(req.GetAmount(), req.GetDecimalAmount(), req.GetCurrency())
+	if err != nil {
+		violations = append(violations, fieldViolation("decimalAmount", err))
+	}
+	nextRunAt, err := firstRunAt(req.GetSchedule(), convertOptionalTimestamp(req.GetRunAt()))
+	if err != nil {
+		violations = append(violations, fieldViolation("runAt", err))
+	}
+	if violations != nil {
+		return nil, invalidArgumentError(violations)
+	}
+
+	fromAccount, err := server.validAccount(ctx, req.GetFromAccountId(), req.GetCurrency())
+	if err != nil {
+		return nil, err
+	}
+	if fromAccount.Username != authPayload.Username {
+		return nil, status.Errorf(codes.PermissionDenied, "from account does not belong to the authenticated user")
+	}
+	toCurrency := req.GetToCurrency()
+	if toCurrency == "" {
+		toCurrency = req.GetCurrency()
+	}
+	_, err = server.validAccount(ctx, req.GetToAccountId(), toCurrency)
+	if err != nil {
+		return nil, err
+	}
+
+	arg := db.CreateScheduledTransferParams{
+		Username:      authPayload.Username,
+		FromAccountId: req.GetFromAccountId(),
+		ToAccountId:   req.GetToAccountId(),
+		Amount:        amount.Amount,
+		Schedule:      req.GetSchedule(),
+		NextRunAt:     nextRunAt,
+	}
+	scheduledTransfer, err := server.store.CreateScheduledTransfer(ctx, arg)
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to create scheduled transfer: %v", err)
+	}
+	response := &pb.CreateScheduledTransferResponse{
+		ScheduledTransfer: convertScheduledTransfer(scheduledTransfer),
+	}
+	return response, nil
+}
+
+// firstRunAt returns when a scheduled transfer runs first: at runAt when it is given, otherwise at the next
+// occurrence of the schedule
+func firstRunAt(schedule string, runAt time.Time) (time.Time, error) {
+	if !runAt.IsZero() {
+		if !runAt.After(time.Now()) {
+			return runAt, errors.New("runAt must be in the future")
+		}
+		return runAt, nil
+	}
+	if schedule == "" {
+		return runAt, errors.New("runAt is required for a one-off transfer")
+	}
+	parsed, err := util.ParseSchedule(schedule)
+	if err != nil {
+		return runAt, err
+	}
+	return parsed.Next(time.Now()), nil
+}
+
+func validateCreateScheduledTransferRequest(req *pb.CreateScheduledTransferRequest) (violations []*errdetails.BadRequest_FieldViolation) {
+	if err := validator.ValidateAccountId(req.GetFromAccountId()); err != nil {
+		violations = append(violations, fieldViolation("fromAccountId", err))
+	}
+	if err := validator.ValidateAccountId(req.GetToAccountId()); err != nil {
+		violations = append(violations, fieldViolation("toAccountId", err))
+	}
+	if req.GetFromAccountId() == req.GetToAccountId() {
+		violations = append(violations, fieldViolation("toAccountId", fmt.Errorf("cannot transfer to the same account")))
+	}
+	if req.GetDecimalAmount() == "" && req.GetAmount() <= 0 {
+		violations = append(violations, fieldViolation("amount", fmt.Errorf("amount must be greater than 0")))
+	}
+	if req.GetDecimalAmount() != "" && req.GetAmount() != 0 {
+		violations = append(violations, fieldViolation("amount", fmt.Errorf("amount and decimalAmount are mutually exclusive")))
+	}
+	if err := validator.ValidateCurrency(req.GetCurrency()); err != nil {
+		violations = append(violations, fieldViolation("currency", err))
+	}
+	if req.GetToCurrency() != "" {
+		if err := validator.ValidateCurrency(req.GetToCurrency()); err != nil {
+			violations = append(violations, fieldViolation("toCurrency", err))
+		}
+	}
+	if err := validator.ValidateSchedule(req.GetSchedule()); err != nil {
+		violations = append(violations, fieldViolation("schedule", err))
+	}
+	return violations
+}
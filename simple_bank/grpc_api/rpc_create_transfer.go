@@ -0,0 +1,159 @@
+package grpcapi
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	db "simple_bank/db/sqlc"
+	"simple_bank/exchange"
+	"simple_bank/pb"
+	"simple_bank/util"
+	"simple_bank/validator"
+
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func (server *Server) CreateTransfer(ctx context.Context, req *pb.CreateTransferRequest) (*pb.CreateTransferResponse, error) {
+	authPayload, err := server.authorizeUser(ctx)
+	if err != nil {
+		return nil, unauthenticatedError(err)
+	}
+	violations := validateCreateTransferRequest(req)
+	amount, err := requestAmount(req.GetAmount(), req.GetDecimalAmount(), req.GetCurrency())
+	if err != nil {
+		violations = append(violations, fieldViolation("decimalAmount", err))
+	}
+	idempotencyKey := server.extractIdempotencyKey(ctx)
+	if err := validator.ValidateIdempotencyKey(idempotencyKey); err != nil {
+		violations = append(violations, fieldViolation(idempotencyKeyHeaderKey, err))
+	}
+	if violations != nil {
+		return nil, invalidArgumentError(violations)
+	}
+
+	fromAccount, err := server.validAccount(ctx, req.GetFromAccountId(), req.GetCurrency())
+	if err != nil {
+		return nil, err
+	}
+	if fromAccount.Username != authPayload.Username {
+		return nil, status.Errorf(codes.PermissionDenied, "from account does not belong to the authenticated user")
+	}
+	toCurrency := req.GetToCurrency()
+	if toCurrency == "" {
+		toCurrency = req.GetCurrency()
+	}
+	_, err = server.validAccount(ctx, req.GetToAccountId(), toCurrency)
+	if err != nil {
+		return nil, err
+	}
+
+	arg := db.TransferTxParams{
+		FromAccountID:  req.GetFromAccountId(),
+		ToAccountID:    req.GetToAccountId(),
+		Amount:         amount.Amount,
+		IdempotencyKey: idempotencyKey,
+		Username:       authPayload.Username,
+	}
+	if toCurrency != req.GetCurrency() {
+		rate, err := server.exchangeRates.GetRate(ctx, req.GetCurrency(), toCurrency)
+		if err != nil {
+			if errors.Is(err, exchange.ErrRateNotFound) {
+				return nil, invalidArgumentError([]*errdetails.BadRequest_FieldViolation{fieldViolation("toCurrency", err)})
+			}
+			return nil, status.Errorf(codes.Internal, "failed to get exchange rate: %v", err)
+		}
+		arg.ExchangeRate = &rate
+	}
+	result, err := server.store.TransferTx(ctx, arg)
+	if err != nil {
+		var limitErr *db.LimitExceededError
+		if errors.As(err, &limitErr) {
+			return nil, limitExceededError(limitErr)
+		}
+		if errors.Is(err, db.ErrIdempotencyKeyConflict) {
+			return nil, status.Errorf(codes.AlreadyExists, "%v", err)
+		}
+		if errors.Is(err, db.ErrEmailNotVerified) {
+			return nil, status.Errorf(codes.PermissionDenied, "%v", err)
+		}
+		if errors.Is(err, db.ErrInsufficientFunds) || errors.Is(err, db.ErrAccountNotActive) {
+			return nil, status.Errorf(codes.FailedPrecondition, "%v", err)
+		}
+		if errors.Is(err, db.ErrCurrencyMismatch) || errors.Is(err, exchange.ErrAmountTooSmall) {
+			return nil, status.Errorf(codes.InvalidArgument, "%v", err)
+		}
+		return nil, status.Errorf(codes.Internal, "failed to transfer: %v", err)
+	}
+	response := &pb.CreateTransferResponse{
+		Transfer:    convertTransfer(result.Transfer),
+		FromAccount: convertAccount(result.FromAccount),
+		ToAccount:   convertAccount(result.ToAccount),
+		FromEntry:   convertEntry(result.FromEntry),
+		ToEntry:     convertEntry(result.ToEntry),
+
+		FormattedAmount:   util.FormatAmount(result.Transfer.Amount, req.GetCurrency()),
+		FormattedToAmount: util.FormatAmount(result.Transfer.ToAmount, toCurrency),
+	}
+	return response, nil
+}
+
+// validAccount checks that the account exists and holds the given currency
+func (server *Server) validAccount(ctx context.Context, accountID int64, currency string) (db.Account, error) {
+	account, err := server.store.GetAccount(ctx, accountID)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return account, status.Errorf(codes.NotFound, "account [%d] not found: %v", accountID, err)
+		}
+		return account, status.Errorf(codes.Internal, "failed to get account [%d]: %v", accountID, err)
+	}
+	if account.Currency != currency {
+		err := fmt.Errorf("account [%d] currency mismatch: %s vs %s", account.ID, account.Currency, currency)
+		return account, invalidArgumentError([]*errdetails.BadRequest_FieldViolation{fieldViolation("currency", err)})
+	}
+	return account, nil
+}
+
+// requestAmount returns the amount of a request in minor units, parsing decimalAmount when it is set
+func requestAmount(amount int64, decimalAmount string, currency string) (util.Money, error) {
+	if decimalAmount == "" {
+		return util.NewMoney(amount, currency), nil
+	}
+	money, err := util.ParseMoney(decimalAmount, currency)
+	if err != nil {
+		return money, err
+	}
+	if money.Amount <= 0 {
+		return money, fmt.Errorf("amount must be greater than 0")
+	}
+	return money, nil
+}
+
+func validateCreateTransferRequest(req *pb.CreateTransferRequest) (violations []*errdetails.BadRequest_FieldViolation) {
+	if err := validator.ValidateAccountId(req.GetFromAccountId()); err != nil {
+		violations = append(violations, fieldViolation("fromAccountId", err))
+	}
+	if err := validator.ValidateAccountId(req.GetToAccountId()); err != nil {
+		violations = append(violations, fieldViolation("toAccountId", err))
+	}
+	if req.GetFromAccountId() == req.GetToAccountId() {
+		violations = append(violations, fieldViolation("toAccountId", fmt.Errorf("cannot transfer to the same account")))
+	}
+	if req.GetDecimalAmount() == "" && req.GetAmount() <= 0 {
+		violations = append(violations, fieldViolation("amount", fmt.Errorf("amount must be greater than 0")))
+	}
+	if req.GetDecimalAmount() != "" && req.GetAmount() != 0 {
+		violations = append(violations, fieldViolation("amount", fmt.Errorf("amount and decimalAmount are mutually exclusive")))
+	}
+	if err := validator.ValidateCurrency(req.GetCurrency()); err != nil {
+		violations = append(violations, fieldViolation("currency", err))
+	}
+	if req.GetToCurrency() != "" {
+		if err := validator.ValidateCurrency(req.GetToCurrency()); err != nil {
+			violations = append(violations, fieldViolation("toCurrency", err))
+		}
+	}
+	return violations
+}
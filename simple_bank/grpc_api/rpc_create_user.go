@@ -6,6 +6,7 @@ import (
 	"simple_bank/pb"
 	util "simple_bank/util"
 	"simple_bank/validator"
+	"simple_bank/worker"
 
 	"github.com/lib/pq"
 	"google.golang.org/genproto/googleapis/rpc/errdetails"
@@ -23,17 +24,26 @@ func (server *Server) CreateUser(ctx context.Context, req *pb.CreateUserRequest)
 	if err != nil {
 		return nil, status.Errorf(codes.Internal, "failed to hash password: %s", err)
 	}
-	arg := db.CreateUserParams{
-		Username:       req.GetUsername(),
-		Name1:          req.GetName1(),
-		Name2:          util.StringToSqlNullString(req.GetName2()),
-		Lastname1:      req.GetLastname1(),
-		Lastname2:      util.StringToSqlNullString(req.GetLastname2()),
-		Email:          req.GetEmail(),
-		HashedPassword: hashedPassword,
+	arg := db.CreateUserTxParams{
+		CreateUserParams: db.CreateUserParams{
+			Username:       req.GetUsername(),
+			Name1:          req.GetName1(),
+			Name2:          util.StringToSqlNullString(req.GetName2()),
+			Lastname1:      req.GetLastname1(),
+			Lastname2:      util.StringToSqlNullString(req.GetLastname2()),
+			Email:          req.GetEmail(),
+			HashedPassword: hashedPassword,
+		},
+		SecretCode: util.RandomString(32),
+		// the email is sent in the background, the task is only enqueued when the user is created
+		AfterCreate: func(q db.Querier, user db.User, verifyEmail db.VerifyEmail) error {
+			return server.taskDistributor.DistributeTaskSendVerifyEmail(ctx, q, &worker.PayloadSendVerifyEmail{
+				EmailId: verifyEmail.ID,
+			})
+		},
 	}
 
-	user, err := server.store.CreateUser(ctx, arg)
+	result, err := server.store.CreateUserTx(ctx, arg)
 	if err != nil {
 		if pqErr, value := err.(*pq.Error); value {
 			switch pqErr.Code.Name() {
@@ -46,7 +56,7 @@ func (server *Server) CreateUser(ctx context.Context, req *pb.CreateUserRequest)
 		return nil, status.Errorf(codes.Internal, "failed to create user")
 	}
 	response := &pb.CreateUserResponse{
-		User: convertUser(user),
+		User: convertUser(result.User),
 	}
 	return response, nil
 }
@@ -0,0 +1,42 @@
+package grpcapi
+
+import (
+	"context"
+	db "simple_bank/db/sqlc"
+	"simple_bank/pb"
+	"simple_bank/validator"
+
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+)
+
+// DeleteAccount closes the account, its entries and transfers are kept. The balance has to be zero.
+func (server *Server) DeleteAccount(ctx context.Context, req *pb.DeleteAccountRequest) (*pb.DeleteAccountResponse, error) {
+	authPayload, err := server.authorizeUser(ctx)
+	if err != nil {
+		return nil, unauthenticatedError(err)
+	}
+	violations := validateDeleteAccountRequest(req)
+	if violations != nil {
+		return nil, invalidArgumentError(violations)
+	}
+
+	_, err = server.authorizedAccount(ctx, req.GetId(), authPayload)
+	if err != nil {
+		return nil, err
+	}
+	_, err = server.store.UpdateAccountStatusTx(ctx, db.UpdateAccountStatusTxParams{
+		AccountID: req.GetId(),
+		Status:    db.AccountStatusClosed,
+	})
+	if err != nil {
+		return nil, accountStatusError(err, "failed to close account")
+	}
+	return &pb.DeleteAccountResponse{}, nil
+}
+
+func validateDeleteAccountRequest(req *pb.DeleteAccountRequest) (violations []*errdetails.BadRequest_FieldViolation) {
+	if err := validator.ValidateAccountId(req.GetId()); err != nil {
+		violations = append(violations, fieldViolation("id", err))
+	}
+	return violations
+}
@@ -0,0 +1,30 @@
+package grpcapi
+
+import (
+	"context"
+	"database/sql"
+	db "simple_bank/db/sqlc"
+	"simple_bank/pb"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// DeleteScheduledTransfer cancels the transfer, its runs are kept
+func (server *Server) DeleteScheduledTransfer(ctx context.Context, req *pb.DeleteScheduledTransferRequest) (*pb.DeleteScheduledTransferResponse, error) {
+	scheduledTransfer, err := server.validScheduledTransfer(ctx, req.GetId())
+	if err != nil {
+		return nil, err
+	}
+	if !isPending(scheduledTransfer) {
+		return nil, status.Errorf(codes.FailedPrecondition, "scheduled transfer is %s", scheduledTransfer.Status)
+	}
+	_, err = server.store.UpdateScheduledTransfer(ctx, db.UpdateScheduledTransferParams{
+		ID:     scheduledTransfer.ID,
+		Status: sql.NullString{String: db.ScheduledTransferStatusCancelled, Valid: true},
+	})
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to cancel scheduled transfer: %v", err)
+	}
+	return &pb.DeleteScheduledTransferResponse{}, nil
+}
@@ -0,0 +1,117 @@
+package grpcapi
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	db "simple_bank/db/sqlc"
+	"simple_bank/pb"
+	"simple_bank/util"
+	"simple_bank/validator"
+
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// Deposit adds money to an account. Only bankers and admins can deposit, e.g. for cash paid in at
+// a branch, owners would otherwise create money out of nothing.
+func (server *Server) Deposit(ctx context.Context, req *pb.DepositRequest) (*pb.DepositResponse, error) {
+	_, err := server.authorizeRoles(ctx, util.BankerRole, util.AdminRole)
+	if err != nil {
+		return nil, err
+	}
+	account, amount, err := server.validBalanceChange(ctx, req.GetAccountId(), req.GetAmount(), req.GetDecimalAmount(), req.GetCurrency(), req.GetReason(), req.GetExternalReference(), util.BankerRole, util.AdminRole)
+	if err != nil {
+		return nil, err
+	}
+	result, err := server.store.DepositTx(ctx, db.DepositTxParams{
+		AccountID:         account.ID,
+		Amount:            amount.Amount,
+		Reason:            req.GetReason(),
+		ExternalReference: req.GetExternalReference(),
+	})
+	if err != nil {
+		return nil, balanceChangeError(err, "failed to deposit")
+	}
+	response := &pb.DepositResponse{
+		Account:         convertAccount(result.Account),
+		Entry:           convertEntry(result.Entry),
+		FormattedAmount: util.FormatAmount(result.Entry.Amount, result.Account.Currency),
+	}
+	return response, nil
+}
+
+// validBalanceChange validates a deposit or withdrawal and checks the account belongs to the
+// authenticated user, or the user has one of the staff roles, and holds the requested currency
+func (server *Server) validBalanceChange(
+	ctx context.Context,
+	accountID int64,
+	amount int64,
+	decimalAmount string,
+	currency string,
+	reason string,
+	externalReference string,
+	staffRoles ...string,
+) (db.Account, util.Money, error) {
+	authPayload, err := server.authorizeUser(ctx)
+	if err != nil {
+		return db.Account{}, util.Money{}, unauthenticatedError(err)
+	}
+	violations := validateBalanceChangeRequest(accountID, amount, decimalAmount, currency, reason, externalReference)
+	money, err := requestAmount(amount, decimalAmount, currency)
+	if err != nil {
+		violations = append(violations, fieldViolation("decimalAmount", err))
+	}
+	if violations != nil {
+		return db.Account{}, money, invalidArgumentError(violations)
+	}
+
+	account, err := server.validAccount(ctx, accountID, currency)
+	if err != nil {
+		return account, money, err
+	}
+	if account.Username != authPayload.Username && !hasRole(authPayload, staffRoles...) {
+		return account, money, status.Errorf(codes.PermissionDenied, "account doesn't belong to the authenticated user")
+	}
+	return account, money, nil
+}
+
+func balanceChangeError(err error, message string) error {
+	switch {
+	case errors.Is(err, db.ErrDuplicateExternalReference):
+		return status.Errorf(codes.AlreadyExists, "%v", err)
+	case errors.Is(err, db.ErrInsufficientFunds), errors.Is(err, db.ErrAccountNotActive), errors.Is(err, util.ErrMoneyOverflow):
+		return status.Errorf(codes.FailedPrecondition, "%v", err)
+	}
+	return status.Errorf(codes.Internal, "%s: %v", message, err)
+}
+
+func validateBalanceChangeRequest(
+	accountID int64,
+	amount int64,
+	decimalAmount string,
+	currency string,
+	reason string,
+	externalReference string,
+) (violations []*errdetails.BadRequest_FieldViolation) {
+	if err := validator.ValidateAccountId(accountID); err != nil {
+		violations = append(violations, fieldViolation("accountId", err))
+	}
+	if decimalAmount == "" && amount <= 0 {
+		violations = append(violations, fieldViolation("amount", fmt.Errorf("amount must be greater than 0")))
+	}
+	if decimalAmount != "" && amount != 0 {
+		violations = append(violations, fieldViolation("amount", fmt.Errorf("amount and decimalAmount are mutually exclusive")))
+	}
+	if err := validator.ValidateCurrency(currency); err != nil {
+		violations = append(violations, fieldViolation("currency", err))
+	}
+	if err := validator.ValidateEntryReason(reason); err != nil {
+		violations = append(violations, fieldViolation("reason", err))
+	}
+	if err := validator.ValidateExternalReference(externalReference); err != nil {
+		violations = append(violations, fieldViolation("externalReference", err))
+	}
+	return violations
+}
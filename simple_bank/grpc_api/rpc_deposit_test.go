@@ -0,0 +1,96 @@
+package grpcapi
+
+import (
+	"context"
+	mockdb "simple_bank/db/mock"
+	db "simple_bank/db/sqlc"
+	"simple_bank/pb"
+	"simple_bank/token"
+	"simple_bank/util"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+	"google.golang.org/grpc/codes"
+)
+
+func TestDepositRPC(t *testing.T) {
+	account := randomAccount(util.RandomUsername())
+	account.Currency = util.GetCurrency(0)
+	amount := int64(1234)
+
+	testCases := []struct {
+		name          string
+		req           *pb.DepositRequest
+		buildStubs    func(store *mockdb.MockStore)
+		buildContext  func(t *testing.T, tokenMaker token.Maker) context.Context
+		checkResponse func(t *testing.T, res *pb.DepositResponse, err error)
+	}{
+		{
+			name: "OK",
+			req:  &pb.DepositRequest{AccountId: account.ID, Amount: amount, Currency: account.Currency},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetAccount(gomock.Any(), gomock.Eq(account.ID)).Times(1).Return(account, nil)
+				arg := db.DepositTxParams{AccountID: account.ID, Amount: amount}
+				result := db.BalanceTxResult{
+					Account: account,
+					Entry:   db.Entry{AccountId: account.ID, Amount: amount},
+				}
+				store.EXPECT().DepositTx(gomock.Any(), gomock.Eq(arg)).Times(1).Return(result, nil)
+			},
+			buildContext: func(t *testing.T, tokenMaker token.Maker) context.Context {
+				return newContextWithBearerToken(t, tokenMaker, util.RandomUsername(), util.BankerRole, time.Minute)
+			},
+			checkResponse: func(t *testing.T, res *pb.DepositResponse, err error) {
+				require.NoError(t, err)
+				require.Equal(t, amount, res.GetEntry().GetAmount())
+				require.Equal(t, "12.34", res.GetFormattedAmount())
+			},
+		},
+		{
+			name: "OwnerCannotDeposit",
+			req:  &pb.DepositRequest{AccountId: account.ID, Amount: amount, Currency: account.Currency},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetAccount(gomock.Any(), gomock.Any()).Times(0)
+				store.EXPECT().DepositTx(gomock.Any(), gomock.Any()).Times(0)
+			},
+			buildContext: func(t *testing.T, tokenMaker token.Maker) context.Context {
+				return newContextWithBearerToken(t, tokenMaker, account.Username, util.DepositorRole, time.Minute)
+			},
+			checkResponse: func(t *testing.T, res *pb.DepositResponse, err error) {
+				requireStatusCode(t, err, codes.PermissionDenied)
+			},
+		},
+		{
+			name: "NoAuthorization",
+			req:  &pb.DepositRequest{AccountId: account.ID, Amount: amount, Currency: account.Currency},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().DepositTx(gomock.Any(), gomock.Any()).Times(0)
+			},
+			buildContext: func(t *testing.T, tokenMaker token.Maker) context.Context {
+				return context.Background()
+			},
+			checkResponse: func(t *testing.T, res *pb.DepositResponse, err error) {
+				requireStatusCode(t, err, codes.Unauthenticated)
+			},
+		},
+	}
+
+	for i := range testCases {
+		tc := testCases[i]
+
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			store := mockdb.NewMockStore(ctrl)
+			tc.buildStubs(store)
+
+			server := newTestServer(t, store)
+			ctx := tc.buildContext(t, server.tokenMaker)
+			res, err := server.Deposit(ctx, tc.req)
+			tc.checkResponse(t, res, err)
+		})
+	}
+}
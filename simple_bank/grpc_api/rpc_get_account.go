@@ -0,0 +1,37 @@
+package grpcapi
+
+import (
+	"context"
+	"simple_bank/pb"
+	"simple_bank/util"
+	"simple_bank/validator"
+
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+)
+
+func (server *Server) GetAccount(ctx context.Context, req *pb.GetAccountRequest) (*pb.GetAccountResponse, error) {
+	authPayload, err := server.authorizeUser(ctx)
+	if err != nil {
+		return nil, unauthenticatedError(err)
+	}
+	violations := validateGetAccountRequest(req)
+	if violations != nil {
+		return nil, invalidArgumentError(violations)
+	}
+
+	account, err := server.authorizedAccount(ctx, req.GetId(), authPayload, util.BankerRole, util.AdminRole)
+	if err != nil {
+		return nil, err
+	}
+	response := &pb.GetAccountResponse{
+		Account: convertAccount(account),
+	}
+	return response, nil
+}
+
+func validateGetAccountRequest(req *pb.GetAccountRequest) (violations []*errdetails.BadRequest_FieldViolation) {
+	if err := validator.ValidateAccountId(req.GetId()); err != nil {
+		violations = append(violations, fieldViolation("id", err))
+	}
+	return violations
+}
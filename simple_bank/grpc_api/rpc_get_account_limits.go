@@ -0,0 +1,48 @@
+package grpcapi
+
+import (
+	"context"
+	"database/sql"
+	db "simple_bank/db/sqlc"
+	"simple_bank/pb"
+	"simple_bank/util"
+	"simple_bank/validator"
+
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func (server *Server) GetAccountLimits(ctx context.Context, req *pb.GetAccountLimitsRequest) (*pb.GetAccountLimitsResponse, error) {
+	authPayload, err := server.authorizeUser(ctx)
+	if err != nil {
+		return nil, unauthenticatedError(err)
+	}
+	if err := validator.ValidateAccountId(req.GetAccountId()); err != nil {
+		return nil, invalidArgumentError([]*errdetails.BadRequest_FieldViolation{fieldViolation("accountId", err)})
+	}
+	account, err := server.authorizedAccount(ctx, req.GetAccountId(), authPayload, util.BankerRole, util.AdminRole)
+	if err != nil {
+		return nil, err
+	}
+
+	response := &pb.GetAccountLimitsResponse{}
+	accountLimit, err := server.store.GetAccountLimit(ctx, account.ID)
+	if err != nil && err != sql.ErrNoRows {
+		return nil, status.Errorf(codes.Internal, "failed to get account limits: %v", err)
+	}
+	if err == nil {
+		response.Account = convertAccountLimit(accountLimit)
+	}
+	userLimit, err := server.store.GetUserLimit(ctx, db.GetUserLimitParams{
+		Username: account.Username,
+		Currency: account.Currency,
+	})
+	if err != nil && err != sql.ErrNoRows {
+		return nil, status.Errorf(codes.Internal, "failed to get user limits: %v", err)
+	}
+	if err == nil {
+		response.User = convertUserLimit(userLimit)
+	}
+	return response, nil
+}
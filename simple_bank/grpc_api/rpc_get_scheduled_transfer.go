@@ -0,0 +1,53 @@
+package grpcapi
+
+import (
+	"context"
+	"database/sql"
+	db "simple_bank/db/sqlc"
+	"simple_bank/pb"
+	"simple_bank/validator"
+
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func (server *Server) GetScheduledTransfer(ctx context.Context, req *pb.GetScheduledTransferRequest) (*pb.GetScheduledTransferResponse, error) {
+	scheduledTransfer, err := server.validScheduledTransfer(ctx, req.GetId())
+	if err != nil {
+		return nil, err
+	}
+	response := &pb.GetScheduledTransferResponse{
+		ScheduledTransfer: convertScheduledTransfer(scheduledTransfer),
+	}
+	return response, nil
+}
+
+// validScheduledTransfer loads the scheduled transfer and checks it belongs to the authenticated user
+func (server *Server) validScheduledTransfer(ctx context.Context, id int64) (db.ScheduledTransfer, error) {
+	authPayload, err := server.authorizeUser(ctx)
+	if err != nil {
+		return db.ScheduledTransfer{}, unauthenticatedError(err)
+	}
+	if err := validator.ValidateScheduledTransferId(id); err != nil {
+		return db.ScheduledTransfer{}, invalidArgumentError([]*errdetails.BadRequest_FieldViolation{fieldViolation("id", err)})
+	}
+
+	scheduledTransfer, err := server.store.GetScheduledTransfer(ctx, id)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return scheduledTransfer, status.Errorf(codes.NotFound, "scheduled transfer not found: %v", err)
+		}
+		return scheduledTransfer, status.Errorf(codes.Internal, "failed to get scheduled transfer: %v", err)
+	}
+	if scheduledTransfer.Username != authPayload.Username {
+		return scheduledTransfer, status.Errorf(codes.PermissionDenied, "scheduled transfer doesn't belong to the authenticated user")
+	}
+	return scheduledTransfer, nil
+}
+
+// isPending reports whether the transfer still has runs ahead, only those can be changed or cancelled
+func isPending(scheduledTransfer db.ScheduledTransfer) bool {
+	return scheduledTransfer.Status == db.ScheduledTransferStatusActive ||
+		scheduledTransfer.Status == db.ScheduledTransferStatusPaused
+}
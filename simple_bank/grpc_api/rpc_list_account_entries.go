@@ -0,0 +1,72 @@
+package grpcapi
+
+import (
+	"context"
+	"fmt"
+	db "simple_bank/db/sqlc"
+	"simple_bank/pb"
+	"simple_bank/util"
+	"simple_bank/validator"
+
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func (server *Server) ListAccountEntries(ctx context.Context, req *pb.ListAccountEntriesRequest) (*pb.ListAccountEntriesResponse, error) {
+	authPayload, err := server.authorizeUser(ctx)
+	if err != nil {
+		return nil, unauthenticatedError(err)
+	}
+	violations := validateListAccountEntriesRequest(req)
+	if violations != nil {
+		return nil, invalidArgumentError(violations)
+	}
+	afterCreatedAt, afterID, err := util.DecodeCursor(req.GetCursor())
+	if err != nil {
+		return nil, invalidArgumentError([]*errdetails.BadRequest_FieldViolation{fieldViolation("cursor", err)})
+	}
+
+	account, err := server.authorizedAccount(ctx, req.GetAccountId(), authPayload, util.BankerRole, util.AdminRole)
+	if err != nil {
+		return nil, err
+	}
+
+	arg := db.ListAccountEntriesParams{
+		AccountID:      account.ID,
+		FromTime:       util.TimeToSqlNullTime(convertOptionalTimestamp(req.GetFromTime())),
+		ToTime:         util.TimeToSqlNullTime(convertOptionalTimestamp(req.GetToTime())),
+		AfterCreatedAt: afterCreatedAt,
+		AfterID:        afterID,
+		RowLimit:       req.GetLimit(),
+	}
+	entries, err := server.store.ListAccountEntries(ctx, arg)
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to list account entries: %v", err)
+	}
+	response := &pb.ListAccountEntriesResponse{
+		Entries: make([]*pb.StatementEntry, 0, len(entries)),
+	}
+	for _, entry := range entries {
+		response.Entries = append(response.Entries, convertStatementEntry(entry))
+	}
+	// a full page means there may be more rows after the last one
+	if len(entries) == int(req.GetLimit()) {
+		last := entries[len(entries)-1]
+		response.NextCursor = util.EncodeCursor(last.CreatedAt, last.ID)
+	}
+	return response, nil
+}
+
+func validateListAccountEntriesRequest(req *pb.ListAccountEntriesRequest) (violations []*errdetails.BadRequest_FieldViolation) {
+	if err := validator.ValidateAccountId(req.GetAccountId()); err != nil {
+		violations = append(violations, fieldViolation("accountId", err))
+	}
+	if err := validator.ValidateIntRange(int64(req.GetLimit()), 5, 1000); err != nil {
+		violations = append(violations, fieldViolation("limit", err))
+	}
+	if req.FromTime != nil && req.ToTime != nil && !req.GetFromTime().AsTime().Before(req.GetToTime().AsTime()) {
+		violations = append(violations, fieldViolation("fromTime", fmt.Errorf("must be before toTime")))
+	}
+	return violations
+}
@@ -0,0 +1,51 @@
+package grpcapi
+
+import (
+	"context"
+	"fmt"
+	db "simple_bank/db/sqlc"
+	"simple_bank/pb"
+	"simple_bank/validator"
+
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func (server *Server) ListAccounts(ctx context.Context, req *pb.ListAccountsRequest) (*pb.ListAccountsResponse, error) {
+	authPayload, err := server.authorizeUser(ctx)
+	if err != nil {
+		return nil, unauthenticatedError(err)
+	}
+	violations := validateListAccountsRequest(req)
+	if violations != nil {
+		return nil, invalidArgumentError(violations)
+	}
+
+	arg := db.GetAccountsParams{
+		Username: authPayload.Username,
+		Limit:    req.GetLimit(),
+		Offset:   req.GetOffset() * req.GetLimit(),
+	}
+	accounts, err := server.store.GetAccounts(ctx, arg)
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to list accounts: %v", err)
+	}
+	response := &pb.ListAccountsResponse{
+		Accounts: make([]*pb.Account, 0, len(accounts)),
+	}
+	for _, account := range accounts {
+		response.Accounts = append(response.Accounts, convertAccount(account))
+	}
+	return response, nil
+}
+
+func validateListAccountsRequest(req *pb.ListAccountsRequest) (violations []*errdetails.BadRequest_FieldViolation) {
+	if req.GetOffset() < 0 {
+		violations = append(violations, fieldViolation("offset", fmt.Errorf("must not be negative")))
+	}
+	if err := validator.ValidateIntRange(int64(req.GetLimit()), 5, 1000); err != nil {
+		violations = append(violations, fieldViolation("limit", err))
+	}
+	return violations
+}
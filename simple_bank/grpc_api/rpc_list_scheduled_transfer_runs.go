@@ -0,0 +1,52 @@
+package grpcapi
+
+import (
+	"context"
+	"fmt"
+	db "simple_bank/db/sqlc"
+	"simple_bank/pb"
+	"simple_bank/validator"
+
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// ListScheduledTransferRuns returns the attempts of a scheduled transfer, latest first
+func (server *Server) ListScheduledTransferRuns(ctx context.Context, req *pb.ListScheduledTransferRunsRequest) (*pb.ListScheduledTransferRunsResponse, error) {
+	scheduledTransfer, err := server.validScheduledTransfer(ctx, req.GetId())
+	if err != nil {
+		return nil, err
+	}
+	violations := validateListScheduledTransferRunsRequest(req)
+	if violations != nil {
+		return nil, invalidArgumentError(violations)
+	}
+
+	arg := db.ListScheduledTransferRunsParams{
+		ScheduledTransferId: scheduledTransfer.ID,
+		Limit:               req.GetLimit(),
+		Offset:              req.GetOffset() * req.GetLimit(),
+	}
+	runs, err := server.store.ListScheduledTransferRuns(ctx, arg)
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to list scheduled transfer runs: %v", err)
+	}
+	response := &pb.ListScheduledTransferRunsResponse{
+		Runs: make([]*pb.ScheduledTransferRun, 0, len(runs)),
+	}
+	for _, run := range runs {
+		response.Runs = append(response.Runs, convertScheduledTransferRun(run))
+	}
+	return response, nil
+}
+
+func validateListScheduledTransferRunsRequest(req *pb.ListScheduledTransferRunsRequest) (violations []*errdetails.BadRequest_FieldViolation) {
+	if req.GetOffset() < 0 {
+		violations = append(violations, fieldViolation("offset", fmt.Errorf("must not be negative")))
+	}
+	if err := validator.ValidateIntRange(int64(req.GetLimit()), 5, 1000); err != nil {
+		violations = append(violations, fieldViolation("limit", err))
+	}
+	return violations
+}
@@ -0,0 +1,51 @@
+package grpcapi
+
+import (
+	"context"
+	"fmt"
+	db "simple_bank/db/sqlc"
+	"simple_bank/pb"
+	"simple_bank/validator"
+
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func (server *Server) ListScheduledTransfers(ctx context.Context, req *pb.ListScheduledTransfersRequest) (*pb.ListScheduledTransfersResponse, error) {
+	authPayload, err := server.authorizeUser(ctx)
+	if err != nil {
+		return nil, unauthenticatedError(err)
+	}
+	violations := validateListScheduledTransfersRequest(req)
+	if violations != nil {
+		return nil, invalidArgumentError(violations)
+	}
+
+	arg := db.ListScheduledTransfersParams{
+		Username: authPayload.Username,
+		Limit:    req.GetLimit(),
+		Offset:   req.GetOffset() * req.GetLimit(),
+	}
+	scheduledTransfers, err := server.store.ListScheduledTransfers(ctx, arg)
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to list scheduled transfers: %v", err)
+	}
+	response := &pb.ListScheduledTransfersResponse{
+		ScheduledTransfers: make([]*pb.ScheduledTransfer, 0, len(scheduledTransfers)),
+	}
+	for _, scheduledTransfer := range scheduledTransfers {
+		response.ScheduledTransfers = append(response.ScheduledTransfers, convertScheduledTransfer(scheduledTransfer))
+	}
+	return response, nil
+}
+
+func validateListScheduledTransfersRequest(req *pb.ListScheduledTransfersRequest) (violations []*errdetails.BadRequest_FieldViolation) {
+	if req.GetOffset() < 0 {
+		violations = append(violations, fieldViolation("offset", fmt.Errorf("must not be negative")))
+	}
+	if err := validator.ValidateIntRange(int64(req.GetLimit()), 5, 1000); err != nil {
+		violations = append(violations, fieldViolation("limit", err))
+	}
+	return violations
+}
@@ -0,0 +1,102 @@
+package grpcapi
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	db "simple_bank/db/sqlc"
+	"simple_bank/pb"
+	"simple_bank/util"
+	"simple_bank/validator"
+
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// ListTransfers returns the transfers that touch any account of the authenticated user
+func (server *Server) ListTransfers(ctx context.Context, req *pb.ListTransfersRequest) (*pb.ListTransfersResponse, error) {
+	authPayload, err := server.authorizeUser(ctx)
+	if err != nil {
+		return nil, unauthenticatedError(err)
+	}
+	violations := validateListTransfersRequest(req)
+	if violations != nil {
+		return nil, invalidArgumentError(violations)
+	}
+	afterCreatedAt, afterID, err := util.DecodeCursor(req.GetCursor())
+	if err != nil {
+		return nil, invalidArgumentError([]*errdetails.BadRequest_FieldViolation{fieldViolation("cursor", err)})
+	}
+	if req.GetAccountId() != 0 {
+		account, err := server.store.GetAccount(ctx, req.GetAccountId())
+		if err != nil {
+			if err == sql.ErrNoRows {
+				return nil, status.Errorf(codes.NotFound, "account not found: %v", err)
+			}
+			return nil, status.Errorf(codes.Internal, "failed to get account: %v", err)
+		}
+		if account.Username != authPayload.Username {
+			return nil, status.Errorf(codes.PermissionDenied, "account doesn't belong to the authenticated user")
+		}
+	}
+
+	arg := db.ListUserTransfersParams{
+		Username:       authPayload.Username,
+		Direction:      req.GetDirection(),
+		AccountID:      util.Int64ToSqlNullInt64(req.GetAccountId()),
+		CounterpartyID: util.Int64ToSqlNullInt64(req.GetCounterpartyId()),
+		FromTime:       util.TimeToSqlNullTime(convertOptionalTimestamp(req.GetFromTime())),
+		ToTime:         util.TimeToSqlNullTime(convertOptionalTimestamp(req.GetToTime())),
+		MinAmount:      util.Int64ToSqlNullInt64(req.GetMinAmount()),
+		MaxAmount:      util.Int64ToSqlNullInt64(req.GetMaxAmount()),
+		AfterCreatedAt: afterCreatedAt,
+		AfterID:        afterID,
+		RowLimit:       req.GetLimit(),
+	}
+	transfers, err := server.store.ListUserTransfers(ctx, arg)
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to list transfers: %v", err)
+	}
+	response := &pb.ListTransfersResponse{
+		Transfers: make([]*pb.Transfer, 0, len(transfers)),
+	}
+	for _, transfer := range transfers {
+		response.Transfers = append(response.Transfers, convertTransfer(transfer))
+	}
+	// a full page means there may be more rows after the last one
+	if len(transfers) == int(req.GetLimit()) {
+		last := transfers[len(transfers)-1]
+		response.NextCursor = util.EncodeCursor(last.CreatedAt, last.ID)
+	}
+	return response, nil
+}
+
+func validateListTransfersRequest(req *pb.ListTransfersRequest) (violations []*errdetails.BadRequest_FieldViolation) {
+	if req.GetAccountId() != 0 {
+		if err := validator.ValidateAccountId(req.GetAccountId()); err != nil {
+			violations = append(violations, fieldViolation("accountId", err))
+		}
+	}
+	if req.GetCounterpartyId() != 0 {
+		if err := validator.ValidateAccountId(req.GetCounterpartyId()); err != nil {
+			violations = append(violations, fieldViolation("counterpartyId", err))
+		}
+	}
+	if err := validator.ValidateTransferDirection(req.GetDirection()); err != nil {
+		violations = append(violations, fieldViolation("direction", err))
+	}
+	if req.GetMinAmount() < 0 {
+		violations = append(violations, fieldViolation("minAmount", fmt.Errorf("must not be negative")))
+	}
+	if req.GetMaxAmount() < 0 || (req.GetMaxAmount() != 0 && req.GetMaxAmount() < req.GetMinAmount()) {
+		violations = append(violations, fieldViolation("maxAmount", fmt.Errorf("must not be negative nor lower than minAmount")))
+	}
+	if req.FromTime != nil && req.ToTime != nil && !req.GetFromTime().AsTime().Before(req.GetToTime().AsTime()) {
+		violations = append(violations, fieldViolation("fromTime", fmt.Errorf("must be before toTime")))
+	}
+	if err := validator.ValidateIntRange(int64(req.GetLimit()), 5, 1000); err != nil {
+		violations = append(violations, fieldViolation("limit", err))
+	}
+	return violations
+}
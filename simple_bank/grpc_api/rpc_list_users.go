@@ -0,0 +1,50 @@
+package grpcapi
+
+import (
+	"context"
+	"fmt"
+	db "simple_bank/db/sqlc"
+	"simple_bank/pb"
+	"simple_bank/util"
+	"simple_bank/validator"
+
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func (server *Server) ListUsers(ctx context.Context, req *pb.ListUsersRequest) (*pb.ListUsersResponse, error) {
+	_, err := server.authorizeRoles(ctx, util.AdminRole)
+	if err != nil {
+		return nil, err
+	}
+	violations := validateListUsersRequest(req)
+	if violations != nil {
+		return nil, invalidArgumentError(violations)
+	}
+
+	users, err := server.store.GetUsers(ctx, db.GetUsersParams{
+		Limit:  req.GetLimit(),
+		Offset: req.GetOffset() * req.GetLimit(),
+	})
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to list users: %v", err)
+	}
+	response := &pb.ListUsersResponse{
+		Users: make([]*pb.User, 0, len(users)),
+	}
+	for _, user := range users {
+		response.Users = append(response.Users, convertUser(user))
+	}
+	return response, nil
+}
+
+func validateListUsersRequest(req *pb.ListUsersRequest) (violations []*errdetails.BadRequest_FieldViolation) {
+	if req.GetOffset() < 0 {
+		violations = append(violations, fieldViolation("offset", fmt.Errorf("must not be negative")))
+	}
+	if err := validator.ValidateIntRange(int64(req.GetLimit()), 5, 100); err != nil {
+		violations = append(violations, fieldViolation("limit", err))
+	}
+	return violations
+}
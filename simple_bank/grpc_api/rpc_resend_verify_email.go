@@ -0,0 +1,64 @@
+package grpcapi
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	db "simple_bank/db/sqlc"
+	"simple_bank/pb"
+	"simple_bank/util"
+	"simple_bank/validator"
+	"simple_bank/worker"
+
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+// ResendVerifyEmail sends a new verification code to the email of the authenticated user, e.g.
+// after the previous one expired. Like for a new user the email is sent in the background.
+func (server *Server) ResendVerifyEmail(ctx context.Context, req *pb.ResendVerifyEmailRequest) (*pb.ResendVerifyEmailResponse, error) {
+	authPayload, err := server.authorizeUser(ctx)
+	if err != nil {
+		return nil, unauthenticatedError(err)
+	}
+	violations := validateResendVerifyEmailRequest(req)
+	if violations != nil {
+		return nil, invalidArgumentError(violations)
+	}
+	if req.GetUsername() != authPayload.Username {
+		return nil, status.Errorf(codes.PermissionDenied, "cannot verify the email of another user")
+	}
+
+	result, err := server.store.ResendVerifyEmailTx(ctx, db.ResendVerifyEmailTxParams{
+		Username:   req.GetUsername(),
+		SecretCode: util.RandomString(32),
+		AfterCreate: func(q db.Querier, user db.User, verifyEmail db.VerifyEmail) error {
+			return server.taskDistributor.DistributeTaskSendVerifyEmail(ctx, q, &worker.PayloadSendVerifyEmail{
+				EmailId: verifyEmail.ID,
+			})
+		},
+	})
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, status.Errorf(codes.NotFound, "user not found: %v", err)
+		}
+		if errors.Is(err, db.ErrEmailAlreadyVerified) {
+			return nil, status.Errorf(codes.FailedPrecondition, "%v", err)
+		}
+		return nil, status.Errorf(codes.Internal, "failed to resend verification email: %v", err)
+	}
+	response := &pb.ResendVerifyEmailResponse{
+		Email:     result.VerifyEmail.Email,
+		ExpiredAt: timestamppb.New(result.VerifyEmail.ExpiredAt),
+	}
+	return response, nil
+}
+
+func validateResendVerifyEmailRequest(req *pb.ResendVerifyEmailRequest) (violations []*errdetails.BadRequest_FieldViolation) {
+	if err := validator.ValidateUsername(req.GetUsername()); err != nil {
+		violations = append(violations, fieldViolation("username", err))
+	}
+	return violations
+}
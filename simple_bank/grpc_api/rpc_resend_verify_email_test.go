@@ -0,0 +1,117 @@
+package grpcapi
+
+import (
+	"context"
+	mockdb "simple_bank/db/mock"
+	db "simple_bank/db/sqlc"
+	"simple_bank/pb"
+	"simple_bank/token"
+	"simple_bank/util"
+	"simple_bank/worker"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+	"google.golang.org/grpc/codes"
+)
+
+func TestResendVerifyEmailRPC(t *testing.T) {
+	username := util.RandomUsername()
+	verifyEmail := db.VerifyEmail{
+		ID:        1,
+		Username:  username,
+		Email:     util.RandomEmail(),
+		ExpiredAt: time.Now().Add(15 * time.Minute),
+	}
+
+	testCases := []struct {
+		name          string
+		req           *pb.ResendVerifyEmailRequest
+		buildStubs    func(store *mockdb.MockStore)
+		buildContext  func(t *testing.T, tokenMaker token.Maker) context.Context
+		checkResponse func(t *testing.T, res *pb.ResendVerifyEmailResponse, err error)
+	}{
+		{
+			name: "OK",
+			req:  &pb.ResendVerifyEmailRequest{Username: username},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					CreateTask(gomock.Any(), gomock.Any()).
+					Times(1).
+					DoAndReturn(func(_ any, arg db.CreateTaskParams) (db.Task, error) {
+						require.Equal(t, worker.TaskSendVerifyEmail, arg.Type)
+						require.JSONEq(t, `{"emailId": 1}`, string(arg.Payload))
+						return db.Task{ID: 1, Type: arg.Type, Payload: arg.Payload}, nil
+					})
+				store.EXPECT().
+					ResendVerifyEmailTx(gomock.Any(), gomock.Any()).
+					Times(1).
+					DoAndReturn(func(_ any, arg db.ResendVerifyEmailTxParams) (db.ResendVerifyEmailTxResult, error) {
+						require.Equal(t, username, arg.Username)
+						require.Len(t, arg.SecretCode, 32)
+						user := db.User{Username: username}
+						err := arg.AfterCreate(store, user, verifyEmail)
+						require.NoError(t, err)
+						return db.ResendVerifyEmailTxResult{User: user, VerifyEmail: verifyEmail}, nil
+					})
+			},
+			buildContext: func(t *testing.T, tokenMaker token.Maker) context.Context {
+				return newContextWithBearerToken(t, tokenMaker, username, util.DepositorRole, time.Minute)
+			},
+			checkResponse: func(t *testing.T, res *pb.ResendVerifyEmailResponse, err error) {
+				require.NoError(t, err)
+				require.Equal(t, verifyEmail.Email, res.GetEmail())
+				require.WithinDuration(t, verifyEmail.ExpiredAt, res.GetExpiredAt().AsTime(), time.Second)
+			},
+		},
+		{
+			name: "AlreadyVerified",
+			req:  &pb.ResendVerifyEmailRequest{Username: username},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					ResendVerifyEmailTx(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(db.ResendVerifyEmailTxResult{}, db.ErrEmailAlreadyVerified)
+			},
+			buildContext: func(t *testing.T, tokenMaker token.Maker) context.Context {
+				return newContextWithBearerToken(t, tokenMaker, username, util.DepositorRole, time.Minute)
+			},
+			checkResponse: func(t *testing.T, res *pb.ResendVerifyEmailResponse, err error) {
+				requireStatusCode(t, err, codes.FailedPrecondition)
+			},
+		},
+		{
+			name: "OtherUser",
+			req:  &pb.ResendVerifyEmailRequest{Username: username},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					ResendVerifyEmailTx(gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			buildContext: func(t *testing.T, tokenMaker token.Maker) context.Context {
+				return newContextWithBearerToken(t, tokenMaker, util.RandomUsername(), util.DepositorRole, time.Minute)
+			},
+			checkResponse: func(t *testing.T, res *pb.ResendVerifyEmailResponse, err error) {
+				requireStatusCode(t, err, codes.PermissionDenied)
+			},
+		},
+	}
+
+	for i := range testCases {
+		tc := testCases[i]
+
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			store := mockdb.NewMockStore(ctrl)
+			tc.buildStubs(store)
+
+			server := newTestServer(t, store)
+			ctx := tc.buildContext(t, server.tokenMaker)
+			res, err := server.ResendVerifyEmail(ctx, tc.req)
+			tc.checkResponse(t, res, err)
+		})
+	}
+}
@@ -0,0 +1,93 @@
+package grpcapi
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	db "simple_bank/db/sqlc"
+	"simple_bank/exchange"
+	"simple_bank/pb"
+	"simple_bank/util"
+	"simple_bank/validator"
+
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// ReverseTransfer gives the money of a transfer back to its sender. Only bankers and admins can
+// reverse transfers, so senders ask them to refund a mistaken transfer.
+func (server *Server) ReverseTransfer(ctx context.Context, req *pb.ReverseTransferRequest) (*pb.ReverseTransferResponse, error) {
+	_, err := server.authorizeRoles(ctx, util.BankerRole, util.AdminRole)
+	if err != nil {
+		return nil, err
+	}
+	if violations := validateReverseTransferRequest(req); violations != nil {
+		return nil, invalidArgumentError(violations)
+	}
+
+	transfer, err := server.store.GetTransfer(ctx, req.GetTransferId())
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, status.Errorf(codes.NotFound, "transfer [%d] not found: %v", req.GetTransferId(), err)
+		}
+		return nil, status.Errorf(codes.Internal, "failed to get transfer: %v", err)
+	}
+	payer, err := server.store.GetAccount(ctx, transfer.ToAccountId)
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to get account [%d]: %v", transfer.ToAccountId, err)
+	}
+	payee, err := server.store.GetAccount(ctx, transfer.FromAccountId)
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to get account [%d]: %v", transfer.FromAccountId, err)
+	}
+	var amount util.Money
+	if req.GetAmount() != 0 || req.GetDecimalAmount() != "" {
+		amount, err = requestAmount(req.GetAmount(), req.GetDecimalAmount(), payee.Currency)
+		if err != nil {
+			return nil, invalidArgumentError([]*errdetails.BadRequest_FieldViolation{fieldViolation("decimalAmount", err)})
+		}
+	}
+
+	result, err := server.store.ReverseTransferTx(ctx, db.ReverseTransferTxParams{
+		TransferID: transfer.ID,
+		Amount:     amount.Amount,
+	})
+	if err != nil {
+		if errors.Is(err, db.ErrTransferAlreadyReversed) || errors.Is(err, db.ErrTransferNotReversible) {
+			return nil, status.Errorf(codes.FailedPrecondition, "%v", err)
+		}
+		if errors.Is(err, db.ErrInsufficientFunds) || errors.Is(err, db.ErrAccountNotActive) {
+			return nil, status.Errorf(codes.FailedPrecondition, "%v", err)
+		}
+		if errors.Is(err, db.ErrReversalExceedsTransfer) || errors.Is(err, exchange.ErrAmountTooSmall) {
+			return nil, status.Errorf(codes.InvalidArgument, "%v", err)
+		}
+		return nil, status.Errorf(codes.Internal, "failed to reverse transfer: %v", err)
+	}
+	response := &pb.ReverseTransferResponse{
+		Transfer:    convertTransfer(result.Transfer),
+		FromAccount: convertAccount(result.FromAccount),
+		ToAccount:   convertAccount(result.ToAccount),
+		FromEntry:   convertEntry(result.FromEntry),
+		ToEntry:     convertEntry(result.ToEntry),
+
+		FormattedAmount:   util.FormatAmount(result.Transfer.Amount, payer.Currency),
+		FormattedToAmount: util.FormatAmount(result.Transfer.ToAmount, payee.Currency),
+	}
+	return response, nil
+}
+
+func validateReverseTransferRequest(req *pb.ReverseTransferRequest) (violations []*errdetails.BadRequest_FieldViolation) {
+	if err := validator.ValidateTransferId(req.GetTransferId()); err != nil {
+		violations = append(violations, fieldViolation("transferId", err))
+	}
+	if req.GetAmount() < 0 {
+		violations = append(violations, fieldViolation("amount", fmt.Errorf("amount must be greater than 0")))
+	}
+	if req.GetDecimalAmount() != "" && req.GetAmount() != 0 {
+		violations = append(violations, fieldViolation("amount", fmt.Errorf("amount and decimalAmount are mutually exclusive")))
+	}
+	return violations
+}
@@ -0,0 +1,125 @@
+package grpcapi
+
+import (
+	"context"
+	"database/sql"
+	mockdb "simple_bank/db/mock"
+	db "simple_bank/db/sqlc"
+	"simple_bank/pb"
+	"simple_bank/token"
+	"simple_bank/util"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+	"google.golang.org/grpc/codes"
+)
+
+func TestReverseTransferRPC(t *testing.T) {
+	sender := randomAccount(util.RandomUsername())
+	receiver := randomAccount(util.RandomUsername())
+	receiver.ID = sender.ID + 1
+	receiver.Currency = sender.Currency
+	original := db.Transfer{
+		ID:            util.RandomInt(1, 1000),
+		FromAccountId: sender.ID,
+		ToAccountId:   receiver.ID,
+		Amount:        1000,
+		ToAmount:      1000,
+		ExchangeRate:  "1",
+	}
+
+	testCases := []struct {
+		name          string
+		req           *pb.ReverseTransferRequest
+		buildStubs    func(store *mockdb.MockStore)
+		buildContext  func(t *testing.T, tokenMaker token.Maker) context.Context
+		checkResponse func(t *testing.T, res *pb.ReverseTransferResponse, err error)
+	}{
+		{
+			name: "OK",
+			req:  &pb.ReverseTransferRequest{TransferId: original.ID},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetTransfer(gomock.Any(), gomock.Eq(original.ID)).Times(1).Return(original, nil)
+				store.EXPECT().GetAccount(gomock.Any(), gomock.Eq(receiver.ID)).Times(1).Return(receiver, nil)
+				store.EXPECT().GetAccount(gomock.Any(), gomock.Eq(sender.ID)).Times(1).Return(sender, nil)
+				arg := db.ReverseTransferTxParams{TransferID: original.ID}
+				result := db.TransferTxResult{Transfer: db.Transfer{
+					FromAccountId:      receiver.ID,
+					ToAccountId:        sender.ID,
+					Amount:             original.ToAmount,
+					ToAmount:           original.Amount,
+					ReversedTransferId: sql.NullInt64{Int64: original.ID, Valid: true},
+				}}
+				store.EXPECT().ReverseTransferTx(gomock.Any(), gomock.Eq(arg)).Times(1).Return(result, nil)
+			},
+			buildContext: func(t *testing.T, tokenMaker token.Maker) context.Context {
+				return newContextWithBearerToken(t, tokenMaker, util.RandomUsername(), util.BankerRole, time.Minute)
+			},
+			checkResponse: func(t *testing.T, res *pb.ReverseTransferResponse, err error) {
+				require.NoError(t, err)
+				require.Equal(t, original.ID, res.GetTransfer().GetReversedTransferId())
+			},
+		},
+		{
+			name: "ReceiverCannotReverse",
+			req:  &pb.ReverseTransferRequest{TransferId: original.ID},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetTransfer(gomock.Any(), gomock.Any()).Times(0)
+				store.EXPECT().ReverseTransferTx(gomock.Any(), gomock.Any()).Times(0)
+			},
+			buildContext: func(t *testing.T, tokenMaker token.Maker) context.Context {
+				return newContextWithBearerToken(t, tokenMaker, receiver.Username, util.DepositorRole, time.Minute)
+			},
+			checkResponse: func(t *testing.T, res *pb.ReverseTransferResponse, err error) {
+				requireStatusCode(t, err, codes.PermissionDenied)
+			},
+		},
+		{
+			name: "NotFound",
+			req:  &pb.ReverseTransferRequest{TransferId: original.ID},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetTransfer(gomock.Any(), gomock.Eq(original.ID)).Times(1).Return(db.Transfer{}, sql.ErrNoRows)
+				store.EXPECT().ReverseTransferTx(gomock.Any(), gomock.Any()).Times(0)
+			},
+			buildContext: func(t *testing.T, tokenMaker token.Maker) context.Context {
+				return newContextWithBearerToken(t, tokenMaker, util.RandomUsername(), util.AdminRole, time.Minute)
+			},
+			checkResponse: func(t *testing.T, res *pb.ReverseTransferResponse, err error) {
+				requireStatusCode(t, err, codes.NotFound)
+			},
+		},
+		{
+			name: "NoAuthorization",
+			req:  &pb.ReverseTransferRequest{TransferId: original.ID},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetTransfer(gomock.Any(), gomock.Any()).Times(0)
+				store.EXPECT().ReverseTransferTx(gomock.Any(), gomock.Any()).Times(0)
+			},
+			buildContext: func(t *testing.T, tokenMaker token.Maker) context.Context {
+				return context.Background()
+			},
+			checkResponse: func(t *testing.T, res *pb.ReverseTransferResponse, err error) {
+				requireStatusCode(t, err, codes.Unauthenticated)
+			},
+		},
+	}
+
+	for i := range testCases {
+		tc := testCases[i]
+
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			store := mockdb.NewMockStore(ctrl)
+			tc.buildStubs(store)
+
+			server := newTestServer(t, store)
+			ctx := tc.buildContext(t, server.tokenMaker)
+			res, err := server.ReverseTransfer(ctx, tc.req)
+			tc.checkResponse(t, res, err)
+		})
+	}
+}
@@ -0,0 +1,80 @@
+package grpcapi
+
+import (
+	"context"
+	db "simple_bank/db/sqlc"
+	"simple_bank/pb"
+	"simple_bank/util"
+	"simple_bank/validator"
+
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// UpdateAccountLimits replaces all limits of the account, a limit that is not set is removed.
+// Only bankers and admins can change limits, so owners cannot lift the limits that are set on them.
+func (server *Server) UpdateAccountLimits(ctx context.Context, req *pb.UpdateAccountLimitsRequest) (*pb.UpdateAccountLimitsResponse, error) {
+	authPayload, err := server.authorizeRoles(ctx, util.BankerRole, util.AdminRole)
+	if err != nil {
+		return nil, err
+	}
+	violations := validateUpdateAccountLimitsRequest(req)
+	if violations != nil {
+		return nil, invalidArgumentError(violations)
+	}
+	_, err = server.authorizedAccount(ctx, req.GetAccountId(), authPayload, util.BankerRole, util.AdminRole)
+	if err != nil {
+		return nil, err
+	}
+
+	accountLimit, err := server.store.UpsertAccountLimit(ctx, db.UpsertAccountLimitParams{
+		AccountId:         req.GetAccountId(),
+		MaxTransferAmount: convertOptionalInt64(req.MaxTransferAmount),
+		DailyAmount:       convertOptionalInt64(req.DailyAmount),
+		MonthlyAmount:     convertOptionalInt64(req.MonthlyAmount),
+		HourlyTransfers:   convertOptionalInt32(req.HourlyTransfers),
+	})
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to update account limits: %v", err)
+	}
+	return &pb.UpdateAccountLimitsResponse{Limits: convertAccountLimit(accountLimit)}, nil
+}
+
+func validateUpdateAccountLimitsRequest(req *pb.UpdateAccountLimitsRequest) (violations []*errdetails.BadRequest_FieldViolation) {
+	if err := validator.ValidateAccountId(req.GetAccountId()); err != nil {
+		violations = append(violations, fieldViolation("accountId", err))
+	}
+	return append(violations, validateTransferLimits(req.MaxTransferAmount, req.DailyAmount, req.MonthlyAmount, req.HourlyTransfers)...)
+}
+
+// validateTransferLimits checks the limits that are set
+func validateTransferLimits(
+	maxTransferAmount *int64,
+	dailyAmount *int64,
+	monthlyAmount *int64,
+	hourlyTransfers *int32,
+) (violations []*errdetails.BadRequest_FieldViolation) {
+	amounts := []struct {
+		field string
+		value *int64
+	}{
+		{"maxTransferAmount", maxTransferAmount},
+		{"dailyAmount", dailyAmount},
+		{"monthlyAmount", monthlyAmount},
+	}
+	for _, amount := range amounts {
+		if amount.value == nil {
+			continue
+		}
+		if err := validator.ValidateTransferLimit(*amount.value); err != nil {
+			violations = append(violations, fieldViolation(amount.field, err))
+		}
+	}
+	if hourlyTransfers != nil {
+		if err := validator.ValidateTransferLimit(int64(*hourlyTransfers)); err != nil {
+			violations = append(violations, fieldViolation("hourlyTransfers", err))
+		}
+	}
+	return violations
+}
@@ -0,0 +1,61 @@
+package grpcapi
+
+import (
+	"context"
+	"errors"
+	db "simple_bank/db/sqlc"
+	"simple_bank/pb"
+	"simple_bank/util"
+	"simple_bank/validator"
+
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func (server *Server) UpdateAccountStatus(ctx context.Context, req *pb.UpdateAccountStatusRequest) (*pb.UpdateAccountStatusResponse, error) {
+	authPayload, err := server.authorizeUser(ctx)
+	if err != nil {
+		return nil, unauthenticatedError(err)
+	}
+	violations := validateUpdateAccountStatusRequest(req)
+	if violations != nil {
+		return nil, invalidArgumentError(violations)
+	}
+
+	_, err = server.authorizedAccount(ctx, req.GetId(), authPayload, util.BankerRole, util.AdminRole)
+	if err != nil {
+		return nil, err
+	}
+	// owners can freeze their accounts, but only bankers can lift a freeze. The transaction checks it
+	// against the locked account, so a freeze placed meanwhile is not lifted by the owner.
+	account, err := server.store.UpdateAccountStatusTx(ctx, db.UpdateAccountStatusTxParams{
+		AccountID:     req.GetId(),
+		Status:        req.GetStatus(),
+		AllowUnfreeze: hasRole(authPayload, util.BankerRole, util.AdminRole),
+	})
+	if err != nil {
+		return nil, accountStatusError(err, "failed to update account status")
+	}
+	return &pb.UpdateAccountStatusResponse{Account: convertAccount(account)}, nil
+}
+
+func accountStatusError(err error, message string) error {
+	switch {
+	case errors.Is(err, db.ErrInvalidAccountStatusTransition), errors.Is(err, db.ErrAccountBalanceNotZero):
+		return status.Errorf(codes.FailedPrecondition, "%v", err)
+	case errors.Is(err, db.ErrUnfreezeNotAllowed):
+		return status.Errorf(codes.PermissionDenied, "%v", err)
+	}
+	return status.Errorf(codes.Internal, "%s: %v", message, err)
+}
+
+func validateUpdateAccountStatusRequest(req *pb.UpdateAccountStatusRequest) (violations []*errdetails.BadRequest_FieldViolation) {
+	if err := validator.ValidateAccountId(req.GetId()); err != nil {
+		violations = append(violations, fieldViolation("id", err))
+	}
+	if err := validator.ValidateAccountStatus(req.GetStatus()); err != nil {
+		violations = append(violations, fieldViolation("status", err))
+	}
+	return violations
+}
@@ -0,0 +1,110 @@
+package grpcapi
+
+import (
+	"context"
+	mockdb "simple_bank/db/mock"
+	db "simple_bank/db/sqlc"
+	"simple_bank/pb"
+	"simple_bank/token"
+	"simple_bank/util"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+	"google.golang.org/grpc/codes"
+)
+
+func TestUpdateAccountStatusRPC(t *testing.T) {
+	account := randomAccount(util.RandomUsername())
+	frozenAccount := account
+	frozenAccount.Status = db.AccountStatusFrozen
+
+	testCases := []struct {
+		name          string
+		req           *pb.UpdateAccountStatusRequest
+		buildStubs    func(store *mockdb.MockStore)
+		buildContext  func(t *testing.T, tokenMaker token.Maker) context.Context
+		checkResponse func(t *testing.T, res *pb.UpdateAccountStatusResponse, err error)
+	}{
+		{
+			name: "OwnerFreezes",
+			req:  &pb.UpdateAccountStatusRequest{Id: account.ID, Status: db.AccountStatusFrozen},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetAccount(gomock.Any(), gomock.Eq(account.ID)).Times(1).Return(account, nil)
+				arg := db.UpdateAccountStatusTxParams{AccountID: account.ID, Status: db.AccountStatusFrozen}
+				store.EXPECT().UpdateAccountStatusTx(gomock.Any(), gomock.Eq(arg)).Times(1).Return(frozenAccount, nil)
+			},
+			buildContext: func(t *testing.T, tokenMaker token.Maker) context.Context {
+				return newContextWithBearerToken(t, tokenMaker, account.Username, util.DepositorRole, time.Minute)
+			},
+			checkResponse: func(t *testing.T, res *pb.UpdateAccountStatusResponse, err error) {
+				require.NoError(t, err)
+				require.Equal(t, db.AccountStatusFrozen, res.GetAccount().GetStatus())
+			},
+		},
+		{
+			name: "OwnerCannotUnfreeze",
+			req:  &pb.UpdateAccountStatusRequest{Id: account.ID, Status: db.AccountStatusActive},
+			buildStubs: func(store *mockdb.MockStore) {
+				// the account looked active before the transaction locked it
+				store.EXPECT().GetAccount(gomock.Any(), gomock.Eq(account.ID)).Times(1).Return(account, nil)
+				arg := db.UpdateAccountStatusTxParams{AccountID: account.ID, Status: db.AccountStatusActive}
+				store.EXPECT().UpdateAccountStatusTx(gomock.Any(), gomock.Eq(arg)).Times(1).Return(db.Account{}, db.ErrUnfreezeNotAllowed)
+			},
+			buildContext: func(t *testing.T, tokenMaker token.Maker) context.Context {
+				return newContextWithBearerToken(t, tokenMaker, account.Username, util.DepositorRole, time.Minute)
+			},
+			checkResponse: func(t *testing.T, res *pb.UpdateAccountStatusResponse, err error) {
+				requireStatusCode(t, err, codes.PermissionDenied)
+			},
+		},
+		{
+			name: "BankerUnfreezes",
+			req:  &pb.UpdateAccountStatusRequest{Id: account.ID, Status: db.AccountStatusActive},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetAccount(gomock.Any(), gomock.Eq(account.ID)).Times(1).Return(frozenAccount, nil)
+				arg := db.UpdateAccountStatusTxParams{AccountID: account.ID, Status: db.AccountStatusActive, AllowUnfreeze: true}
+				store.EXPECT().UpdateAccountStatusTx(gomock.Any(), gomock.Eq(arg)).Times(1).Return(account, nil)
+			},
+			buildContext: func(t *testing.T, tokenMaker token.Maker) context.Context {
+				return newContextWithBearerToken(t, tokenMaker, util.RandomUsername(), util.BankerRole, time.Minute)
+			},
+			checkResponse: func(t *testing.T, res *pb.UpdateAccountStatusResponse, err error) {
+				require.NoError(t, err)
+				require.Equal(t, db.AccountStatusActive, res.GetAccount().GetStatus())
+			},
+		},
+		{
+			name: "OtherDepositor",
+			req:  &pb.UpdateAccountStatusRequest{Id: account.ID, Status: db.AccountStatusFrozen},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetAccount(gomock.Any(), gomock.Eq(account.ID)).Times(1).Return(account, nil)
+				store.EXPECT().UpdateAccountStatusTx(gomock.Any(), gomock.Any()).Times(0)
+			},
+			buildContext: func(t *testing.T, tokenMaker token.Maker) context.Context {
+				return newContextWithBearerToken(t, tokenMaker, util.RandomUsername(), util.DepositorRole, time.Minute)
+			},
+			checkResponse: func(t *testing.T, res *pb.UpdateAccountStatusResponse, err error) {
+				requireStatusCode(t, err, codes.PermissionDenied)
+			},
+		},
+	}
+
+	for i := range testCases {
+		tc := testCases[i]
+
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			store := mockdb.NewMockStore(ctrl)
+			tc.buildStubs(store)
+
+			server := newTestServer(t, store)
+			ctx := tc.buildContext(t, server.tokenMaker)
+			res, err := server.UpdateAccountStatus(ctx, tc.req)
+			tc.checkResponse(t, res, err)
+		})
+	}
+}
@@ -0,0 +1,46 @@
+package grpcapi
+
+import (
+	"context"
+	"database/sql"
+	db "simple_bank/db/sqlc"
+	"simple_bank/pb"
+	"simple_bank/util"
+	"simple_bank/validator"
+
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// UpdateCurrency enables or disables a currency. Once the servers refresh their currencies, requests in
+// a disabled currency are rejected, the accounts holding it keep their balance.
+func (server *Server) UpdateCurrency(ctx context.Context, req *pb.UpdateCurrencyRequest) (*pb.UpdateCurrencyResponse, error) {
+	_, err := server.authorizeRoles(ctx, util.AdminRole)
+	if err != nil {
+		return nil, err
+	}
+	violations := validateUpdateCurrencyRequest(req)
+	if violations != nil {
+		return nil, invalidArgumentError(violations)
+	}
+
+	currency, err := server.store.UpdateCurrencyEnabled(ctx, db.UpdateCurrencyEnabledParams{
+		Code:    req.GetCode(),
+		Enabled: req.GetEnabled(),
+	})
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, status.Errorf(codes.NotFound, "currency not found: %v", err)
+		}
+		return nil, status.Errorf(codes.Internal, "failed to update currency: %v", err)
+	}
+	return &pb.UpdateCurrencyResponse{Currency: convertCurrency(currency)}, nil
+}
+
+func validateUpdateCurrencyRequest(req *pb.UpdateCurrencyRequest) (violations []*errdetails.BadRequest_FieldViolation) {
+	if err := validator.ValidateCurrencyCode(req.GetCode()); err != nil {
+		violations = append(violations, fieldViolation("code", err))
+	}
+	return violations
+}
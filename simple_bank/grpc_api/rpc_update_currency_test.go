@@ -0,0 +1,89 @@
+package grpcapi
+
+import (
+	"context"
+	"database/sql"
+	mockdb "simple_bank/db/mock"
+	db "simple_bank/db/sqlc"
+	"simple_bank/pb"
+	"simple_bank/token"
+	"simple_bank/util"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+	"google.golang.org/grpc/codes"
+)
+
+func TestUpdateCurrencyRPC(t *testing.T) {
+	currency := db.Currency{Code: "USD", MinorUnit: 2, Symbol: "$", CreatedAt: time.Now()}
+
+	testCases := []struct {
+		name          string
+		req           *pb.UpdateCurrencyRequest
+		buildStubs    func(store *mockdb.MockStore)
+		buildContext  func(t *testing.T, tokenMaker token.Maker) context.Context
+		checkResponse func(t *testing.T, res *pb.UpdateCurrencyResponse, err error)
+	}{
+		{
+			name: "OK",
+			req:  &pb.UpdateCurrencyRequest{Code: currency.Code, Enabled: false},
+			buildStubs: func(store *mockdb.MockStore) {
+				arg := db.UpdateCurrencyEnabledParams{Code: currency.Code, Enabled: false}
+				store.EXPECT().UpdateCurrencyEnabled(gomock.Any(), gomock.Eq(arg)).Times(1).Return(currency, nil)
+			},
+			buildContext: func(t *testing.T, tokenMaker token.Maker) context.Context {
+				return newContextWithBearerToken(t, tokenMaker, "admin", util.AdminRole, time.Minute)
+			},
+			checkResponse: func(t *testing.T, res *pb.UpdateCurrencyResponse, err error) {
+				require.NoError(t, err)
+				require.Equal(t, currency.Code, res.GetCurrency().GetCode())
+				require.False(t, res.GetCurrency().GetEnabled())
+			},
+		},
+		{
+			name: "NotFound",
+			req:  &pb.UpdateCurrencyRequest{Code: "GBP", Enabled: true},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().UpdateCurrencyEnabled(gomock.Any(), gomock.Any()).Times(1).Return(db.Currency{}, sql.ErrNoRows)
+			},
+			buildContext: func(t *testing.T, tokenMaker token.Maker) context.Context {
+				return newContextWithBearerToken(t, tokenMaker, "admin", util.AdminRole, time.Minute)
+			},
+			checkResponse: func(t *testing.T, res *pb.UpdateCurrencyResponse, err error) {
+				requireStatusCode(t, err, codes.NotFound)
+			},
+		},
+		{
+			name: "DepositorNotAllowed",
+			req:  &pb.UpdateCurrencyRequest{Code: currency.Code, Enabled: false},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().UpdateCurrencyEnabled(gomock.Any(), gomock.Any()).Times(0)
+			},
+			buildContext: func(t *testing.T, tokenMaker token.Maker) context.Context {
+				return newContextWithBearerToken(t, tokenMaker, "user", util.DepositorRole, time.Minute)
+			},
+			checkResponse: func(t *testing.T, res *pb.UpdateCurrencyResponse, err error) {
+				requireStatusCode(t, err, codes.PermissionDenied)
+			},
+		},
+	}
+
+	for i := range testCases {
+		tc := testCases[i]
+
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			store := mockdb.NewMockStore(ctrl)
+			tc.buildStubs(store)
+
+			server := newTestServer(t, store)
+			ctx := tc.buildContext(t, server.tokenMaker)
+			res, err := server.UpdateCurrency(ctx, tc.req)
+			tc.checkResponse(t, res, err)
+		})
+	}
+}
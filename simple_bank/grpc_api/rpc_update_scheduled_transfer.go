@@ -0,0 +1,84 @@
+package grpcapi
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	db "simple_bank/db/sqlc"
+	"simple_bank/pb"
+	"simple_bank/validator"
+
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func (server *Server) UpdateScheduledTransfer(ctx context.Context, req *pb.UpdateScheduledTransferRequest) (*pb.UpdateScheduledTransferResponse, error) {
+	scheduledTransfer, err := server.validScheduledTransfer(ctx, req.GetId())
+	if err != nil {
+		return nil, err
+	}
+	violations := validateUpdateScheduledTransferRequest(req)
+	if violations != nil {
+		return nil, invalidArgumentError(violations)
+	}
+	if !isPending(scheduledTransfer) {
+		return nil, status.Errorf(codes.FailedPrecondition, "scheduled transfer is %s", scheduledTransfer.Status)
+	}
+
+	arg := db.UpdateScheduledTransferParams{
+		ID:     scheduledTransfer.ID,
+		Status: sql.NullString{String: req.GetStatus(), Valid: req.Status != nil},
+	}
+	if req.Amount != nil || req.DecimalAmount != nil {
+		fromAccount, err := server.store.GetAccount(ctx, scheduledTransfer.FromAccountId)
+		if err != nil {
+			return nil, status.Errorf(codes.Internal, "failed to get account: %v", err)
+		}
+		amount, err := requestAmount(req.GetAmount(), req.GetDecimalAmount(), fromAccount.Currency)
+		if err != nil {
+			return nil, invalidArgumentError([]*errdetails.BadRequest_FieldViolation{fieldViolation("decimalAmount", err)})
+		}
+		arg.Amount = sql.NullInt64{Int64: amount.Amount, Valid: true}
+	}
+	if req.Schedule != nil || req.RunAt != nil {
+		schedule := scheduledTransfer.Schedule
+		if req.Schedule != nil {
+			schedule = req.GetSchedule()
+		}
+		nextRunAt, err := firstRunAt(schedule, convertOptionalTimestamp(req.GetRunAt()))
+		if err != nil {
+			return nil, invalidArgumentError([]*errdetails.BadRequest_FieldViolation{fieldViolation("runAt", err)})
+		}
+		arg.Schedule = sql.NullString{String: schedule, Valid: true}
+		arg.NextRunAt = sql.NullTime{Time: nextRunAt, Valid: true}
+	}
+	scheduledTransfer, err = server.store.UpdateScheduledTransfer(ctx, arg)
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to update scheduled transfer: %v", err)
+	}
+	response := &pb.UpdateScheduledTransferResponse{
+		ScheduledTransfer: convertScheduledTransfer(scheduledTransfer),
+	}
+	return response, nil
+}
+
+func validateUpdateScheduledTransferRequest(req *pb.UpdateScheduledTransferRequest) (violations []*errdetails.BadRequest_FieldViolation) {
+	if req.Amount != nil && req.GetAmount() <= 0 {
+		violations = append(violations, fieldViolation("amount", fmt.Errorf("amount must be greater than 0")))
+	}
+	if req.Amount != nil && req.DecimalAmount != nil {
+		violations = append(violations, fieldViolation("amount", fmt.Errorf("amount and decimalAmount are mutually exclusive")))
+	}
+	if req.Schedule != nil {
+		if err := validator.ValidateSchedule(req.GetSchedule()); err != nil {
+			violations = append(violations, fieldViolation("schedule", err))
+		}
+	}
+	if req.Status != nil {
+		if err := validator.ValidateScheduledTransferStatus(req.GetStatus()); err != nil {
+			violations = append(violations, fieldViolation("status", err))
+		}
+	}
+	return violations
+}
@@ -0,0 +1,153 @@
+package grpcapi
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	db "simple_bank/db/sqlc"
+	"simple_bank/pb"
+	util "simple_bank/util"
+	"simple_bank/validator"
+	"simple_bank/worker"
+	"time"
+
+	"github.com/lib/pq"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// UpdateUser changes the fields that are set, an empty name2 or lastname2 removes it. Changing the
+// email or the password requires the current password, and a password change blocks the other
+// sessions of the user and revokes the access tokens issued before it. A new email gets a
+// verification email like the one of a new user.
+func (server *Server) UpdateUser(ctx context.Context, req *pb.UpdateUserRequest) (*pb.UpdateUserResponse, error) {
+	authPayload, err := server.authorizeUser(ctx)
+	if err != nil {
+		return nil, unauthenticatedError(err)
+	}
+	violations := validateUpdateUserRequest(req)
+	if violations != nil {
+		return nil, invalidArgumentError(violations)
+	}
+	if req.GetUsername() != authPayload.Username {
+		return nil, status.Errorf(codes.PermissionDenied, "cannot update another user")
+	}
+
+	user, err := server.store.GetUser(ctx, authPayload.Username)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, status.Errorf(codes.NotFound, "user not found: %v", err)
+		}
+		return nil, status.Errorf(codes.Internal, "failed to find user: %v", err)
+	}
+	arg := db.UpdateUserParams{
+		Username:          user.Username,
+		Name1:             user.Name1,
+		Name2:             user.Name2,
+		Lastname1:         user.Lastname1,
+		Lastname2:         user.Lastname2,
+		Email:             user.Email,
+		HashedPassword:    user.HashedPassword,
+		PasswordChangedAt: user.PasswordChangedAt,
+	}
+	if req.Name1 != nil {
+		arg.Name1 = req.GetName1()
+	}
+	if req.Name2 != nil {
+		arg.Name2 = util.StringToSqlNullString(req.GetName2())
+	}
+	if req.Lastname1 != nil {
+		arg.Lastname1 = req.GetLastname1()
+	}
+	if req.Lastname2 != nil {
+		arg.Lastname2 = util.StringToSqlNullString(req.GetLastname2())
+	}
+
+	if (req.Email != nil && req.GetEmail() != user.Email) || req.Password != nil {
+		if req.GetCurrentPassword() == "" {
+			return nil, invalidArgumentError([]*errdetails.BadRequest_FieldViolation{
+				fieldViolation("currentPassword", fmt.Errorf("is required to change the email or the password")),
+			})
+		}
+		if err := util.CheckPasswordHash(req.GetCurrentPassword(), user.HashedPassword); err != nil {
+			return nil, status.Errorf(codes.Unauthenticated, "invalid password: %v", err)
+		}
+	}
+	if req.Email != nil {
+		arg.Email = req.GetEmail()
+	}
+	if req.Password != nil {
+		hashedPassword, err := util.HashPassword(req.GetPassword())
+		if err != nil {
+			return nil, status.Errorf(codes.Internal, "failed to hash password: %s", err)
+		}
+		arg.HashedPassword = hashedPassword
+		arg.PasswordChangedAt = time.Now()
+	}
+
+	// the session of the request is found by its access token, the in-process gateway forwards it too
+	accessToken, _ := extractAccessToken(ctx)
+	txArg := db.UpdateUserTxParams{
+		User:               arg,
+		BlockOtherSessions: req.Password != nil,
+		AccessToken:        accessToken,
+	}
+	if arg.Email != user.Email {
+		// the new email is verified like the one of a new user
+		txArg.SecretCode = util.RandomString(32)
+		txArg.AfterEmailChange = func(q db.Querier, user db.User, verifyEmail db.VerifyEmail) error {
+			return server.taskDistributor.DistributeTaskSendVerifyEmail(ctx, q, &worker.PayloadSendVerifyEmail{
+				EmailId: verifyEmail.ID,
+			})
+		}
+	}
+	result, err := server.store.UpdateUserTx(ctx, txArg)
+	if err != nil {
+		if pqErr, value := err.(*pq.Error); value {
+			switch pqErr.Code.Name() {
+			case "unique_violation":
+				return nil, status.Errorf(codes.AlreadyExists, "email already in use")
+			}
+		}
+		return nil, status.Errorf(codes.Internal, "failed to update user: %v", err)
+	}
+	return &pb.UpdateUserResponse{User: convertUser(result.User)}, nil
+}
+
+func validateUpdateUserRequest(req *pb.UpdateUserRequest) (violations []*errdetails.BadRequest_FieldViolation) {
+	if err := validator.ValidateUsername(req.GetUsername()); err != nil {
+		violations = append(violations, fieldViolation("username", err))
+	}
+	if req.Name1 != nil {
+		if err := validator.ValidateName(req.GetName1()); err != nil {
+			violations = append(violations, fieldViolation("name1", err))
+		}
+	}
+	if req.GetName2() != "" {
+		if err := validator.ValidateName(req.GetName2()); err != nil {
+			violations = append(violations, fieldViolation("name2", err))
+		}
+	}
+	if req.Lastname1 != nil {
+		if err := validator.ValidateName(req.GetLastname1()); err != nil {
+			violations = append(violations, fieldViolation("lastname1", err))
+		}
+	}
+	if req.GetLastname2() != "" {
+		if err := validator.ValidateName(req.GetLastname2()); err != nil {
+			violations = append(violations, fieldViolation("lastname2", err))
+		}
+	}
+	if req.Email != nil {
+		if err := validator.ValidateEmail(req.GetEmail()); err != nil {
+			violations = append(violations, fieldViolation("email", err))
+		}
+	}
+	if req.Password != nil {
+		if err := validator.ValidatePassword(req.GetPassword()); err != nil {
+			violations = append(violations, fieldViolation("password", err))
+		}
+	}
+	return violations
+}
@@ -0,0 +1,53 @@
+package grpcapi
+
+import (
+	"context"
+	db "simple_bank/db/sqlc"
+	"simple_bank/pb"
+	"simple_bank/util"
+	"simple_bank/validator"
+
+	"github.com/lib/pq"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// UpdateUserLimits replaces the limits shared by all accounts of a user in a currency, like
+// UpdateAccountLimits only bankers and admins can change them
+func (server *Server) UpdateUserLimits(ctx context.Context, req *pb.UpdateUserLimitsRequest) (*pb.UpdateUserLimitsResponse, error) {
+	_, err := server.authorizeRoles(ctx, util.BankerRole, util.AdminRole)
+	if err != nil {
+		return nil, err
+	}
+	violations := validateUpdateUserLimitsRequest(req)
+	if violations != nil {
+		return nil, invalidArgumentError(violations)
+	}
+
+	userLimit, err := server.store.UpsertUserLimit(ctx, db.UpsertUserLimitParams{
+		Username:          req.GetUsername(),
+		Currency:          req.GetCurrency(),
+		MaxTransferAmount: convertOptionalInt64(req.MaxTransferAmount),
+		DailyAmount:       convertOptionalInt64(req.DailyAmount),
+		MonthlyAmount:     convertOptionalInt64(req.MonthlyAmount),
+		HourlyTransfers:   convertOptionalInt32(req.HourlyTransfers),
+	})
+	if err != nil {
+		if pqErr, value := err.(*pq.Error); value && pqErr.Code.Name() == "foreign_key_violation" {
+			return nil, status.Errorf(codes.NotFound, "user not found: %v", err)
+		}
+		return nil, status.Errorf(codes.Internal, "failed to update user limits: %v", err)
+	}
+	return &pb.UpdateUserLimitsResponse{Limits: convertUserLimit(userLimit)}, nil
+}
+
+func validateUpdateUserLimitsRequest(req *pb.UpdateUserLimitsRequest) (violations []*errdetails.BadRequest_FieldViolation) {
+	if err := validator.ValidateUsername(req.GetUsername()); err != nil {
+		violations = append(violations, fieldViolation("username", err))
+	}
+	if err := validator.ValidateCurrency(req.GetCurrency()); err != nil {
+		violations = append(violations, fieldViolation("currency", err))
+	}
+	return append(violations, validateTransferLimits(req.MaxTransferAmount, req.DailyAmount, req.MonthlyAmount, req.HourlyTransfers)...)
+}
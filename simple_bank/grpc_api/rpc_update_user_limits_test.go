@@ -0,0 +1,95 @@
+package grpcapi
+
+import (
+	"context"
+	"database/sql"
+	mockdb "simple_bank/db/mock"
+	db "simple_bank/db/sqlc"
+	"simple_bank/pb"
+	"simple_bank/token"
+	"simple_bank/util"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+	"google.golang.org/grpc/codes"
+)
+
+func TestUpdateUserLimitsRPC(t *testing.T) {
+	username := util.RandomUsername()
+	currency := util.GetCurrency(0)
+	hourlyTransfers := int32(10)
+
+	testCases := []struct {
+		name          string
+		req           *pb.UpdateUserLimitsRequest
+		buildStubs    func(store *mockdb.MockStore)
+		buildContext  func(t *testing.T, tokenMaker token.Maker) context.Context
+		checkResponse func(t *testing.T, res *pb.UpdateUserLimitsResponse, err error)
+	}{
+		{
+			name: "OK",
+			req:  &pb.UpdateUserLimitsRequest{Username: username, Currency: currency, HourlyTransfers: &hourlyTransfers},
+			buildStubs: func(store *mockdb.MockStore) {
+				arg := db.UpsertUserLimitParams{
+					Username:        username,
+					Currency:        currency,
+					HourlyTransfers: sql.NullInt32{Int32: hourlyTransfers, Valid: true},
+				}
+				store.EXPECT().UpsertUserLimit(gomock.Any(), gomock.Eq(arg)).Times(1).
+					Return(db.UserLimit{Username: username, Currency: currency, HourlyTransfers: arg.HourlyTransfers}, nil)
+			},
+			buildContext: func(t *testing.T, tokenMaker token.Maker) context.Context {
+				return newContextWithBearerToken(t, tokenMaker, util.RandomUsername(), util.BankerRole, time.Minute)
+			},
+			checkResponse: func(t *testing.T, res *pb.UpdateUserLimitsResponse, err error) {
+				require.NoError(t, err)
+				require.Equal(t, hourlyTransfers, res.GetLimits().GetHourlyTransfers())
+			},
+		},
+		{
+			name: "DepositorCannotUpdate",
+			req:  &pb.UpdateUserLimitsRequest{Username: username, Currency: currency},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().UpsertUserLimit(gomock.Any(), gomock.Any()).Times(0)
+			},
+			buildContext: func(t *testing.T, tokenMaker token.Maker) context.Context {
+				return newContextWithBearerToken(t, tokenMaker, username, util.DepositorRole, time.Minute)
+			},
+			checkResponse: func(t *testing.T, res *pb.UpdateUserLimitsResponse, err error) {
+				requireStatusCode(t, err, codes.PermissionDenied)
+			},
+		},
+		{
+			name: "InvalidUsername",
+			req:  &pb.UpdateUserLimitsRequest{Username: "invalid-user#1", Currency: currency},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().UpsertUserLimit(gomock.Any(), gomock.Any()).Times(0)
+			},
+			buildContext: func(t *testing.T, tokenMaker token.Maker) context.Context {
+				return newContextWithBearerToken(t, tokenMaker, util.RandomUsername(), util.AdminRole, time.Minute)
+			},
+			checkResponse: func(t *testing.T, res *pb.UpdateUserLimitsResponse, err error) {
+				requireStatusCode(t, err, codes.InvalidArgument)
+			},
+		},
+	}
+
+	for i := range testCases {
+		tc := testCases[i]
+
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			store := mockdb.NewMockStore(ctrl)
+			tc.buildStubs(store)
+
+			server := newTestServer(t, store)
+			ctx := tc.buildContext(t, server.tokenMaker)
+			res, err := server.UpdateUserLimits(ctx, tc.req)
+			tc.checkResponse(t, res, err)
+		})
+	}
+}
@@ -0,0 +1,49 @@
+package grpcapi
+
+import (
+	"context"
+	"database/sql"
+	db "simple_bank/db/sqlc"
+	"simple_bank/pb"
+	"simple_bank/util"
+	"simple_bank/validator"
+
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// UpdateUserRole changes the role of a user. Access tokens created before keep the old role until
+// they are renewed.
+func (server *Server) UpdateUserRole(ctx context.Context, req *pb.UpdateUserRoleRequest) (*pb.UpdateUserRoleResponse, error) {
+	_, err := server.authorizeRoles(ctx, util.AdminRole)
+	if err != nil {
+		return nil, err
+	}
+	violations := validateUpdateUserRoleRequest(req)
+	if violations != nil {
+		return nil, invalidArgumentError(violations)
+	}
+
+	user, err := server.store.UpdateUserRole(ctx, db.UpdateUserRoleParams{
+		Username: req.GetUsername(),
+		Role:     req.GetRole(),
+	})
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, status.Errorf(codes.NotFound, "user not found: %v", err)
+		}
+		return nil, status.Errorf(codes.Internal, "failed to update user role: %v", err)
+	}
+	return &pb.UpdateUserRoleResponse{User: convertUser(user)}, nil
+}
+
+func validateUpdateUserRoleRequest(req *pb.UpdateUserRoleRequest) (violations []*errdetails.BadRequest_FieldViolation) {
+	if err := validator.ValidateUsername(req.GetUsername()); err != nil {
+		violations = append(violations, fieldViolation("username", err))
+	}
+	if err := validator.ValidateRole(req.GetRole()); err != nil {
+		violations = append(violations, fieldViolation("role", err))
+	}
+	return violations
+}
@@ -0,0 +1,30 @@
+package grpcapi
+
+import (
+	"context"
+	db "simple_bank/db/sqlc"
+	"simple_bank/pb"
+	"simple_bank/util"
+)
+
+func (server *Server) Withdraw(ctx context.Context, req *pb.WithdrawRequest) (*pb.WithdrawResponse, error) {
+	account, amount, err := server.validBalanceChange(ctx, req.GetAccountId(), req.GetAmount(), req.GetDecimalAmount(), req.GetCurrency(), req.GetReason(), req.GetExternalReference())
+	if err != nil {
+		return nil, err
+	}
+	result, err := server.store.WithdrawTx(ctx, db.WithdrawTxParams{
+		AccountID:         account.ID,
+		Amount:            amount.Amount,
+		Reason:            req.GetReason(),
+		ExternalReference: req.GetExternalReference(),
+	})
+	if err != nil {
+		return nil, balanceChangeError(err, "failed to withdraw")
+	}
+	response := &pb.WithdrawResponse{
+		Account:         convertAccount(result.Account),
+		Entry:           convertEntry(result.Entry),
+		FormattedAmount: util.FormatAmount(result.Entry.Amount, result.Account.Currency),
+	}
+	return response, nil
+}
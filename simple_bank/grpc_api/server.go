@@ -3,28 +3,40 @@ package grpcapi
 import (
 	"fmt"
 	db "simple_bank/db/sqlc"
+	"simple_bank/exchange"
 	"simple_bank/pb"
 	"simple_bank/token"
 	util "simple_bank/util"
+	"simple_bank/worker"
 )
 
 type Server struct {
 	config     util.Config
 	store      db.Store
 	tokenMaker token.Maker
+	// converts amounts of cross-currency transfers
+	exchangeRates exchange.ExchangeRateProvider
+	// enqueues the side effects run in the background, like the verification emails of new users
+	taskDistributor worker.TaskDistributor
 	pb.UnimplementedSimpleBankServer
 }
 
-func NewServer(config util.Config, store db.Store) (*Server, error) {
+func NewServer(config util.Config, store db.Store, taskDistributor worker.TaskDistributor) (*Server, error) {
 	tokenMaker, err := token.NewPasetoMaker(config.TokenKey)
 	if err != nil {
 		return nil, fmt.Errorf("cannot create a token maker: %w", err)
 	}
+	exchangeRates, err := exchange.NewFileProvider(config.ExchangeRatesFile)
+	if err != nil {
+		return nil, fmt.Errorf("cannot create an exchange rate provider: %w", err)
+	}
 
 	server := &Server{
-		config:     config,
-		store:      store,
-		tokenMaker: tokenMaker,
+		config:          config,
+		store:           store,
+		tokenMaker:      tokenMaker,
+		exchangeRates:   exchangeRates,
+		taskDistributor: taskDistributor,
 	}
 
 	return server, nil
@@ -0,0 +1,90 @@
+package mail
+
+import (
+	"context"
+	"fmt"
+	"net/smtp"
+	"strings"
+	"sync"
+)
+
+// Email is an HTML message to one or more recipients
+type Email struct {
+	To      []string `json:"to"`
+	Subject string   `json:"subject"`
+	Content string   `json:"content"`
+}
+
+// Mailer is an interface that sends emails
+type Mailer interface {
+	SendEmail(ctx context.Context, email Email) error
+}
+
+// SMTPMailer sends emails through an SMTP server with plain authentication
+type SMTPMailer struct {
+	address     string
+	auth        smtp.Auth
+	senderName  string
+	fromAddress string
+}
+
+// NewSMTPMailer sends from fromAddress through the server at address, a host:port pair.
+// No authentication is used when username is empty.
+func NewSMTPMailer(address string, username string, password string, senderName string, fromAddress string) *SMTPMailer {
+	mailer := &SMTPMailer{
+		address:     address,
+		senderName:  senderName,
+		fromAddress: fromAddress,
+	}
+	if username != "" {
+		host := strings.Split(address, ":")[0]
+		mailer.auth = smtp.PlainAuth("", username, password, host)
+	}
+	return mailer
+}
+
+func (mailer *SMTPMailer) SendEmail(ctx context.Context, email Email) error {
+	if len(email.To) == 0 {
+		return fmt.Errorf("email has no recipient")
+	}
+	var message strings.Builder
+	fmt.Fprintf(&message, "From: %s <%s>\r\n", mailer.senderName, mailer.fromAddress)
+	fmt.Fprintf(&message, "To: %s\r\n", strings.Join(email.To, ", "))
+	fmt.Fprintf(&message, "Subject: %s\r\n", email.Subject)
+	message.WriteString("MIME-Version: 1.0\r\n")
+	message.WriteString("Content-Type: text/html; charset=UTF-8\r\n\r\n")
+	message.WriteString(email.Content)
+
+	err := smtp.SendMail(mailer.address, mailer.auth, mailer.fromAddress, email.To, []byte(message.String()))
+	if err != nil {
+		return fmt.Errorf("cannot send email: %w", err)
+	}
+	return nil
+}
+
+// InMemoryMailer keeps the emails instead of sending them, for tests and for running without an SMTP server
+type InMemoryMailer struct {
+	mutex  sync.Mutex
+	emails []Email
+}
+
+func NewInMemoryMailer() *InMemoryMailer {
+	return &InMemoryMailer{}
+}
+
+func (mailer *InMemoryMailer) SendEmail(ctx context.Context, email Email) error {
+	if len(email.To) == 0 {
+		return fmt.Errorf("email has no recipient")
+	}
+	mailer.mutex.Lock()
+	defer mailer.mutex.Unlock()
+	mailer.emails = append(mailer.emails, email)
+	return nil
+}
+
+// Emails returns the emails sent so far, oldest first
+func (mailer *InMemoryMailer) Emails() []Email {
+	mailer.mutex.Lock()
+	defer mailer.mutex.Unlock()
+	return append([]Email(nil), mailer.emails...)
+}
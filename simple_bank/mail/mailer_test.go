@@ -0,0 +1,55 @@
+package mail
+
+import (
+	"context"
+	"net/url"
+	db "simple_bank/db/sqlc"
+	"simple_bank/util"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestInMemoryMailer(t *testing.T) {
+	mailer := NewInMemoryMailer()
+	email := Email{
+		To:      []string{util.RandomEmail()},
+		Subject: "subject",
+		Content: "content",
+	}
+	err := mailer.SendEmail(context.Background(), email)
+	require.NoError(t, err)
+
+	err = mailer.SendEmail(context.Background(), Email{Subject: "no recipient"})
+	require.Error(t, err)
+	require.Equal(t, []Email{email}, mailer.Emails())
+}
+
+func TestNewVerifyEmail(t *testing.T) {
+	user := db.User{
+		Username: util.RandomUsername(),
+		Name1:    util.RandomUsername(),
+		Email:    util.RandomEmail(),
+	}
+	verifyEmail := db.VerifyEmail{
+		ID:         util.RandomInt(1, 1000),
+		Username:   user.Username,
+		Email:      user.Email,
+		SecretCode: util.RandomString(32),
+		ExpiredAt:  time.Now().Add(15 * time.Minute),
+	}
+
+	email := NewVerifyEmail("http://localhost:8080/verify_email", user, verifyEmail)
+	require.Equal(t, []string{user.Email}, email.To)
+	require.NotEmpty(t, email.Subject)
+	require.Contains(t, email.Content, user.Name1)
+
+	start := strings.Index(email.Content, `href="`) + len(`href="`)
+	end := strings.Index(email.Content[start:], `"`)
+	link, err := url.Parse(strings.ReplaceAll(email.Content[start:start+end], "&amp;", "&"))
+	require.NoError(t, err)
+	require.Equal(t, "/verify_email", link.Path)
+	require.Equal(t, verifyEmail.SecretCode, link.Query().Get("secretCode"))
+}
@@ -0,0 +1,27 @@
+package mail
+
+import (
+	"fmt"
+	"html"
+	"net/url"
+	db "simple_bank/db/sqlc"
+)
+
+// NewVerifyEmail builds the email with the link that verifies the email of a new user. The link
+// points to verifyEmailUrl, which is expected to call the VerifyEmail endpoint with its parameters.
+func NewVerifyEmail(verifyEmailUrl string, user db.User, verifyEmail db.VerifyEmail) Email {
+	query := url.Values{}
+	query.Set("emailId", fmt.Sprint(verifyEmail.ID))
+	query.Set("secretCode", verifyEmail.SecretCode)
+	link := verifyEmailUrl + "?" + query.Encode()
+
+	content := fmt.Sprintf(`Hello %s,<br/>
+Thank you for registering with us!<br/>
+Please <a href="%s">click here</a> to verify your email address. The link expires at %s.<br/>`,
+		html.EscapeString(user.Name1), html.EscapeString(link), verifyEmail.ExpiredAt.UTC().Format("2006-01-02 15:04 MST"))
+	return Email{
+		To:      []string{verifyEmail.Email},
+		Subject: "Welcome to Simple Bank",
+		Content: content,
+	}
+}
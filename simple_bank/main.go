@@ -1,59 +1,306 @@
 package main
 
 import (
+	"context"
 	"database/sql"
+	"errors"
+	"fmt"
 	"log"
 	"net"
+	"net/http"
+	"os"
+	"os/signal"
 	"simple_bank/api"
+	"simple_bank/db/migration"
 	db "simple_bank/db/sqlc"
 	grpcapi "simple_bank/grpc_api"
+	"simple_bank/mail"
 	"simple_bank/pb"
 	"simple_bank/util"
+	"simple_bank/worker"
+	"syscall"
+	"time"
 
+	"github.com/grpc-ecosystem/grpc-gateway/v2/runtime"
 	_ "github.com/lib/pq"
+	"golang.org/x/sync/errgroup"
 	"google.golang.org/grpc"
 	"google.golang.org/grpc/reflection"
+	"google.golang.org/protobuf/encoding/protojson"
 )
 
+const (
+	httpServerTypeGateway = "gateway"
+	httpServerTypeGin     = "gin"
+)
+
+var interruptSignals = []os.Signal{
+	os.Interrupt,
+	syscall.SIGTERM,
+}
+
 func main() {
 	config, err := util.LoadConfig(".")
 	if err != nil {
 		log.Fatal("cannot load configuration: ", err)
 	}
+	switch {
+	case len(os.Args) > 1 && os.Args[1] == "migrate":
+		err = runMigrateCommand(config, os.Args[2:])
+	case len(os.Args) > 1 && os.Args[1] == "reconcile":
+		err = runReconcileCommand(config)
+	default:
+		err = run(config)
+	}
+	if err != nil {
+		log.Fatal(err)
+	}
+}
+
+// run applies pending migrations when configured, then serves gRPC and, when an
+// address is configured, HTTP until an interrupt signal arrives. It drains both
+// servers and closes the database pool before returning.
+func run(config util.Config) error {
+	ctx, stop := signal.NotifyContext(context.Background(), interruptSignals...)
+	defer stop()
+
 	conn, err := sql.Open(config.DbDriver, config.DbSource)
 	if err != nil {
-		log.Fatal("cannot connect to db: ", err)
+		return fmt.Errorf("cannot connect to db: %w", err)
+	}
+	defer func() {
+		if err := conn.Close(); err != nil {
+			log.Print("cannot close db connection: ", err)
+			return
+		}
+		log.Print("db connection is closed")
+	}()
+
+	if config.RunMigrations {
+		err = migration.Up(config.DbSource)
+		if err != nil {
+			return err
+		}
+		log.Print("db migrated successfully")
 	}
 
 	store := db.NewStore(conn)
-	runGrpcServer(config, store)
+	waitGroup, ctx := errgroup.WithContext(ctx)
+
+	err = startServices(ctx, waitGroup, config, conn, store)
+	if err != nil {
+		// let the services that did start shut down before giving up
+		stop()
+		waitGroup.Wait()
+		return err
+	}
+	return waitGroup.Wait()
+}
+
+// startServices starts the background jobs, the gRPC server and, when an address is configured, the
+// HTTP server in waitGroup.
+func startServices(ctx context.Context, waitGroup *errgroup.Group, config util.Config, conn *sql.DB, store db.Store) error {
+	err := runCurrencyRefresher(ctx, waitGroup, config, store)
+	if err != nil {
+		return err
+	}
+	runReconciler(ctx, waitGroup, config, conn, store)
+	err = runScheduler(ctx, waitGroup, config, store)
+	if err != nil {
+		return err
+	}
+	err = runTaskProcessor(ctx, waitGroup, config, store, newMailer(config))
+	if err != nil {
+		return err
+	}
+	taskDistributor := worker.NewPGTaskDistributor(config.TaskMaxAttempts)
+	err = runGrpcServer(ctx, waitGroup, config, store, taskDistributor)
+	if err != nil {
+		return err
+	}
+	if config.HttpServerAddress == "" {
+		return nil
+	}
+	switch config.HttpServerType {
+	case httpServerTypeGin:
+		return runGinServer(ctx, waitGroup, config, store, taskDistributor)
+	case httpServerTypeGateway, "":
+		return runGatewayServer(ctx, waitGroup, config, store, taskDistributor)
+	default:
+		return fmt.Errorf("unknown http server type: %s", config.HttpServerType)
+	}
+}
+
+// runCurrencyRefresher loads the currency registry from the database before the servers start, and
+// reloads it every CurrencyRefreshInterval so enabling or disabling a currency needs no restart.
+func runCurrencyRefresher(ctx context.Context, waitGroup *errgroup.Group, config util.Config, store db.Store) error {
+	err := loadCurrencies(ctx, store)
+	if err != nil {
+		return err
+	}
+	waitGroup.Go(func() error {
+		ticker := time.NewTicker(config.CurrencyRefreshInterval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ctx.Done():
+				return nil
+			case <-ticker.C:
+				if err := loadCurrencies(ctx, store); err != nil {
+					log.Print(err)
+				}
+			}
+		}
+	})
+	return nil
+}
+
+func loadCurrencies(ctx context.Context, store db.Store) error {
+	rows, err := store.ListCurrencies(ctx)
+	if err != nil {
+		return fmt.Errorf("cannot load currencies: %w", err)
+	}
+	currencies := make([]util.Currency, len(rows))
+	for i, row := range rows {
+		currencies[i] = util.Currency{
+			Code:      row.Code,
+			MinorUnit: int(row.MinorUnit),
+			Symbol:    row.Symbol,
+			Enabled:   row.Enabled,
+		}
+	}
+	util.SetCurrencies(currencies)
+	return nil
+}
+
+// newMailer sends the emails through the configured SMTP server, without one they are only kept in memory
+func newMailer(config util.Config) mail.Mailer {
+	if config.SmtpAddress == "" {
+		log.Print("no SMTP server configured, emails will not be delivered")
+		return mail.NewInMemoryMailer()
+	}
+	return mail.NewSMTPMailer(
+		config.SmtpAddress,
+		config.SmtpUsername,
+		config.SmtpPassword,
+		config.EmailSenderName,
+		config.EmailSenderAddress,
+	)
 }
-func runGrpcServer(config util.Config, store db.Store) {
-	server, err := grpcapi.NewServer(config, store)
+
+func runGrpcServer(ctx context.Context, waitGroup *errgroup.Group, config util.Config, store db.Store, taskDistributor worker.TaskDistributor) error {
+	server, err := grpcapi.NewServer(config, store, taskDistributor)
 	if err != nil {
-		log.Fatal("cannot create server: ", err)
+		return fmt.Errorf("cannot create server: %w", err)
 	}
-	grpcServer := grpc.NewServer()
+	grpcServer := grpc.NewServer(
+		grpc.UnaryInterceptor(server.UnaryAuthInterceptor),
+		grpc.StreamInterceptor(server.StreamAuthInterceptor),
+	)
 	pb.RegisterSimpleBankServer(grpcServer, server)
 	reflection.Register(grpcServer)
 	listener, err := net.Listen("tcp", config.GrpcServerAddress)
 	if err != nil {
-		log.Fatal("cannot start listener for grpc server: ", err)
+		return fmt.Errorf("cannot start listener for grpc server: %w", err)
 	}
-	log.Printf("start gRPC server at %s", listener.Addr().String())
-	err = grpcServer.Serve(listener)
+
+	waitGroup.Go(func() error {
+		log.Printf("start gRPC server at %s", listener.Addr().String())
+		err := grpcServer.Serve(listener)
+		if err != nil && !errors.Is(err, grpc.ErrServerStopped) {
+			return fmt.Errorf("grpc server failed to serve: %w", err)
+		}
+		return nil
+	})
+	waitGroup.Go(func() error {
+		<-ctx.Done()
+		log.Print("graceful shutdown of gRPC server")
+		stopped := make(chan struct{})
+		go func() {
+			grpcServer.GracefulStop()
+			close(stopped)
+		}()
+		select {
+		case <-stopped:
+		case <-time.After(config.ShutdownTimeout):
+			log.Print("gRPC server did not drain in time, closing remaining connections")
+			grpcServer.Stop()
+		}
+		log.Print("gRPC server is stopped")
+		return nil
+	})
+	return nil
+}
+
+func runGatewayServer(ctx context.Context, waitGroup *errgroup.Group, config util.Config, store db.Store, taskDistributor worker.TaskDistributor) error {
+	server, err := grpcapi.NewServer(config, store, taskDistributor)
 	if err != nil {
-		log.Fatal("cannot start grpc server: ", err)
+		return fmt.Errorf("cannot create server: %w", err)
 	}
+	jsonOption := runtime.WithMarshalerOption(runtime.MIMEWildcard, &runtime.JSONPb{
+		MarshalOptions: protojson.MarshalOptions{
+			UseProtoNames:   true,
+			EmitUnpopulated: true,
+		},
+		UnmarshalOptions: protojson.UnmarshalOptions{
+			DiscardUnknown: true,
+		},
+	})
+	grpcMux := runtime.NewServeMux(jsonOption, runtime.WithIncomingHeaderMatcher(gatewayHeaderMatcher))
+	err = pb.RegisterSimpleBankHandlerServer(ctx, grpcMux, server)
+	if err != nil {
+		return fmt.Errorf("cannot register handler server: %w", err)
+	}
+	mux := http.NewServeMux()
+	mux.Handle("/", grpcMux)
+
+	return serveHttp(ctx, waitGroup, config, "HTTP gateway", mux)
 }
 
-func runGinServer(config util.Config, store db.Store) {
-	server, err := api.NewServer(config, store)
+// gatewayHeaderMatcher forwards the Idempotency-Key header to the gRPC handlers on top of the default headers
+func gatewayHeaderMatcher(key string) (string, bool) {
+	if http.CanonicalHeaderKey(key) == "Idempotency-Key" {
+		return "idempotency-key", true
+	}
+	return runtime.DefaultHeaderMatcher(key)
+}
+
+func runGinServer(ctx context.Context, waitGroup *errgroup.Group, config util.Config, store db.Store, taskDistributor worker.TaskDistributor) error {
+	server, err := api.NewServer(config, store, taskDistributor)
 	if err != nil {
-		log.Fatal("cannot create server: ", err)
+		return fmt.Errorf("cannot create server: %w", err)
 	}
-	err = server.Start(config.HttpServerAddress)
+	return serveHttp(ctx, waitGroup, config, "Gin", server.Handler())
+}
+
+// serveHttp runs handler on the configured HTTP address and shuts it down once ctx is done.
+func serveHttp(ctx context.Context, waitGroup *errgroup.Group, config util.Config, name string, handler http.Handler) error {
+	listener, err := net.Listen("tcp", config.HttpServerAddress)
 	if err != nil {
-		log.Fatal("cannot start server: ", err)
+		return fmt.Errorf("cannot start listener for %s server: %w", name, err)
 	}
+	httpServer := &http.Server{Handler: handler}
+
+	waitGroup.Go(func() error {
+		log.Printf("start %s server at %s", name, listener.Addr().String())
+		err := httpServer.Serve(listener)
+		if err != nil && !errors.Is(err, http.ErrServerClosed) {
+			return fmt.Errorf("%s server failed to serve: %w", name, err)
+		}
+		return nil
+	})
+	waitGroup.Go(func() error {
+		<-ctx.Done()
+		log.Printf("graceful shutdown of %s server", name)
+		shutdownCtx, cancel := context.WithTimeout(context.Background(), config.ShutdownTimeout)
+		defer cancel()
+		err := httpServer.Shutdown(shutdownCtx)
+		if err != nil {
+			log.Printf("%s server did not drain in time: %v", name, err)
+			return httpServer.Close()
+		}
+		log.Printf("%s server is stopped", name)
+		return nil
+	})
+	return nil
 }
@@ -0,0 +1,46 @@
+package main
+
+import (
+	"fmt"
+	"log"
+	"simple_bank/db/migration"
+	"simple_bank/util"
+	"strconv"
+)
+
+const migrateUsage = "usage: migrate up | down N | version"
+
+// runMigrateCommand handles the "migrate" subcommand with the migrations embedded in the binary
+func runMigrateCommand(config util.Config, args []string) error {
+	if len(args) == 0 {
+		return fmt.Errorf(migrateUsage)
+	}
+	switch args[0] {
+	case "up":
+		if err := migration.Up(config.DbSource); err != nil {
+			return err
+		}
+		log.Print("db migrated successfully")
+	case "down":
+		if len(args) != 2 {
+			return fmt.Errorf(migrateUsage)
+		}
+		n, err := strconv.Atoi(args[1])
+		if err != nil {
+			return fmt.Errorf("invalid number of migrations %q: %w", args[1], err)
+		}
+		if err := migration.Down(config.DbSource, n); err != nil {
+			return err
+		}
+		log.Printf("rolled back %d migration(s)", n)
+	case "version":
+		version, dirty, err := migration.Version(config.DbSource)
+		if err != nil {
+			return fmt.Errorf("cannot get migration version: %w", err)
+		}
+		log.Printf("db version: %d, dirty: %t", version, dirty)
+	default:
+		return fmt.Errorf(migrateUsage)
+	}
+	return nil
+}
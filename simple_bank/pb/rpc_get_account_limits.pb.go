@@ -0,0 +1,224 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.28.1
+// 	protoc        v3.12.4
+// source: rpc_get_account_limits.proto
+
+package pb
+
+import (
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	reflect "reflect"
+	sync "sync"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+type GetAccountLimitsRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	AccountId int64 `protobuf:"varint,1,opt,name=accountId,proto3" json:"accountId,omitempty"`
+}
+
+func (x *GetAccountLimitsRequest) Reset() {
+	*x = GetAccountLimitsRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_rpc_get_account_limits_proto_msgTypes[0]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *GetAccountLimitsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetAccountLimitsRequest) ProtoMessage() {}
+
+func (x *GetAccountLimitsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_rpc_get_account_limits_proto_msgTypes[0]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetAccountLimitsRequest.ProtoReflect.Descriptor instead.
+func (*GetAccountLimitsRequest) Descriptor() ([]byte, []int) {
+	return file_rpc_get_account_limits_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *GetAccountLimitsRequest) GetAccountId() int64 {
+	if x != nil {
+		return x.AccountId
+	}
+	return 0
+}
+
+type GetAccountLimitsResponse struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Account *TransferLimits `protobuf:"bytes,1,opt,name=account,proto3" json:"account,omitempty"`
+	User    *TransferLimits `protobuf:"bytes,2,opt,name=user,proto3" json:"user,omitempty"`
+}
+
+func (x *GetAccountLimitsResponse) Reset() {
+	*x = GetAccountLimitsResponse{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_rpc_get_account_limits_proto_msgTypes[1]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *GetAccountLimitsResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetAccountLimitsResponse) ProtoMessage() {}
+
+func (x *GetAccountLimitsResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_rpc_get_account_limits_proto_msgTypes[1]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetAccountLimitsResponse.ProtoReflect.Descriptor instead.
+func (*GetAccountLimitsResponse) Descriptor() ([]byte, []int) {
+	return file_rpc_get_account_limits_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *GetAccountLimitsResponse) GetAccount() *TransferLimits {
+	if x != nil {
+		return x.Account
+	}
+	return nil
+}
+
+func (x *GetAccountLimitsResponse) GetUser() *TransferLimits {
+	if x != nil {
+		return x.User
+	}
+	return nil
+}
+
+var File_rpc_get_account_limits_proto protoreflect.FileDescriptor
+
+var file_rpc_get_account_limits_proto_rawDesc = []byte{
+	0x0a, 0x1c, 0x72, 0x70, 0x63, 0x5f, 0x67, 0x65, 0x74, 0x5f, 0x61, 0x63, 0x63, 0x6f, 0x75, 0x6e,
+	0x74, 0x5f, 0x6c, 0x69, 0x6d, 0x69, 0x74, 0x73, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x12, 0x02,
+	0x70, 0x62, 0x1a, 0x14, 0x74, 0x72, 0x61, 0x6e, 0x73, 0x66, 0x65, 0x72, 0x5f, 0x6c, 0x69, 0x6d,
+	0x69, 0x74, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x22, 0x37, 0x0a, 0x17, 0x47, 0x65, 0x74, 0x41,
+	0x63, 0x63, 0x6f, 0x75, 0x6e, 0x74, 0x4c, 0x69, 0x6d, 0x69, 0x74, 0x73, 0x52, 0x65, 0x71, 0x75,
+	0x65, 0x73, 0x74, 0x12, 0x1c, 0x0a, 0x09, 0x61, 0x63, 0x63, 0x6f, 0x75, 0x6e, 0x74, 0x49, 0x64,
+	0x18, 0x01, 0x20, 0x01, 0x28, 0x03, 0x52, 0x09, 0x61, 0x63, 0x63, 0x6f, 0x75, 0x6e, 0x74, 0x49,
+	0x64, 0x22, 0x70, 0x0a, 0x18, 0x47, 0x65, 0x74, 0x41, 0x63, 0x63, 0x6f, 0x75, 0x6e, 0x74, 0x4c,
+	0x69, 0x6d, 0x69, 0x74, 0x73, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x2c, 0x0a,
+	0x07, 0x61, 0x63, 0x63, 0x6f, 0x75, 0x6e, 0x74, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x12,
+	0x2e, 0x70, 0x62, 0x2e, 0x54, 0x72, 0x61, 0x6e, 0x73, 0x66, 0x65, 0x72, 0x4c, 0x69, 0x6d, 0x69,
+	0x74, 0x73, 0x52, 0x07, 0x61, 0x63, 0x63, 0x6f, 0x75, 0x6e, 0x74, 0x12, 0x26, 0x0a, 0x04, 0x75,
+	0x73, 0x65, 0x72, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x12, 0x2e, 0x70, 0x62, 0x2e, 0x54,
+	0x72, 0x61, 0x6e, 0x73, 0x66, 0x65, 0x72, 0x4c, 0x69, 0x6d, 0x69, 0x74, 0x73, 0x52, 0x04, 0x75,
+	0x73, 0x65, 0x72, 0x42, 0x10, 0x5a, 0x0e, 0x73, 0x69, 0x6d, 0x70, 0x6c, 0x65, 0x5f, 0x62, 0x61,
+	0x6e, 0x6b, 0x2f, 0x70, 0x62, 0x62, 0x06, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x33,
+}
+
+var (
+	file_rpc_get_account_limits_proto_rawDescOnce sync.Once
+	file_rpc_get_account_limits_proto_rawDescData = file_rpc_get_account_limits_proto_rawDesc
+)
+
+func file_rpc_get_account_limits_proto_rawDescGZIP() []byte {
+	file_rpc_get_account_limits_proto_rawDescOnce.Do(func() {
+		file_rpc_get_account_limits_proto_rawDescData = protoimpl.X.CompressGZIP(file_rpc_get_account_limits_proto_rawDescData)
+	})
+	return file_rpc_get_account_limits_proto_rawDescData
+}
+
+var file_rpc_get_account_limits_proto_msgTypes = make([]protoimpl.MessageInfo, 2)
+var file_rpc_get_account_limits_proto_goTypes = []interface{}{
+	(*GetAccountLimitsRequest)(nil),  // 0: pb.GetAccountLimitsRequest
+	(*GetAccountLimitsResponse)(nil), // 1: pb.GetAccountLimitsResponse
+	(*TransferLimits)(nil),           // 2: pb.TransferLimits
+}
+var file_rpc_get_account_limits_proto_depIdxs = []int32{
+	2, // 0: pb.GetAccountLimitsResponse.account:type_name -> pb.TransferLimits
+	2, // 1: pb.GetAccountLimitsResponse.user:type_name -> pb.TransferLimits
+	2, // [2:2] is the sub-list for method output_type
+	2, // [2:2] is the sub-list for method input_type
+	2, // [2:2] is the sub-list for extension type_name
+	2, // [2:2] is the sub-list for extension extendee
+	0, // [0:2] is the sub-list for field type_name
+}
+
+func init() { file_rpc_get_account_limits_proto_init() }
+func file_rpc_get_account_limits_proto_init() {
+	if File_rpc_get_account_limits_proto != nil {
+		return
+	}
+	file_transfer_limit_proto_init()
+	if !protoimpl.UnsafeEnabled {
+		file_rpc_get_account_limits_proto_msgTypes[0].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*GetAccountLimitsRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_rpc_get_account_limits_proto_msgTypes[1].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*GetAccountLimitsResponse); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+	}
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: file_rpc_get_account_limits_proto_rawDesc,
+			NumEnums:      0,
+			NumMessages:   2,
+			NumExtensions: 0,
+			NumServices:   0,
+		},
+		GoTypes:           file_rpc_get_account_limits_proto_goTypes,
+		DependencyIndexes: file_rpc_get_account_limits_proto_depIdxs,
+		MessageInfos:      file_rpc_get_account_limits_proto_msgTypes,
+	}.Build()
+	File_rpc_get_account_limits_proto = out.File
+	file_rpc_get_account_limits_proto_rawDesc = nil
+	file_rpc_get_account_limits_proto_goTypes = nil
+	file_rpc_get_account_limits_proto_depIdxs = nil
+}
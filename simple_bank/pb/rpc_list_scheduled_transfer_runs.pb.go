@@ -0,0 +1,233 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.28.1
+// 	protoc        v3.12.4
+// source: rpc_list_scheduled_transfer_runs.proto
+
+package pb
+
+import (
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	reflect "reflect"
+	sync "sync"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+type ListScheduledTransferRunsRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Id     int64 `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`
+	Offset int32 `protobuf:"varint,2,opt,name=offset,proto3" json:"offset,omitempty"`
+	Limit  int32 `protobuf:"varint,3,opt,name=limit,proto3" json:"limit,omitempty"`
+}
+
+func (x *ListScheduledTransferRunsRequest) Reset() {
+	*x = ListScheduledTransferRunsRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_rpc_list_scheduled_transfer_runs_proto_msgTypes[0]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *ListScheduledTransferRunsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListScheduledTransferRunsRequest) ProtoMessage() {}
+
+func (x *ListScheduledTransferRunsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_rpc_list_scheduled_transfer_runs_proto_msgTypes[0]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListScheduledTransferRunsRequest.ProtoReflect.Descriptor instead.
+func (*ListScheduledTransferRunsRequest) Descriptor() ([]byte, []int) {
+	return file_rpc_list_scheduled_transfer_runs_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *ListScheduledTransferRunsRequest) GetId() int64 {
+	if x != nil {
+		return x.Id
+	}
+	return 0
+}
+
+func (x *ListScheduledTransferRunsRequest) GetOffset() int32 {
+	if x != nil {
+		return x.Offset
+	}
+	return 0
+}
+
+func (x *ListScheduledTransferRunsRequest) GetLimit() int32 {
+	if x != nil {
+		return x.Limit
+	}
+	return 0
+}
+
+type ListScheduledTransferRunsResponse struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Runs []*ScheduledTransferRun `protobuf:"bytes,1,rep,name=runs,proto3" json:"runs,omitempty"`
+}
+
+func (x *ListScheduledTransferRunsResponse) Reset() {
+	*x = ListScheduledTransferRunsResponse{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_rpc_list_scheduled_transfer_runs_proto_msgTypes[1]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *ListScheduledTransferRunsResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListScheduledTransferRunsResponse) ProtoMessage() {}
+
+func (x *ListScheduledTransferRunsResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_rpc_list_scheduled_transfer_runs_proto_msgTypes[1]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListScheduledTransferRunsResponse.ProtoReflect.Descriptor instead.
+func (*ListScheduledTransferRunsResponse) Descriptor() ([]byte, []int) {
+	return file_rpc_list_scheduled_transfer_runs_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *ListScheduledTransferRunsResponse) GetRuns() []*ScheduledTransferRun {
+	if x != nil {
+		return x.Runs
+	}
+	return nil
+}
+
+var File_rpc_list_scheduled_transfer_runs_proto protoreflect.FileDescriptor
+
+var file_rpc_list_scheduled_transfer_runs_proto_rawDesc = []byte{
+	0x0a, 0x26, 0x72, 0x70, 0x63, 0x5f, 0x6c, 0x69, 0x73, 0x74, 0x5f, 0x73, 0x63, 0x68, 0x65, 0x64,
+	0x75, 0x6c, 0x65, 0x64, 0x5f, 0x74, 0x72, 0x61, 0x6e, 0x73, 0x66, 0x65, 0x72, 0x5f, 0x72, 0x75,
+	0x6e, 0x73, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x12, 0x02, 0x70, 0x62, 0x1a, 0x18, 0x73, 0x63,
+	0x68, 0x65, 0x64, 0x75, 0x6c, 0x65, 0x64, 0x5f, 0x74, 0x72, 0x61, 0x6e, 0x73, 0x66, 0x65, 0x72,
+	0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x22, 0x60, 0x0a, 0x20, 0x4c, 0x69, 0x73, 0x74, 0x53, 0x63,
+	0x68, 0x65, 0x64, 0x75, 0x6c, 0x65, 0x64, 0x54, 0x72, 0x61, 0x6e, 0x73, 0x66, 0x65, 0x72, 0x52,
+	0x75, 0x6e, 0x73, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x0e, 0x0a, 0x02, 0x69, 0x64,
+	0x18, 0x01, 0x20, 0x01, 0x28, 0x03, 0x52, 0x02, 0x69, 0x64, 0x12, 0x16, 0x0a, 0x06, 0x6f, 0x66,
+	0x66, 0x73, 0x65, 0x74, 0x18, 0x02, 0x20, 0x01, 0x28, 0x05, 0x52, 0x06, 0x6f, 0x66, 0x66, 0x73,
+	0x65, 0x74, 0x12, 0x14, 0x0a, 0x05, 0x6c, 0x69, 0x6d, 0x69, 0x74, 0x18, 0x03, 0x20, 0x01, 0x28,
+	0x05, 0x52, 0x05, 0x6c, 0x69, 0x6d, 0x69, 0x74, 0x22, 0x51, 0x0a, 0x21, 0x4c, 0x69, 0x73, 0x74,
+	0x53, 0x63, 0x68, 0x65, 0x64, 0x75, 0x6c, 0x65, 0x64, 0x54, 0x72, 0x61, 0x6e, 0x73, 0x66, 0x65,
+	0x72, 0x52, 0x75, 0x6e, 0x73, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x2c, 0x0a,
+	0x04, 0x72, 0x75, 0x6e, 0x73, 0x18, 0x01, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x18, 0x2e, 0x70, 0x62,
+	0x2e, 0x53, 0x63, 0x68, 0x65, 0x64, 0x75, 0x6c, 0x65, 0x64, 0x54, 0x72, 0x61, 0x6e, 0x73, 0x66,
+	0x65, 0x72, 0x52, 0x75, 0x6e, 0x52, 0x04, 0x72, 0x75, 0x6e, 0x73, 0x42, 0x10, 0x5a, 0x0e, 0x73,
+	0x69, 0x6d, 0x70, 0x6c, 0x65, 0x5f, 0x62, 0x61, 0x6e, 0x6b, 0x2f, 0x70, 0x62, 0x62, 0x06, 0x70,
+	0x72, 0x6f, 0x74, 0x6f, 0x33,
+}
+
+var (
+	file_rpc_list_scheduled_transfer_runs_proto_rawDescOnce sync.Once
+	file_rpc_list_scheduled_transfer_runs_proto_rawDescData = file_rpc_list_scheduled_transfer_runs_proto_rawDesc
+)
+
+func file_rpc_list_scheduled_transfer_runs_proto_rawDescGZIP() []byte {
+	file_rpc_list_scheduled_transfer_runs_proto_rawDescOnce.Do(func() {
+		file_rpc_list_scheduled_transfer_runs_proto_rawDescData = protoimpl.X.CompressGZIP(file_rpc_list_scheduled_transfer_runs_proto_rawDescData)
+	})
+	return file_rpc_list_scheduled_transfer_runs_proto_rawDescData
+}
+
+var file_rpc_list_scheduled_transfer_runs_proto_msgTypes = make([]protoimpl.MessageInfo, 2)
+var file_rpc_list_scheduled_transfer_runs_proto_goTypes = []interface{}{
+	(*ListScheduledTransferRunsRequest)(nil),  // 0: pb.ListScheduledTransferRunsRequest
+	(*ListScheduledTransferRunsResponse)(nil), // 1: pb.ListScheduledTransferRunsResponse
+	(*ScheduledTransferRun)(nil),              // 2: pb.ScheduledTransferRun
+}
+var file_rpc_list_scheduled_transfer_runs_proto_depIdxs = []int32{
+	2, // 0: pb.ListScheduledTransferRunsResponse.runs:type_name -> pb.ScheduledTransferRun
+	1, // [1:1] is the sub-list for method output_type
+	1, // [1:1] is the sub-list for method input_type
+	1, // [1:1] is the sub-list for extension type_name
+	1, // [1:1] is the sub-list for extension extendee
+	0, // [0:1] is the sub-list for field type_name
+}
+
+func init() { file_rpc_list_scheduled_transfer_runs_proto_init() }
+func file_rpc_list_scheduled_transfer_runs_proto_init() {
+	if File_rpc_list_scheduled_transfer_runs_proto != nil {
+		return
+	}
+	file_scheduled_transfer_proto_init()
+	if !protoimpl.UnsafeEnabled {
+		file_rpc_list_scheduled_transfer_runs_proto_msgTypes[0].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*ListScheduledTransferRunsRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_rpc_list_scheduled_transfer_runs_proto_msgTypes[1].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*ListScheduledTransferRunsResponse); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+	}
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: file_rpc_list_scheduled_transfer_runs_proto_rawDesc,
+			NumEnums:      0,
+			NumMessages:   2,
+			NumExtensions: 0,
+			NumServices:   0,
+		},
+		GoTypes:           file_rpc_list_scheduled_transfer_runs_proto_goTypes,
+		DependencyIndexes: file_rpc_list_scheduled_transfer_runs_proto_depIdxs,
+		MessageInfos:      file_rpc_list_scheduled_transfer_runs_proto_msgTypes,
+	}.Build()
+	File_rpc_list_scheduled_transfer_runs_proto = out.File
+	file_rpc_list_scheduled_transfer_runs_proto_rawDesc = nil
+	file_rpc_list_scheduled_transfer_runs_proto_goTypes = nil
+	file_rpc_list_scheduled_transfer_runs_proto_depIdxs = nil
+}
@@ -0,0 +1,271 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.28.1
+// 	protoc        v3.12.4
+// source: rpc_update_user_limits.proto
+
+package pb
+
+import (
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	reflect "reflect"
+	sync "sync"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+type UpdateUserLimitsRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Currency          string `protobuf:"bytes,1,opt,name=currency,proto3" json:"currency,omitempty"`
+	MaxTransferAmount *int64 `protobuf:"varint,2,opt,name=maxTransferAmount,proto3,oneof" json:"maxTransferAmount,omitempty"`
+	DailyAmount       *int64 `protobuf:"varint,3,opt,name=dailyAmount,proto3,oneof" json:"dailyAmount,omitempty"`
+	MonthlyAmount     *int64 `protobuf:"varint,4,opt,name=monthlyAmount,proto3,oneof" json:"monthlyAmount,omitempty"`
+	HourlyTransfers   *int32 `protobuf:"varint,5,opt,name=hourlyTransfers,proto3,oneof" json:"hourlyTransfers,omitempty"`
+	Username          string `protobuf:"bytes,6,opt,name=username,proto3" json:"username,omitempty"`
+}
+
+func (x *UpdateUserLimitsRequest) Reset() {
+	*x = UpdateUserLimitsRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_rpc_update_user_limits_proto_msgTypes[0]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *UpdateUserLimitsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*UpdateUserLimitsRequest) ProtoMessage() {}
+
+func (x *UpdateUserLimitsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_rpc_update_user_limits_proto_msgTypes[0]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use UpdateUserLimitsRequest.ProtoReflect.Descriptor instead.
+func (*UpdateUserLimitsRequest) Descriptor() ([]byte, []int) {
+	return file_rpc_update_user_limits_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *UpdateUserLimitsRequest) GetCurrency() string {
+	if x != nil {
+		return x.Currency
+	}
+	return ""
+}
+
+func (x *UpdateUserLimitsRequest) GetMaxTransferAmount() int64 {
+	if x != nil && x.MaxTransferAmount != nil {
+		return *x.MaxTransferAmount
+	}
+	return 0
+}
+
+func (x *UpdateUserLimitsRequest) GetDailyAmount() int64 {
+	if x != nil && x.DailyAmount != nil {
+		return *x.DailyAmount
+	}
+	return 0
+}
+
+func (x *UpdateUserLimitsRequest) GetMonthlyAmount() int64 {
+	if x != nil && x.MonthlyAmount != nil {
+		return *x.MonthlyAmount
+	}
+	return 0
+}
+
+func (x *UpdateUserLimitsRequest) GetHourlyTransfers() int32 {
+	if x != nil && x.HourlyTransfers != nil {
+		return *x.HourlyTransfers
+	}
+	return 0
+}
+
+func (x *UpdateUserLimitsRequest) GetUsername() string {
+	if x != nil {
+		return x.Username
+	}
+	return ""
+}
+
+type UpdateUserLimitsResponse struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Limits *TransferLimits `protobuf:"bytes,1,opt,name=limits,proto3" json:"limits,omitempty"`
+}
+
+func (x *UpdateUserLimitsResponse) Reset() {
+	*x = UpdateUserLimitsResponse{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_rpc_update_user_limits_proto_msgTypes[1]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *UpdateUserLimitsResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*UpdateUserLimitsResponse) ProtoMessage() {}
+
+func (x *UpdateUserLimitsResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_rpc_update_user_limits_proto_msgTypes[1]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use UpdateUserLimitsResponse.ProtoReflect.Descriptor instead.
+func (*UpdateUserLimitsResponse) Descriptor() ([]byte, []int) {
+	return file_rpc_update_user_limits_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *UpdateUserLimitsResponse) GetLimits() *TransferLimits {
+	if x != nil {
+		return x.Limits
+	}
+	return nil
+}
+
+var File_rpc_update_user_limits_proto protoreflect.FileDescriptor
+
+var file_rpc_update_user_limits_proto_rawDesc = []byte{
+	0x0a, 0x1c, 0x72, 0x70, 0x63, 0x5f, 0x75, 0x70, 0x64, 0x61, 0x74, 0x65, 0x5f, 0x75, 0x73, 0x65,
+	0x72, 0x5f, 0x6c, 0x69, 0x6d, 0x69, 0x74, 0x73, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x12, 0x02,
+	0x70, 0x62, 0x1a, 0x14, 0x74, 0x72, 0x61, 0x6e, 0x73, 0x66, 0x65, 0x72, 0x5f, 0x6c, 0x69, 0x6d,
+	0x69, 0x74, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x22, 0xd1, 0x02, 0x0a, 0x17, 0x55, 0x70, 0x64,
+	0x61, 0x74, 0x65, 0x55, 0x73, 0x65, 0x72, 0x4c, 0x69, 0x6d, 0x69, 0x74, 0x73, 0x52, 0x65, 0x71,
+	0x75, 0x65, 0x73, 0x74, 0x12, 0x1a, 0x0a, 0x08, 0x63, 0x75, 0x72, 0x72, 0x65, 0x6e, 0x63, 0x79,
+	0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x08, 0x63, 0x75, 0x72, 0x72, 0x65, 0x6e, 0x63, 0x79,
+	0x12, 0x31, 0x0a, 0x11, 0x6d, 0x61, 0x78, 0x54, 0x72, 0x61, 0x6e, 0x73, 0x66, 0x65, 0x72, 0x41,
+	0x6d, 0x6f, 0x75, 0x6e, 0x74, 0x18, 0x02, 0x20, 0x01, 0x28, 0x03, 0x48, 0x00, 0x52, 0x11, 0x6d,
+	0x61, 0x78, 0x54, 0x72, 0x61, 0x6e, 0x73, 0x66, 0x65, 0x72, 0x41, 0x6d, 0x6f, 0x75, 0x6e, 0x74,
+	0x88, 0x01, 0x01, 0x12, 0x25, 0x0a, 0x0b, 0x64, 0x61, 0x69, 0x6c, 0x79, 0x41, 0x6d, 0x6f, 0x75,
+	0x6e, 0x74, 0x18, 0x03, 0x20, 0x01, 0x28, 0x03, 0x48, 0x01, 0x52, 0x0b, 0x64, 0x61, 0x69, 0x6c,
+	0x79, 0x41, 0x6d, 0x6f, 0x75, 0x6e, 0x74, 0x88, 0x01, 0x01, 0x12, 0x29, 0x0a, 0x0d, 0x6d, 0x6f,
+	0x6e, 0x74, 0x68, 0x6c, 0x79, 0x41, 0x6d, 0x6f, 0x75, 0x6e, 0x74, 0x18, 0x04, 0x20, 0x01, 0x28,
+	0x03, 0x48, 0x02, 0x52, 0x0d, 0x6d, 0x6f, 0x6e, 0x74, 0x68, 0x6c, 0x79, 0x41, 0x6d, 0x6f, 0x75,
+	0x6e, 0x74, 0x88, 0x01, 0x01, 0x12, 0x2d, 0x0a, 0x0f, 0x68, 0x6f, 0x75, 0x72, 0x6c, 0x79, 0x54,
+	0x72, 0x61, 0x6e, 0x73, 0x66, 0x65, 0x72, 0x73, 0x18, 0x05, 0x20, 0x01, 0x28, 0x05, 0x48, 0x03,
+	0x52, 0x0f, 0x68, 0x6f, 0x75, 0x72, 0x6c, 0x79, 0x54, 0x72, 0x61, 0x6e, 0x73, 0x66, 0x65, 0x72,
+	0x73, 0x88, 0x01, 0x01, 0x12, 0x1a, 0x0a, 0x08, 0x75, 0x73, 0x65, 0x72, 0x6e, 0x61, 0x6d, 0x65,
+	0x18, 0x06, 0x20, 0x01, 0x28, 0x09, 0x52, 0x08, 0x75, 0x73, 0x65, 0x72, 0x6e, 0x61, 0x6d, 0x65,
+	0x42, 0x14, 0x0a, 0x12, 0x5f, 0x6d, 0x61, 0x78, 0x54, 0x72, 0x61, 0x6e, 0x73, 0x66, 0x65, 0x72,
+	0x41, 0x6d, 0x6f, 0x75, 0x6e, 0x74, 0x42, 0x0e, 0x0a, 0x0c, 0x5f, 0x64, 0x61, 0x69, 0x6c, 0x79,
+	0x41, 0x6d, 0x6f, 0x75, 0x6e, 0x74, 0x42, 0x10, 0x0a, 0x0e, 0x5f, 0x6d, 0x6f, 0x6e, 0x74, 0x68,
+	0x6c, 0x79, 0x41, 0x6d, 0x6f, 0x75, 0x6e, 0x74, 0x42, 0x12, 0x0a, 0x10, 0x5f, 0x68, 0x6f, 0x75,
+	0x72, 0x6c, 0x79, 0x54, 0x72, 0x61, 0x6e, 0x73, 0x66, 0x65, 0x72, 0x73, 0x22, 0x46, 0x0a, 0x18,
+	0x55, 0x70, 0x64, 0x61, 0x74, 0x65, 0x55, 0x73, 0x65, 0x72, 0x4c, 0x69, 0x6d, 0x69, 0x74, 0x73,
+	0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x2a, 0x0a, 0x06, 0x6c, 0x69, 0x6d, 0x69,
+	0x74, 0x73, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x12, 0x2e, 0x70, 0x62, 0x2e, 0x54, 0x72,
+	0x61, 0x6e, 0x73, 0x66, 0x65, 0x72, 0x4c, 0x69, 0x6d, 0x69, 0x74, 0x73, 0x52, 0x06, 0x6c, 0x69,
+	0x6d, 0x69, 0x74, 0x73, 0x42, 0x10, 0x5a, 0x0e, 0x73, 0x69, 0x6d, 0x70, 0x6c, 0x65, 0x5f, 0x62,
+	0x61, 0x6e, 0x6b, 0x2f, 0x70, 0x62, 0x62, 0x06, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x33,
+}
+
+var (
+	file_rpc_update_user_limits_proto_rawDescOnce sync.Once
+	file_rpc_update_user_limits_proto_rawDescData = file_rpc_update_user_limits_proto_rawDesc
+)
+
+func file_rpc_update_user_limits_proto_rawDescGZIP() []byte {
+	file_rpc_update_user_limits_proto_rawDescOnce.Do(func() {
+		file_rpc_update_user_limits_proto_rawDescData = protoimpl.X.CompressGZIP(file_rpc_update_user_limits_proto_rawDescData)
+	})
+	return file_rpc_update_user_limits_proto_rawDescData
+}
+
+var file_rpc_update_user_limits_proto_msgTypes = make([]protoimpl.MessageInfo, 2)
+var file_rpc_update_user_limits_proto_goTypes = []interface{}{
+	(*UpdateUserLimitsRequest)(nil),  // 0: pb.UpdateUserLimitsRequest
+	(*UpdateUserLimitsResponse)(nil), // 1: pb.UpdateUserLimitsResponse
+	(*TransferLimits)(nil),           // 2: pb.TransferLimits
+}
+var file_rpc_update_user_limits_proto_depIdxs = []int32{
+	2, // 0: pb.UpdateUserLimitsResponse.limits:type_name -> pb.TransferLimits
+	1, // [1:1] is the sub-list for method output_type
+	1, // [1:1] is the sub-list for method input_type
+	1, // [1:1] is the sub-list for extension type_name
+	1, // [1:1] is the sub-list for extension extendee
+	0, // [0:1] is the sub-list for field type_name
+}
+
+func init() { file_rpc_update_user_limits_proto_init() }
+func file_rpc_update_user_limits_proto_init() {
+	if File_rpc_update_user_limits_proto != nil {
+		return
+	}
+	file_transfer_limit_proto_init()
+	if !protoimpl.UnsafeEnabled {
+		file_rpc_update_user_limits_proto_msgTypes[0].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*UpdateUserLimitsRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_rpc_update_user_limits_proto_msgTypes[1].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*UpdateUserLimitsResponse); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+	}
+	file_rpc_update_user_limits_proto_msgTypes[0].OneofWrappers = []interface{}{}
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: file_rpc_update_user_limits_proto_rawDesc,
+			NumEnums:      0,
+			NumMessages:   2,
+			NumExtensions: 0,
+			NumServices:   0,
+		},
+		GoTypes:           file_rpc_update_user_limits_proto_goTypes,
+		DependencyIndexes: file_rpc_update_user_limits_proto_depIdxs,
+		MessageInfos:      file_rpc_update_user_limits_proto_msgTypes,
+	}.Build()
+	File_rpc_update_user_limits_proto = out.File
+	file_rpc_update_user_limits_proto_rawDesc = nil
+	file_rpc_update_user_limits_proto_goTypes = nil
+	file_rpc_update_user_limits_proto_depIdxs = nil
+}
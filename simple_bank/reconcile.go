@@ -0,0 +1,125 @@
+package main
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"log"
+	db "simple_bank/db/sqlc"
+	"simple_bank/reconcile"
+	"simple_bank/util"
+	"time"
+
+	"golang.org/x/sync/errgroup"
+)
+
+// runReconcileCommand handles the "reconcile" subcommand, it checks the ledger once and
+// fails when inconsistencies are found so it can be used from cron or CI
+func runReconcileCommand(config util.Config) error {
+	conn, err := sql.Open(config.DbDriver, config.DbSource)
+	if err != nil {
+		return fmt.Errorf("cannot connect to db: %w", err)
+	}
+	defer conn.Close()
+
+	reconciler := newReconciler(config, db.NewStore(conn))
+	report, err := reconciler.Run(context.Background())
+	if err != nil {
+		return err
+	}
+	if !report.Consistent() {
+		return fmt.Errorf("ledger is inconsistent: %d balance mismatch(es), %d unbalanced transfer(s)",
+			len(report.BalanceMismatches), len(report.UnbalancedTransfers))
+	}
+	log.Print("ledger is consistent")
+	return nil
+}
+
+// reconcilerLockID keys the Postgres advisory lock held by the replica running the reconciliation job,
+// any value works as long as no other advisory lock of the database uses it
+const reconcilerLockID = 16016
+
+// runReconciler reconciles the ledger every ReconcileInterval until ctx is done.
+// Only the replica holding the reconciler lock runs a pass, the others keep trying to take it over.
+// A failed run is logged and does not stop the servers.
+func runReconciler(ctx context.Context, waitGroup *errgroup.Group, config util.Config, conn *sql.DB, store db.Store) {
+	if config.ReconcileInterval <= 0 {
+		return
+	}
+	reconciler := newReconciler(config, store)
+	lock := &advisoryLock{db: conn, id: reconcilerLockID}
+	waitGroup.Go(func() error {
+		defer lock.release()
+		ticker := time.NewTicker(config.ReconcileInterval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ctx.Done():
+				return nil
+			case <-ticker.C:
+				held, err := lock.acquire(ctx)
+				if err != nil {
+					log.Print("cannot take the reconciler lock: ", err)
+					continue
+				}
+				if !held {
+					continue
+				}
+				if _, err := reconciler.Run(ctx); err != nil {
+					log.Print("reconciliation failed: ", err)
+				}
+			}
+		}
+	})
+}
+
+// advisoryLock is a session level Postgres advisory lock. The session is a connection kept out of
+// the pool for as long as the lock is held, when it breaks the lock is gone with it.
+type advisoryLock struct {
+	db   *sql.DB
+	id   int64
+	conn *sql.Conn
+}
+
+// acquire tells whether the lock is held, trying to take it when it is not
+func (lock *advisoryLock) acquire(ctx context.Context) (bool, error) {
+	if lock.conn != nil {
+		if lock.conn.PingContext(ctx) == nil {
+			return true, nil
+		}
+		lock.release()
+	}
+	conn, err := lock.db.Conn(ctx)
+	if err != nil {
+		return false, err
+	}
+	var locked bool
+	err = conn.QueryRowContext(ctx, "SELECT pg_try_advisory_lock($1)", lock.id).Scan(&locked)
+	if err != nil || !locked {
+		conn.Close()
+		return false, err
+	}
+	lock.conn = conn
+	return true, nil
+}
+
+// release unlocks before handing the connection back, the pool would keep the session and the lock otherwise
+func (lock *advisoryLock) release() {
+	if lock.conn == nil {
+		return
+	}
+	_, err := lock.conn.ExecContext(context.Background(), "SELECT pg_advisory_unlock($1)", lock.id)
+	if err != nil {
+		log.Print("cannot release advisory lock: ", err)
+	}
+	lock.conn.Close()
+	lock.conn = nil
+}
+
+func newReconciler(config util.Config, store db.Store) *reconcile.Reconciler {
+	var notifier reconcile.Notifier = reconcile.LogNotifier{}
+	if config.ReconcileWebhookUrl != "" {
+		notifier = reconcile.NewWebhookNotifier(config.ReconcileWebhookUrl)
+	}
+	return reconcile.NewReconciler(store, config.ReconcileReportDir, notifier)
+}
@@ -0,0 +1,59 @@
+package reconcile
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/http"
+	"time"
+)
+
+// Notifier raises an alert when a reconciliation run finds inconsistencies
+type Notifier interface {
+	Notify(ctx context.Context, report Report) error
+}
+
+// LogNotifier writes a summary of the report to the standard logger
+type LogNotifier struct{}
+
+func (LogNotifier) Notify(ctx context.Context, report Report) error {
+	log.Printf("reconciliation found %d balance mismatch(es) and %d unbalanced transfer(s), report: %s",
+		len(report.BalanceMismatches), len(report.UnbalancedTransfers), report.Path)
+	return nil
+}
+
+// WebhookNotifier posts the report as JSON to a URL, e.g. a chat or paging integration
+type WebhookNotifier struct {
+	url    string
+	client *http.Client
+}
+
+func NewWebhookNotifier(url string) *WebhookNotifier {
+	return &WebhookNotifier{
+		url:    url,
+		client: &http.Client{Timeout: 10 * time.Second},
+	}
+}
+
+func (notifier *WebhookNotifier) Notify(ctx context.Context, report Report) error {
+	body, err := json.Marshal(report)
+	if err != nil {
+		return err
+	}
+	request, err := http.NewRequestWithContext(ctx, http.MethodPost, notifier.url, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	request.Header.Set("Content-Type", "application/json")
+	response, err := notifier.client.Do(request)
+	if err != nil {
+		return err
+	}
+	defer response.Body.Close()
+	if response.StatusCode >= 300 {
+		return fmt.Errorf("webhook responded with status %d", response.StatusCode)
+	}
+	return nil
+}
@@ -0,0 +1,92 @@
+package reconcile
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	db "simple_bank/db/sqlc"
+	"time"
+)
+
+// Report lists every ledger inconsistency found by a reconciliation run
+type Report struct {
+	StartedAt  time.Time `json:"startedAt"`
+	FinishedAt time.Time `json:"finishedAt"`
+	// accounts whose balance differs from the sum of their entries
+	BalanceMismatches []db.ListBalanceMismatchesRow `json:"balanceMismatches"`
+	// transfers without exactly one debit of amount and one credit of toAmount,
+	// for same currency transfers this means the two entries do not net to zero
+	UnbalancedTransfers []db.ListUnbalancedTransfersRow `json:"unbalancedTransfers"`
+	// file the report was written to, empty when no report directory is configured
+	Path string `json:"-"`
+}
+
+// Consistent reports whether the run found nothing to fix
+func (report Report) Consistent() bool {
+	return len(report.BalanceMismatches) == 0 && len(report.UnbalancedTransfers) == 0
+}
+
+// Reconciler compares account balances and transfers with the entries that back them
+type Reconciler struct {
+	store     db.Store
+	reportDir string
+	notifier  Notifier
+}
+
+// NewReconciler writes reports to reportDir when it is not empty, and raises an alert through
+// the notifier when a run is not consistent. A nil notifier disables alerts.
+func NewReconciler(store db.Store, reportDir string, notifier Notifier) *Reconciler {
+	return &Reconciler{
+		store:     store,
+		reportDir: reportDir,
+		notifier:  notifier,
+	}
+}
+
+// Run checks the whole ledger once
+func (reconciler *Reconciler) Run(ctx context.Context) (Report, error) {
+	report := Report{StartedAt: time.Now().UTC()}
+	var err error
+	report.BalanceMismatches, err = reconciler.store.ListBalanceMismatches(ctx)
+	if err != nil {
+		return report, fmt.Errorf("cannot list balance mismatches: %w", err)
+	}
+	report.UnbalancedTransfers, err = reconciler.store.ListUnbalancedTransfers(ctx)
+	if err != nil {
+		return report, fmt.Errorf("cannot list unbalanced transfers: %w", err)
+	}
+	report.FinishedAt = time.Now().UTC()
+
+	if reconciler.reportDir != "" {
+		report.Path, err = writeReport(reconciler.reportDir, report)
+		if err != nil {
+			return report, err
+		}
+	}
+	if !report.Consistent() && reconciler.notifier != nil {
+		err = reconciler.notifier.Notify(ctx, report)
+		if err != nil {
+			return report, fmt.Errorf("cannot send reconciliation alert: %w", err)
+		}
+	}
+	return report, nil
+}
+
+func writeReport(dir string, report Report) (string, error) {
+	err := os.MkdirAll(dir, 0o755)
+	if err != nil {
+		return "", fmt.Errorf("cannot create report directory: %w", err)
+	}
+	data, err := json.MarshalIndent(report, "", "  ")
+	if err != nil {
+		return "", err
+	}
+	path := filepath.Join(dir, fmt.Sprintf("reconciliation-%s.json", report.StartedAt.Format("20060102T150405Z")))
+	err = os.WriteFile(path, data, 0o644)
+	if err != nil {
+		return "", fmt.Errorf("cannot write report: %w", err)
+	}
+	return path, nil
+}
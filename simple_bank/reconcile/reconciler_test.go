@@ -0,0 +1,88 @@
+package reconcile
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	mockdb "simple_bank/db/mock"
+	db "simple_bank/db/sqlc"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+)
+
+type recordingNotifier struct {
+	reports []Report
+}
+
+func (notifier *recordingNotifier) Notify(ctx context.Context, report Report) error {
+	notifier.reports = append(notifier.reports, report)
+	return nil
+}
+
+func TestReconcilerRun(t *testing.T) {
+	mismatches := []db.ListBalanceMismatchesRow{{ID: 1, Currency: "USD", Balance: 100, EntrySum: 90}}
+	transfers := []db.ListUnbalancedTransfersRow{{ID: 2, FromAccountId: 1, ToAccountId: 3, Amount: 10, ToAmount: 10, EntryCount: 1, FromEntrySum: -10}}
+
+	controller := gomock.NewController(t)
+	store := mockdb.NewMockStore(controller)
+	store.EXPECT().ListBalanceMismatches(gomock.Any()).Times(1).Return(mismatches, nil)
+	store.EXPECT().ListUnbalancedTransfers(gomock.Any()).Times(1).Return(transfers, nil)
+
+	notifier := &recordingNotifier{}
+	reconciler := NewReconciler(store, t.TempDir(), notifier)
+	report, err := reconciler.Run(context.Background())
+	require.NoError(t, err)
+	require.False(t, report.Consistent())
+	require.Equal(t, mismatches, report.BalanceMismatches)
+	require.Equal(t, transfers, report.UnbalancedTransfers)
+	require.Len(t, notifier.reports, 1)
+
+	data, err := os.ReadFile(report.Path)
+	require.NoError(t, err)
+	var written Report
+	err = json.Unmarshal(data, &written)
+	require.NoError(t, err)
+	require.Equal(t, mismatches, written.BalanceMismatches)
+	require.Equal(t, transfers, written.UnbalancedTransfers)
+}
+
+func TestReconcilerRunConsistent(t *testing.T) {
+	controller := gomock.NewController(t)
+	store := mockdb.NewMockStore(controller)
+	store.EXPECT().ListBalanceMismatches(gomock.Any()).Times(1).Return([]db.ListBalanceMismatchesRow{}, nil)
+	store.EXPECT().ListUnbalancedTransfers(gomock.Any()).Times(1).Return([]db.ListUnbalancedTransfersRow{}, nil)
+
+	notifier := &recordingNotifier{}
+	reconciler := NewReconciler(store, "", notifier)
+	report, err := reconciler.Run(context.Background())
+	require.NoError(t, err)
+	require.True(t, report.Consistent())
+	require.Empty(t, report.Path)
+	require.Empty(t, notifier.reports)
+}
+
+func TestWebhookNotifier(t *testing.T) {
+	var received Report
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		require.Equal(t, http.MethodPost, r.Method)
+		err := json.NewDecoder(r.Body).Decode(&received)
+		require.NoError(t, err)
+	}))
+	defer server.Close()
+
+	report := Report{BalanceMismatches: []db.ListBalanceMismatchesRow{{ID: 1, Balance: 5, EntrySum: 0}}}
+	err := NewWebhookNotifier(server.URL).Notify(context.Background(), report)
+	require.NoError(t, err)
+	require.Equal(t, report.BalanceMismatches, received.BalanceMismatches)
+
+	failing := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusInternalServerError)
+	}))
+	defer failing.Close()
+	err = NewWebhookNotifier(failing.URL).Notify(context.Background(), report)
+	require.Error(t, err)
+}
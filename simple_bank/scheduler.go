@@ -0,0 +1,46 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"log"
+	db "simple_bank/db/sqlc"
+	"simple_bank/exchange"
+	"simple_bank/scheduler"
+	"simple_bank/util"
+	"time"
+
+	"golang.org/x/sync/errgroup"
+)
+
+// runScheduler runs the due scheduled transfers every SchedulerInterval until ctx is done. Every replica
+// can run it, each due transfer is claimed by a single one. A failed pass is logged and does not stop the servers.
+func runScheduler(ctx context.Context, waitGroup *errgroup.Group, config util.Config, store db.Store) error {
+	if config.SchedulerInterval <= 0 {
+		return nil
+	}
+	exchangeRates, err := exchange.NewFileProvider(config.ExchangeRatesFile)
+	if err != nil {
+		return fmt.Errorf("cannot create an exchange rate provider: %w", err)
+	}
+	policy := scheduler.RetryPolicy{
+		MaxAttempts: config.SchedulerMaxAttempts,
+		Delay:       config.SchedulerRetryDelay,
+	}
+	transferScheduler := scheduler.NewScheduler(store, exchangeRates, policy, config.SchedulerBatchSize)
+	waitGroup.Go(func() error {
+		ticker := time.NewTicker(config.SchedulerInterval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ctx.Done():
+				return nil
+			case <-ticker.C:
+				if _, err := transferScheduler.RunDue(ctx); err != nil {
+					log.Print("scheduled transfers failed: ", err)
+				}
+			}
+		}
+	})
+	return nil
+}
@@ -0,0 +1,159 @@
+package scheduler
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"log"
+	db "simple_bank/db/sqlc"
+	"simple_bank/exchange"
+	"simple_bank/util"
+	"time"
+)
+
+// claimLease is how long a claimed transfer stays hidden from other schedulers. When a scheduler dies
+// mid-run the transfer is picked up again after the lease, the idempotency key of the run makes sure
+// the money does not move twice.
+const claimLease = 5 * time.Minute
+
+// RetryPolicy decides whether and when a failed run is attempted again
+type RetryPolicy struct {
+	// attempts of a run before it is given up, 1 disables retries
+	MaxAttempts int
+	// wait before the first retry, doubled for every following one
+	Delay time.Duration
+}
+
+// backoff returns how long to wait after the given failed attempt
+func (policy RetryPolicy) backoff(attempt int32) time.Duration {
+	delay := policy.Delay
+	for i := int32(1); i < attempt; i++ {
+		delay *= 2
+	}
+	return delay
+}
+
+// Scheduler executes the scheduled transfers that are due through TransferTx. Several schedulers can
+// share a database: due transfers are claimed with FOR UPDATE SKIP LOCKED so each one is run by a
+// single scheduler.
+type Scheduler struct {
+	store         db.Store
+	exchangeRates exchange.ExchangeRateProvider
+	policy        RetryPolicy
+	batchSize     int32
+	now           func() time.Time
+}
+
+func NewScheduler(store db.Store, exchangeRates exchange.ExchangeRateProvider, policy RetryPolicy, batchSize int32) *Scheduler {
+	return &Scheduler{
+		store:         store,
+		exchangeRates: exchangeRates,
+		policy:        policy,
+		batchSize:     batchSize,
+		now:           time.Now,
+	}
+}
+
+// RunDue claims the transfers that are due, runs them and records the outcome. It returns how many
+// transfers were run; a failed transfer is recorded and retried later, it is not returned as an error.
+func (scheduler *Scheduler) RunDue(ctx context.Context) (int, error) {
+	now := scheduler.now().UTC()
+	due, err := scheduler.store.ClaimDueScheduledTransfers(ctx, db.ClaimDueScheduledTransfersParams{
+		LockedUntil: now.Add(claimLease),
+		Now:         now,
+		RowLimit:    scheduler.batchSize,
+	})
+	if err != nil {
+		return 0, fmt.Errorf("cannot claim scheduled transfers: %w", err)
+	}
+	for i, scheduledTransfer := range due {
+		if err := scheduler.run(ctx, scheduledTransfer, now); err != nil {
+			return i, err
+		}
+	}
+	return len(due), nil
+}
+
+func (scheduler *Scheduler) run(ctx context.Context, scheduledTransfer db.ScheduledTransfer, now time.Time) error {
+	attempt := scheduledTransfer.Attempts + 1
+	arg := db.RecordScheduledTransferRunTxParams{
+		Run: db.CreateScheduledTransferRunParams{
+			ScheduledTransferId: scheduledTransfer.ID,
+			ScheduledFor:        scheduledTransfer.NextRunAt,
+			Attempt:             attempt,
+		},
+		Next: db.FinishScheduledTransferRunParams{
+			ID:     scheduledTransfer.ID,
+			Status: scheduledTransfer.Status,
+		},
+	}
+	result, err := scheduler.transfer(ctx, scheduledTransfer)
+	switch {
+	case err == nil:
+		arg.Run.Status = db.ScheduledTransferRunSucceeded
+		arg.Run.TransferId = sql.NullInt64{Int64: result.Transfer.ID, Valid: true}
+		scheduler.advance(&arg.Next, scheduledTransfer, now, db.ScheduledTransferStatusCompleted)
+	case int(attempt) < scheduler.policy.MaxAttempts:
+		arg.Run.Status = db.ScheduledTransferRunFailed
+		arg.Run.Error = err.Error()
+		arg.Next.NextRunAt = now.Add(scheduler.policy.backoff(attempt))
+		arg.Next.Attempts = attempt
+	default:
+		log.Printf("scheduled transfer [%d] failed after %d attempt(s): %v", scheduledTransfer.ID, attempt, err)
+		arg.Run.Status = db.ScheduledTransferRunFailed
+		arg.Run.Error = err.Error()
+		scheduler.advance(&arg.Next, scheduledTransfer, now, db.ScheduledTransferStatusFailed)
+	}
+	_, err = scheduler.store.RecordScheduledTransferRunTx(ctx, arg)
+	if err != nil {
+		return fmt.Errorf("cannot record run of scheduled transfer [%d]: %w", scheduledTransfer.ID, err)
+	}
+	return nil
+}
+
+// advance moves a recurring transfer to its next occurrence after now, skipping the ones that were
+// missed, and ends a one-off transfer with the given status
+func (scheduler *Scheduler) advance(next *db.FinishScheduledTransferRunParams, scheduledTransfer db.ScheduledTransfer, now time.Time, lastStatus string) {
+	next.Attempts = 0
+	next.NextRunAt = scheduledTransfer.NextRunAt
+	if scheduledTransfer.Schedule == "" {
+		next.Status = lastStatus
+		return
+	}
+	schedule, err := util.ParseSchedule(scheduledTransfer.Schedule)
+	if err != nil {
+		// schedules are validated when they are saved, this only happens if the syntax gets stricter
+		log.Printf("scheduled transfer [%d] has an invalid schedule: %v", scheduledTransfer.ID, err)
+		next.Status = db.ScheduledTransferStatusFailed
+		return
+	}
+	next.NextRunAt = schedule.Next(now)
+}
+
+// transfer moves the money of one run. The idempotency key is derived from the run, so a run picked
+// up again after its lease expired returns the transfer that was already booked.
+func (scheduler *Scheduler) transfer(ctx context.Context, scheduledTransfer db.ScheduledTransfer) (db.TransferTxResult, error) {
+	arg := db.TransferTxParams{
+		FromAccountID:  scheduledTransfer.FromAccountId,
+		ToAccountID:    scheduledTransfer.ToAccountId,
+		Amount:         scheduledTransfer.Amount,
+		IdempotencyKey: fmt.Sprintf("scheduled-%d-%d", scheduledTransfer.ID, scheduledTransfer.NextRunAt.Unix()),
+		Username:       scheduledTransfer.Username,
+	}
+	fromAccount, err := scheduler.store.GetAccount(ctx, scheduledTransfer.FromAccountId)
+	if err != nil {
+		return db.TransferTxResult{}, err
+	}
+	toAccount, err := scheduler.store.GetAccount(ctx, scheduledTransfer.ToAccountId)
+	if err != nil {
+		return db.TransferTxResult{}, err
+	}
+	if fromAccount.Currency != toAccount.Currency {
+		rate, err := scheduler.exchangeRates.GetRate(ctx, fromAccount.Currency, toAccount.Currency)
+		if err != nil {
+			return db.TransferTxResult{}, err
+		}
+		arg.ExchangeRate = &rate
+	}
+	return scheduler.store.TransferTx(ctx, arg)
+}
@@ -0,0 +1,158 @@
+package scheduler
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	mockdb "simple_bank/db/mock"
+	db "simple_bank/db/sqlc"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+)
+
+var testPolicy = RetryPolicy{MaxAttempts: 3, Delay: time.Minute}
+
+func newTestScheduler(t *testing.T, store db.Store, now time.Time) *Scheduler {
+	scheduler := NewScheduler(store, nil, testPolicy, 10)
+	scheduler.now = func() time.Time { return now }
+	return scheduler
+}
+
+func expectTransfer(t *testing.T, store *mockdb.MockStore, scheduledTransfer db.ScheduledTransfer, err error) {
+	account := db.Account{Currency: "USD"}
+	store.EXPECT().GetAccount(gomock.Any(), gomock.Any()).Times(2).Return(account, nil)
+	result := db.TransferTxResult{Transfer: db.Transfer{ID: 42}}
+	store.EXPECT().TransferTx(gomock.Any(), gomock.Any()).Times(1).
+		DoAndReturn(func(ctx context.Context, arg db.TransferTxParams) (db.TransferTxResult, error) {
+			require.Equal(t, scheduledTransfer.FromAccountId, arg.FromAccountID)
+			require.Equal(t, scheduledTransfer.ToAccountId, arg.ToAccountID)
+			require.Equal(t, scheduledTransfer.Amount, arg.Amount)
+			require.Equal(t, scheduledTransfer.Username, arg.Username)
+			require.NotEmpty(t, arg.IdempotencyKey)
+			return result, err
+		})
+}
+
+func TestRunDueOneOff(t *testing.T) {
+	now := time.Date(2024, 3, 1, 9, 0, 30, 0, time.UTC)
+	scheduledTransfer := db.ScheduledTransfer{ID: 1, Username: "john", FromAccountId: 1, ToAccountId: 2, Amount: 100,
+		NextRunAt: now.Add(-time.Minute), Status: db.ScheduledTransferStatusActive}
+
+	controller := gomock.NewController(t)
+	store := mockdb.NewMockStore(controller)
+	claim := db.ClaimDueScheduledTransfersParams{LockedUntil: now.Add(claimLease), Now: now, RowLimit: 10}
+	store.EXPECT().ClaimDueScheduledTransfers(gomock.Any(), gomock.Eq(claim)).Times(1).Return([]db.ScheduledTransfer{scheduledTransfer}, nil)
+	expectTransfer(t, store, scheduledTransfer, nil)
+	arg := db.RecordScheduledTransferRunTxParams{
+		Run: db.CreateScheduledTransferRunParams{
+			ScheduledTransferId: 1,
+			ScheduledFor:        scheduledTransfer.NextRunAt,
+			Attempt:             1,
+			Status:              db.ScheduledTransferRunSucceeded,
+			TransferId:          sql.NullInt64{Int64: 42, Valid: true},
+		},
+		Next: db.FinishScheduledTransferRunParams{
+			ID:        1,
+			NextRunAt: scheduledTransfer.NextRunAt,
+			Status:    db.ScheduledTransferStatusCompleted,
+		},
+	}
+	store.EXPECT().RecordScheduledTransferRunTx(gomock.Any(), gomock.Eq(arg)).Times(1)
+
+	n, err := newTestScheduler(t, store, now).RunDue(context.Background())
+	require.NoError(t, err)
+	require.Equal(t, 1, n)
+}
+
+func TestRunDueRecurring(t *testing.T) {
+	now := time.Date(2024, 3, 1, 9, 0, 30, 0, time.UTC)
+	scheduledTransfer := db.ScheduledTransfer{ID: 1, Username: "john", FromAccountId: 1, ToAccountId: 2, Amount: 100,
+		Schedule: "0 9 1 * *", NextRunAt: now.Add(-30 * time.Second), Status: db.ScheduledTransferStatusActive}
+
+	controller := gomock.NewController(t)
+	store := mockdb.NewMockStore(controller)
+	store.EXPECT().ClaimDueScheduledTransfers(gomock.Any(), gomock.Any()).Times(1).Return([]db.ScheduledTransfer{scheduledTransfer}, nil)
+	expectTransfer(t, store, scheduledTransfer, nil)
+	store.EXPECT().RecordScheduledTransferRunTx(gomock.Any(), gomock.Any()).Times(1).
+		DoAndReturn(func(ctx context.Context, arg db.RecordScheduledTransferRunTxParams) (db.RecordScheduledTransferRunTxResult, error) {
+			require.Equal(t, db.ScheduledTransferRunSucceeded, arg.Run.Status)
+			require.Equal(t, time.Date(2024, 4, 1, 9, 0, 0, 0, time.UTC), arg.Next.NextRunAt)
+			require.Equal(t, db.ScheduledTransferStatusActive, arg.Next.Status)
+			require.Zero(t, arg.Next.Attempts)
+			return db.RecordScheduledTransferRunTxResult{}, nil
+		})
+
+	_, err := newTestScheduler(t, store, now).RunDue(context.Background())
+	require.NoError(t, err)
+}
+
+func TestRunDueRetry(t *testing.T) {
+	now := time.Date(2024, 3, 1, 9, 0, 30, 0, time.UTC)
+	scheduledTransfer := db.ScheduledTransfer{ID: 1, Username: "john", FromAccountId: 1, ToAccountId: 2, Amount: 100,
+		Schedule: "0 9 1 * *", NextRunAt: now, Attempts: 1, Status: db.ScheduledTransferStatusActive}
+
+	controller := gomock.NewController(t)
+	store := mockdb.NewMockStore(controller)
+	store.EXPECT().ClaimDueScheduledTransfers(gomock.Any(), gomock.Any()).Times(1).Return([]db.ScheduledTransfer{scheduledTransfer}, nil)
+	expectTransfer(t, store, scheduledTransfer, db.ErrInsufficientFunds)
+	store.EXPECT().RecordScheduledTransferRunTx(gomock.Any(), gomock.Any()).Times(1).
+		DoAndReturn(func(ctx context.Context, arg db.RecordScheduledTransferRunTxParams) (db.RecordScheduledTransferRunTxResult, error) {
+			require.Equal(t, db.ScheduledTransferRunFailed, arg.Run.Status)
+			require.Equal(t, int32(2), arg.Run.Attempt)
+			require.Equal(t, db.ErrInsufficientFunds.Error(), arg.Run.Error)
+			require.False(t, arg.Run.TransferId.Valid)
+			// second failure waits twice the delay
+			require.Equal(t, now.Add(2*time.Minute), arg.Next.NextRunAt)
+			require.Equal(t, int32(2), arg.Next.Attempts)
+			return db.RecordScheduledTransferRunTxResult{}, nil
+		})
+
+	_, err := newTestScheduler(t, store, now).RunDue(context.Background())
+	require.NoError(t, err)
+}
+
+func TestRunDueGivesUp(t *testing.T) {
+	now := time.Date(2024, 3, 1, 9, 0, 30, 0, time.UTC)
+	oneOff := db.ScheduledTransfer{ID: 1, Username: "john", FromAccountId: 1, ToAccountId: 2, Amount: 100,
+		NextRunAt: now, Attempts: 2, Status: db.ScheduledTransferStatusActive}
+	recurring := oneOff
+	recurring.ID = 2
+	recurring.Schedule = "@daily"
+
+	controller := gomock.NewController(t)
+	store := mockdb.NewMockStore(controller)
+	store.EXPECT().ClaimDueScheduledTransfers(gomock.Any(), gomock.Any()).Times(1).Return([]db.ScheduledTransfer{oneOff, recurring}, nil)
+	store.EXPECT().GetAccount(gomock.Any(), gomock.Any()).Times(4).Return(db.Account{Currency: "USD"}, nil)
+	store.EXPECT().TransferTx(gomock.Any(), gomock.Any()).Times(2).Return(db.TransferTxResult{}, db.ErrInsufficientFunds)
+	var recorded []db.RecordScheduledTransferRunTxParams
+	store.EXPECT().RecordScheduledTransferRunTx(gomock.Any(), gomock.Any()).Times(2).
+		DoAndReturn(func(ctx context.Context, arg db.RecordScheduledTransferRunTxParams) (db.RecordScheduledTransferRunTxResult, error) {
+			recorded = append(recorded, arg)
+			return db.RecordScheduledTransferRunTxResult{}, nil
+		})
+
+	n, err := newTestScheduler(t, store, now).RunDue(context.Background())
+	require.NoError(t, err)
+	require.Equal(t, 2, n)
+	require.Len(t, recorded, 2)
+	// the one-off transfer fails for good
+	require.Equal(t, db.ScheduledTransferStatusFailed, recorded[0].Next.Status)
+	require.Zero(t, recorded[0].Next.Attempts)
+	// the recurring transfer skips to its next occurrence
+	require.Equal(t, db.ScheduledTransferStatusActive, recorded[1].Next.Status)
+	require.Equal(t, time.Date(2024, 3, 2, 0, 0, 0, 0, time.UTC), recorded[1].Next.NextRunAt)
+	require.Zero(t, recorded[1].Next.Attempts)
+}
+
+func TestRunDueClaimError(t *testing.T) {
+	controller := gomock.NewController(t)
+	store := mockdb.NewMockStore(controller)
+	store.EXPECT().ClaimDueScheduledTransfers(gomock.Any(), gomock.Any()).Times(1).Return(nil, errors.New("connection refused"))
+	store.EXPECT().TransferTx(gomock.Any(), gomock.Any()).Times(0)
+
+	_, err := newTestScheduler(t, store, time.Now()).RunDue(context.Background())
+	require.Error(t, err)
+}
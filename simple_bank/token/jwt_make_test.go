@@ -12,11 +12,12 @@ func TestJwtMaker(t *testing.T) {
 	maker, err := NewJwtMaker(util.RandomString(32))
 	require.NoError(t, err)
 	username := util.RandomUsername()
+	role := util.DepositorRole
 	duration := time.Minute
 	issuedAt := time.Now()
 	expiredAt := issuedAt.Add(duration)
 
-	token,payload, err := maker.CreateToken(username, duration)
+	token,payload, err := maker.CreateToken(username, role, duration)
 	require.NoError(t, err)
 	require.NotEmpty(t, token)
 	require.NotEmpty(t, payload)
@@ -26,6 +27,7 @@ func TestJwtMaker(t *testing.T) {
 	require.NotEmpty(t, payload)
 	require.NotZero(t, payload.Id)
 	require.Equal(t, username, payload.Username)
+	require.Equal(t, role, payload.Role)
 	require.WithinDuration(t, issuedAt, payload.IssuedAt, time.Second)
 	require.WithinDuration(t, expiredAt, payload.ExpiredAt, time.Second)
 }
@@ -33,7 +35,7 @@ func TestJwtMaker(t *testing.T) {
 func TestExpiredJwtToken(t *testing.T) {
 	maker, err := NewJwtMaker(util.RandomString(32))
 	require.NoError(t, err)
-	token,payload, err := maker.CreateToken(util.RandomUsername(), -time.Minute)
+	token,payload, err := maker.CreateToken(util.RandomUsername(), util.DepositorRole, -time.Minute)
 	require.NoError(t, err)
 	require.NotEmpty(t, token)
 	require.NotEmpty(t, payload)
@@ -45,7 +47,7 @@ func TestExpiredJwtToken(t *testing.T) {
 }
 
 func TestInvalidJwtToken(t *testing.T) {
-	payload := NewPayload(util.RandomUsername(), time.Minute)
+	payload := NewPayload(util.RandomUsername(), util.DepositorRole, time.Minute)
 	jwtToken := jwt.NewWithClaims(jwt.SigningMethodNone, payload)
 	tokenString, err := jwtToken.SignedString(jwt.UnsafeAllowNoneSignatureType)
 	require.NoError(t, err)
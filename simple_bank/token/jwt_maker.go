@@ -21,8 +21,8 @@ func NewJwtMaker(secretKey string) (Maker, error) {
 	return &JwtMaker{secretKey}, nil
 }
 
-func (maker *JwtMaker) CreateToken(username string, duration time.Duration) (string,*Payload, error) {
-	payload := NewPayload(username, duration)
+func (maker *JwtMaker) CreateToken(username string, role string, duration time.Duration) (string,*Payload, error) {
+	payload := NewPayload(username, role, duration)
 	jwtToken := jwt.NewWithClaims(jwt.SigningMethodHS256, payload)
 	token,err:= jwtToken.SignedString([]byte(maker.secretKey))
 	return token, payload, err
@@ -4,8 +4,8 @@ import "time"
 
 // Maker is an interface that creates and verifies tokens
 type Maker interface {
-	/// CreateToken creates a new token for a specific username and duration
-	CreateToken(username string, duration time.Duration) (string, *Payload, error)
+	/// CreateToken creates a new token for a specific username, role and duration
+	CreateToken(username string, role string, duration time.Duration) (string, *Payload, error)
 	/// VerifyToken checks if the token is valid or not
 	VerifyToken(token string) (*Payload, error)
 }
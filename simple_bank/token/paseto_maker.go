@@ -24,8 +24,8 @@ func NewPasetoMaker(key string) (Maker, error) {
 	return maker, nil
 }
 
-func (maker *PasetoMaker) CreateToken(username string, duration time.Duration) (string, *Payload, error) {
-	payload := NewPayload(username, duration)
+func (maker *PasetoMaker) CreateToken(username string, role string, duration time.Duration) (string, *Payload, error) {
+	payload := NewPayload(username, role, duration)
 	token, err := maker.paseto.Encrypt(maker.key, payload, []byte("optionalfooter"))
 	return token, payload, err
 }
@@ -19,14 +19,16 @@ var (
 type Payload struct {
 	Id        uuid.UUID `json:"id"`
 	Username  string    `json:"username"`
+	Role      string    `json:"role"`
 	IssuedAt  time.Time `json:"issuedAt"`
 	ExpiredAt time.Time `json:"expiredAt"`
 }
 
-func NewPayload(username string, duration time.Duration) *Payload {
+func NewPayload(username string, role string, duration time.Duration) *Payload {
 	return &Payload{
 		Id:        uuid.New(),
 		Username:  username,
+		Role:      role,
 		IssuedAt:  time.Now(),
 		ExpiredAt: time.Now().Add(duration),
 	}
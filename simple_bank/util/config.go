@@ -7,13 +7,43 @@ import (
 )
 
 type Config struct {
-	DbDriver             string        `mapstructure:"DB_DRIVER"`
-	DbSource             string        `mapstructure:"DB_SOURCE"`
-	HttpServerAddress    string        `mapstructure:"HTTP_SERVER_ADDRESS"`
-	GrpcServerAddress    string        `mapstructure:"GRPC_SERVER_ADDRESS"`
-	TokenKey             string        `mapstructure:"TOKEN_KEY"`
-	AccessTokenDuration  time.Duration `mapstructure:"ACCESSTOKEN_DURATION"`
-	RefreshTokenDuration time.Duration `mapstructure:"REFRESH_TOKEN_DURATION"`
+	DbDriver                string        `mapstructure:"DB_DRIVER"`
+	DbSource                string        `mapstructure:"DB_SOURCE"`
+	HttpServerAddress       string        `mapstructure:"HTTP_SERVER_ADDRESS"`
+	GrpcServerAddress       string        `mapstructure:"GRPC_SERVER_ADDRESS"`
+	TokenKey                string        `mapstructure:"TOKEN_KEY"`
+	AccessTokenDuration     time.Duration `mapstructure:"ACCESSTOKEN_DURATION"`
+	RefreshTokenDuration    time.Duration `mapstructure:"REFRESH_TOKEN_DURATION"`
+	HttpServerType          string        `mapstructure:"HTTP_SERVER_TYPE"`
+	ShutdownTimeout         time.Duration `mapstructure:"SHUTDOWN_TIMEOUT"`
+	RunMigrations           bool          `mapstructure:"RUN_MIGRATIONS"`
+	ExchangeRatesFile       string        `mapstructure:"EXCHANGE_RATES_FILE"`
+	CurrencyRefreshInterval time.Duration `mapstructure:"CURRENCY_REFRESH_INTERVAL"`
+	// how often the ledger is reconciled in the background, 0 disables the job
+	ReconcileInterval   time.Duration `mapstructure:"RECONCILE_INTERVAL"`
+	ReconcileReportDir  string        `mapstructure:"RECONCILE_REPORT_DIR"`
+	ReconcileWebhookUrl string        `mapstructure:"RECONCILE_WEBHOOK_URL"`
+	// how often due scheduled transfers are run, 0 disables the scheduler
+	SchedulerInterval  time.Duration `mapstructure:"SCHEDULER_INTERVAL"`
+	SchedulerBatchSize int32         `mapstructure:"SCHEDULER_BATCH_SIZE"`
+	// attempts of a scheduled transfer run before it is given up, and the wait before the first retry
+	SchedulerMaxAttempts int           `mapstructure:"SCHEDULER_MAX_ATTEMPTS"`
+	SchedulerRetryDelay  time.Duration `mapstructure:"SCHEDULER_RETRY_DELAY"`
+	// SMTP server as host:port, the emails are only kept in memory when it is empty
+	SmtpAddress        string `mapstructure:"SMTP_ADDRESS"`
+	SmtpUsername       string `mapstructure:"SMTP_USERNAME"`
+	SmtpPassword       string `mapstructure:"SMTP_PASSWORD"`
+	EmailSenderName    string `mapstructure:"EMAIL_SENDER_NAME"`
+	EmailSenderAddress string `mapstructure:"EMAIL_SENDER_ADDRESS"`
+	// page the verification emails link to, it calls the verify email endpoint with the link parameters
+	VerifyEmailUrl string `mapstructure:"VERIFY_EMAIL_URL"`
+	// how often due background tasks are run, 0 disables the task processor
+	TaskInterval    time.Duration `mapstructure:"TASK_INTERVAL"`
+	TaskBatchSize   int32         `mapstructure:"TASK_BATCH_SIZE"`
+	TaskConcurrency int           `mapstructure:"TASK_CONCURRENCY"`
+	// attempts of a task before it is dead, and the wait before the first retry
+	TaskMaxAttempts int32         `mapstructure:"TASK_MAX_ATTEMPTS"`
+	TaskRetryDelay  time.Duration `mapstructure:"TASK_RETRY_DELAY"`
 }
 
 func LoadConfig(path string) (config Config, err error) {
@@ -21,6 +51,18 @@ func LoadConfig(path string) (config Config, err error) {
 	viper.SetConfigName("app")
 	viper.SetConfigType("env")
 	viper.AutomaticEnv()
+	viper.SetDefault("HTTP_SERVER_TYPE", "gateway")
+	viper.SetDefault("SHUTDOWN_TIMEOUT", 10*time.Second)
+	viper.SetDefault("CURRENCY_REFRESH_INTERVAL", time.Minute)
+	viper.SetDefault("SCHEDULER_BATCH_SIZE", 100)
+	viper.SetDefault("SCHEDULER_MAX_ATTEMPTS", 3)
+	viper.SetDefault("SCHEDULER_RETRY_DELAY", 5*time.Minute)
+	// unlike the scheduler the task processor runs by default, without it no verification email is sent
+	viper.SetDefault("TASK_INTERVAL", 5*time.Second)
+	viper.SetDefault("TASK_BATCH_SIZE", 20)
+	viper.SetDefault("TASK_CONCURRENCY", 4)
+	viper.SetDefault("TASK_MAX_ATTEMPTS", 5)
+	viper.SetDefault("TASK_RETRY_DELAY", 30*time.Second)
 	err = viper.ReadInConfig()
 	if err != nil {
 		return
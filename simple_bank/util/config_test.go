@@ -0,0 +1,24 @@
+package util
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestLoadConfigTaskDefaults(t *testing.T) {
+	dir := t.TempDir()
+	err := os.WriteFile(filepath.Join(dir, "app.env"), []byte("DB_DRIVER=postgres\n"), 0o600)
+	require.NoError(t, err)
+
+	config, err := LoadConfig(dir)
+	require.NoError(t, err)
+	require.Equal(t, 5*time.Second, config.TaskInterval)
+	require.Equal(t, int32(20), config.TaskBatchSize)
+	require.Equal(t, 4, config.TaskConcurrency)
+	require.Equal(t, int32(5), config.TaskMaxAttempts)
+	require.Equal(t, 30*time.Second, config.TaskRetryDelay)
+}
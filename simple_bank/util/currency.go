@@ -1,17 +1,67 @@
 package util
 
+import "sync"
+
+// Currency describes an ISO 4217 currency known to the bank. MinorUnit is the number of
+// digits after the decimal point, account balances and amounts are stored in minor units.
+type Currency struct {
+	Code      string
+	MinorUnit int
+	Symbol    string
+	Enabled   bool
+}
+
+var (
+	currenciesMutex sync.RWMutex
+	currencies      = []Currency{
+		{Code: "USD", MinorUnit: 2, Symbol: "$", Enabled: true},
+		{Code: "EUR", MinorUnit: 2, Symbol: "€", Enabled: true},
+		{Code: "JPY", MinorUnit: 0, Symbol: "¥", Enabled: true},
+		{Code: "CAD", MinorUnit: 2, Symbol: "CA$", Enabled: true},
+		{Code: "AUD", MinorUnit: 2, Symbol: "A$", Enabled: true},
+	}
+)
+
+// SetCurrencies replaces the currency registry, usually with the rows of the currency table.
+func SetCurrencies(registry []Currency) {
+	currenciesMutex.Lock()
+	defer currenciesMutex.Unlock()
+	currencies = append([]Currency(nil), registry...)
+}
+
+// GetCurrencyInfo returns the registry entry for code, enabled or not.
+func GetCurrencyInfo(code string) (Currency, bool) {
+	currenciesMutex.RLock()
+	defer currenciesMutex.RUnlock()
+	for _, c := range currencies {
+		if c.Code == code {
+			return c, true
+		}
+	}
+	return Currency{}, false
+}
+
 func getCurrencies() []string {
-	return []string{"USD", "EUR", "YEN", "CAD", "AUD"}
+	currenciesMutex.RLock()
+	defer currenciesMutex.RUnlock()
+	codes := make([]string, 0, len(currencies))
+	for _, c := range currencies {
+		if c.Enabled {
+			codes = append(codes, c.Code)
+		}
+	}
+	return codes
 }
 
 func GetCurrency(index int) string {
 	return getCurrencies()[index]
 }
 func IsSupportedCurrency(currency string) bool {
-	for _, c := range getCurrencies() {
-		if c == currency {
-			return true
-		}
-	}
-	return false
+	c, ok := GetCurrencyInfo(currency)
+	return ok && c.Enabled
+}
+
+// FormatAmount renders an amount in minor units as a decimal string, see Money.String
+func FormatAmount(amount int64, currency string) string {
+	return NewMoney(amount, currency).String()
 }
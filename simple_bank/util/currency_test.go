@@ -0,0 +1,45 @@
+package util
+
+import (
+	"math"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestCurrencyRegistry(t *testing.T) {
+	require.True(t, IsSupportedCurrency("JPY"))
+	require.False(t, IsSupportedCurrency("YEN"))
+
+	SetCurrencies([]Currency{
+		{Code: "USD", MinorUnit: 2, Symbol: "$", Enabled: true},
+		{Code: "EUR", MinorUnit: 2, Symbol: "€", Enabled: false},
+	})
+	t.Cleanup(func() {
+		SetCurrencies([]Currency{
+			{Code: "USD", MinorUnit: 2, Symbol: "$", Enabled: true},
+			{Code: "EUR", MinorUnit: 2, Symbol: "€", Enabled: true},
+			{Code: "JPY", MinorUnit: 0, Symbol: "¥", Enabled: true},
+			{Code: "CAD", MinorUnit: 2, Symbol: "CA$", Enabled: true},
+			{Code: "AUD", MinorUnit: 2, Symbol: "A$", Enabled: true},
+		})
+	})
+
+	require.True(t, IsSupportedCurrency("USD"))
+	require.False(t, IsSupportedCurrency("EUR"))
+	require.False(t, IsSupportedCurrency("JPY"))
+	require.Equal(t, []string{"USD"}, getCurrencies())
+
+	eur, ok := GetCurrencyInfo("EUR")
+	require.True(t, ok)
+	require.Equal(t, 2, eur.MinorUnit)
+	require.False(t, eur.Enabled)
+}
+
+func TestFormatAmount(t *testing.T) {
+	require.Equal(t, "123.45", FormatAmount(12345, "USD"))
+	require.Equal(t, "0.05", FormatAmount(5, "EUR"))
+	require.Equal(t, "-1.50", FormatAmount(-150, "CAD"))
+	require.Equal(t, "12345", FormatAmount(12345, "JPY"))
+	require.Equal(t, "-92233720368547758.08", FormatAmount(math.MinInt64, "USD"))
+}
@@ -0,0 +1,41 @@
+package util
+
+import (
+	"encoding/base64"
+	"errors"
+	"strconv"
+	"strings"
+	"time"
+)
+
+var ErrInvalidCursor = errors.New("invalid pagination cursor")
+
+// EncodeCursor returns an opaque cursor that points right after the row with the given creation time and id
+func EncodeCursor(createdAt time.Time, id int64) string {
+	raw := createdAt.UTC().Format(time.RFC3339Nano) + "|" + strconv.FormatInt(id, 10)
+	return base64.RawURLEncoding.EncodeToString([]byte(raw))
+}
+
+// DecodeCursor reverts EncodeCursor. An empty cursor points to the beginning of the list.
+func DecodeCursor(cursor string) (createdAt time.Time, id int64, err error) {
+	if cursor == "" {
+		return time.Time{}, 0, nil
+	}
+	raw, err := base64.RawURLEncoding.DecodeString(cursor)
+	if err != nil {
+		return time.Time{}, 0, ErrInvalidCursor
+	}
+	fields := strings.Split(string(raw), "|")
+	if len(fields) != 2 {
+		return time.Time{}, 0, ErrInvalidCursor
+	}
+	createdAt, err = time.Parse(time.RFC3339Nano, fields[0])
+	if err != nil {
+		return time.Time{}, 0, ErrInvalidCursor
+	}
+	id, err = strconv.ParseInt(fields[1], 10, 64)
+	if err != nil {
+		return time.Time{}, 0, ErrInvalidCursor
+	}
+	return createdAt, id, nil
+}
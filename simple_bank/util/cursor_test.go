@@ -0,0 +1,29 @@
+package util
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestCursor(t *testing.T) {
+	createdAt := time.Now().Truncate(time.Microsecond)
+	id := RandomInt(1, 1000)
+
+	cursor := EncodeCursor(createdAt, id)
+	require.NotEmpty(t, cursor)
+
+	decodedCreatedAt, decodedId, err := DecodeCursor(cursor)
+	require.NoError(t, err)
+	require.True(t, createdAt.Equal(decodedCreatedAt))
+	require.Equal(t, id, decodedId)
+
+	decodedCreatedAt, decodedId, err = DecodeCursor("")
+	require.NoError(t, err)
+	require.True(t, decodedCreatedAt.IsZero())
+	require.Zero(t, decodedId)
+
+	_, _, err = DecodeCursor("not a cursor")
+	require.ErrorIs(t, err, ErrInvalidCursor)
+}
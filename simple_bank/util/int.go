@@ -0,0 +1,10 @@
+package util
+
+import "database/sql"
+
+func Int64ToSqlNullInt64(value int64) sql.NullInt64 {
+	if value == 0 {
+		return sql.NullInt64{Int64: 0, Valid: false}
+	}
+	return sql.NullInt64{Int64: value, Valid: true}
+}
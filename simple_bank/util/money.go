@@ -0,0 +1,117 @@
+package util
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"strings"
+)
+
+var (
+	ErrMoneyOverflow         = errors.New("amount is out of range")
+	ErrMoneyCurrencyMismatch = errors.New("amounts are in different currencies")
+	ErrInvalidMoneyAmount    = errors.New("amount must be a decimal number")
+)
+
+// Money is an amount in minor units of its currency, e.g. {1234, "USD"} is 12.34 USD
+type Money struct {
+	Amount   int64  `json:"amount"`
+	Currency string `json:"currency"`
+}
+
+func NewMoney(amount int64, currency string) Money {
+	return Money{Amount: amount, Currency: currency}
+}
+
+// ParseMoney reads a decimal string like "12.34" or "-5" into minor units of the currency. It accepts at
+// most as many fraction digits as the minor unit of the currency, so no amount is ever rounded.
+func ParseMoney(value string, currency string) (Money, error) {
+	info, ok := GetCurrencyInfo(currency)
+	if !ok {
+		return Money{}, fmt.Errorf("currency %s is not supported", currency)
+	}
+	digits := value
+	negative := false
+	if strings.HasPrefix(digits, "-") || strings.HasPrefix(digits, "+") {
+		negative = digits[0] == '-'
+		digits = digits[1:]
+	}
+	whole, fraction, hasPoint := strings.Cut(digits, ".")
+	if whole == "" || (hasPoint && fraction == "") || !isDigits(whole) || !isDigits(fraction) {
+		return Money{}, fmt.Errorf("%w: %q", ErrInvalidMoneyAmount, value)
+	}
+	if len(fraction) > info.MinorUnit {
+		return Money{}, fmt.Errorf("%w: %s allows at most %d decimal places", ErrInvalidMoneyAmount, currency, info.MinorUnit)
+	}
+	fraction += strings.Repeat("0", info.MinorUnit-len(fraction))
+
+	// accumulate as a negative number so math.MinInt64 can be parsed as well
+	var amount int64
+	for _, digit := range whole + fraction {
+		d := int64(digit - '0')
+		if amount < (math.MinInt64+d)/10 {
+			return Money{}, fmt.Errorf("%w: %s", ErrMoneyOverflow, value)
+		}
+		amount = amount*10 - d
+	}
+	if !negative {
+		if amount == math.MinInt64 {
+			return Money{}, fmt.Errorf("%w: %s", ErrMoneyOverflow, value)
+		}
+		amount = -amount
+	}
+	return NewMoney(amount, currency), nil
+}
+
+func isDigits(value string) bool {
+	for _, c := range value {
+		if c < '0' || c > '9' {
+			return false
+		}
+	}
+	return true
+}
+
+// Add returns m + other, failing when the currencies differ or the sum does not fit in an int64
+func (m Money) Add(other Money) (Money, error) {
+	if m.Currency != other.Currency {
+		return Money{}, fmt.Errorf("%w: %s and %s", ErrMoneyCurrencyMismatch, m.Currency, other.Currency)
+	}
+	if (other.Amount > 0 && m.Amount > math.MaxInt64-other.Amount) ||
+		(other.Amount < 0 && m.Amount < math.MinInt64-other.Amount) {
+		return Money{}, ErrMoneyOverflow
+	}
+	return NewMoney(m.Amount+other.Amount, m.Currency), nil
+}
+
+// Sub returns m - other, failing when the currencies differ or the difference does not fit in an int64
+func (m Money) Sub(other Money) (Money, error) {
+	if m.Currency != other.Currency {
+		return Money{}, fmt.Errorf("%w: %s and %s", ErrMoneyCurrencyMismatch, m.Currency, other.Currency)
+	}
+	if (other.Amount < 0 && m.Amount > math.MaxInt64+other.Amount) ||
+		(other.Amount > 0 && m.Amount < math.MinInt64+other.Amount) {
+		return Money{}, ErrMoneyOverflow
+	}
+	return NewMoney(m.Amount-other.Amount, m.Currency), nil
+}
+
+// String renders the amount as a decimal using the minor unit of the currency, e.g. "12.34".
+// Unknown currencies are formatted without decimals.
+func (m Money) String() string {
+	info, _ := GetCurrencyInfo(m.Currency)
+	if info.MinorUnit <= 0 {
+		return fmt.Sprintf("%d", m.Amount)
+	}
+	sign := ""
+	magnitude := uint64(m.Amount)
+	if m.Amount < 0 {
+		sign = "-"
+		magnitude = uint64(-(m.Amount + 1)) + 1
+	}
+	scale := uint64(1)
+	for i := 0; i < info.MinorUnit; i++ {
+		scale *= 10
+	}
+	return fmt.Sprintf("%s%d.%0*d", sign, magnitude/scale, info.MinorUnit, magnitude%scale)
+}
@@ -0,0 +1,78 @@
+package util
+
+import (
+	"math"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseMoney(t *testing.T) {
+	testCases := []struct {
+		value    string
+		currency string
+		expected int64
+	}{
+		{value: "12.34", currency: "USD", expected: 1234},
+		{value: "12.3", currency: "USD", expected: 1230},
+		{value: "12", currency: "USD", expected: 1200},
+		{value: "-0.05", currency: "EUR", expected: -5},
+		{value: "+7", currency: "EUR", expected: 700},
+		{value: "1500", currency: "JPY", expected: 1500},
+		{value: "92233720368547758.07", currency: "USD", expected: math.MaxInt64},
+		{value: "-92233720368547758.08", currency: "USD", expected: math.MinInt64},
+	}
+	for _, tc := range testCases {
+		money, err := ParseMoney(tc.value, tc.currency)
+		require.NoError(t, err, tc.value)
+		require.Equal(t, NewMoney(tc.expected, tc.currency), money)
+	}
+
+	for _, value := range []string{"", "-", ".5", "5.", "1.2.3", "1,00", "abc", "1e3", " 1"} {
+		_, err := ParseMoney(value, "USD")
+		require.ErrorIs(t, err, ErrInvalidMoneyAmount, value)
+	}
+	_, err := ParseMoney("1.234", "USD")
+	require.ErrorIs(t, err, ErrInvalidMoneyAmount)
+	_, err = ParseMoney("1.5", "JPY")
+	require.ErrorIs(t, err, ErrInvalidMoneyAmount)
+	_, err = ParseMoney("92233720368547758.08", "USD")
+	require.ErrorIs(t, err, ErrMoneyOverflow)
+	_, err = ParseMoney("1", "XXX")
+	require.Error(t, err)
+}
+
+func TestMoneyString(t *testing.T) {
+	require.Equal(t, "12.34", NewMoney(1234, "USD").String())
+	require.Equal(t, "0.05", NewMoney(5, "EUR").String())
+	require.Equal(t, "-1.50", NewMoney(-150, "CAD").String())
+	require.Equal(t, "12345", NewMoney(12345, "JPY").String())
+	require.Equal(t, "-92233720368547758.08", NewMoney(math.MinInt64, "USD").String())
+}
+
+func TestMoneyAddSub(t *testing.T) {
+	a := NewMoney(1050, "USD")
+	b := NewMoney(250, "USD")
+
+	sum, err := a.Add(b)
+	require.NoError(t, err)
+	require.Equal(t, NewMoney(1300, "USD"), sum)
+
+	difference, err := b.Sub(a)
+	require.NoError(t, err)
+	require.Equal(t, NewMoney(-800, "USD"), difference)
+
+	_, err = a.Add(NewMoney(1, "EUR"))
+	require.ErrorIs(t, err, ErrMoneyCurrencyMismatch)
+	_, err = a.Sub(NewMoney(1, "EUR"))
+	require.ErrorIs(t, err, ErrMoneyCurrencyMismatch)
+
+	_, err = NewMoney(math.MaxInt64, "USD").Add(NewMoney(1, "USD"))
+	require.ErrorIs(t, err, ErrMoneyOverflow)
+	_, err = NewMoney(math.MinInt64, "USD").Add(NewMoney(-1, "USD"))
+	require.ErrorIs(t, err, ErrMoneyOverflow)
+	_, err = NewMoney(math.MinInt64, "USD").Sub(NewMoney(1, "USD"))
+	require.ErrorIs(t, err, ErrMoneyOverflow)
+	_, err = NewMoney(0, "USD").Sub(NewMoney(math.MinInt64, "USD"))
+	require.ErrorIs(t, err, ErrMoneyOverflow)
+}
@@ -0,0 +1,20 @@
+package util
+
+// roles of the users, stored in the user table and carried by the access tokens
+const (
+	// owns accounts and moves money between them
+	DepositorRole = "depositor"
+	// bank staff, can view any account and freeze accounts
+	BankerRole = "banker"
+	// manages users, can do everything a banker can
+	AdminRole = "admin"
+)
+
+// IsSupportedRole reports whether role is one of the user roles
+func IsSupportedRole(role string) bool {
+	switch role {
+	case DepositorRole, BankerRole, AdminRole:
+		return true
+	}
+	return false
+}
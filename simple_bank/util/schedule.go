@@ -0,0 +1,152 @@
+package util
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+var ErrInvalidSchedule = errors.New("invalid schedule")
+
+// scheduleHorizon bounds the search for the next run, long enough to reach the next 29th of February
+const scheduleHorizon = 8 * 366 * 24 * time.Hour
+
+var scheduleDescriptors = map[string]string{
+	"@hourly":  "0 * * * *",
+	"@daily":   "0 0 * * *",
+	"@weekly":  "0 0 * * 0",
+	"@monthly": "0 0 1 * *",
+	"@yearly":  "0 0 1 1 *",
+}
+
+// Schedule is a cron expression with the five standard fields: minute, hour, day of month, month and
+// day of week. Fields accept *, numbers, ranges, lists and steps like "*/15" or "1-5". When both day
+// fields are restricted a day matches either of them, like in cron. Schedules are evaluated in UTC.
+type Schedule struct {
+	minute     uint64
+	hour       uint64
+	dayOfMonth uint64
+	month      uint64
+	dayOfWeek  uint64
+	// whether the day fields are restricted, see matchesDay
+	anyDayOfMonth bool
+	anyDayOfWeek  bool
+}
+
+// ParseSchedule parses a cron expression or one of the descriptors @hourly, @daily, @weekly, @monthly and @yearly
+func ParseSchedule(expr string) (Schedule, error) {
+	var schedule Schedule
+	if descriptor, ok := scheduleDescriptors[expr]; ok {
+		expr = descriptor
+	}
+	fields := strings.Fields(expr)
+	if len(fields) != 5 {
+		return schedule, fmt.Errorf("%w: expected 5 fields, got %d", ErrInvalidSchedule, len(fields))
+	}
+	var err error
+	if schedule.minute, err = parseScheduleField(fields[0], 0, 59); err != nil {
+		return schedule, err
+	}
+	if schedule.hour, err = parseScheduleField(fields[1], 0, 23); err != nil {
+		return schedule, err
+	}
+	if schedule.dayOfMonth, err = parseScheduleField(fields[2], 1, 31); err != nil {
+		return schedule, err
+	}
+	if schedule.month, err = parseScheduleField(fields[3], 1, 12); err != nil {
+		return schedule, err
+	}
+	// both 0 and 7 are sunday
+	if schedule.dayOfWeek, err = parseScheduleField(fields[4], 0, 7); err != nil {
+		return schedule, err
+	}
+	if schedule.dayOfWeek&(1<<7) != 0 {
+		schedule.dayOfWeek |= 1
+	}
+	schedule.anyDayOfMonth = fields[2] == "*"
+	schedule.anyDayOfWeek = fields[4] == "*"
+	if schedule.Next(time.Date(2000, 1, 1, 0, 0, 0, 0, time.UTC)).IsZero() {
+		return schedule, fmt.Errorf("%w: %q never runs", ErrInvalidSchedule, expr)
+	}
+	return schedule, nil
+}
+
+// parseScheduleField returns a bit set of the values matched by a comma separated list of items
+func parseScheduleField(field string, min int, max int) (uint64, error) {
+	var bits uint64
+	for _, item := range strings.Split(field, ",") {
+		rangePart, stepPart, hasStep := strings.Cut(item, "/")
+		step := 1
+		if hasStep {
+			var err error
+			step, err = strconv.Atoi(stepPart)
+			if err != nil || step <= 0 {
+				return 0, fmt.Errorf("%w: invalid step in %q", ErrInvalidSchedule, item)
+			}
+		}
+		low, high := min, max
+		if rangePart != "*" {
+			lowPart, highPart, isRange := strings.Cut(rangePart, "-")
+			var err error
+			low, err = strconv.Atoi(lowPart)
+			if err != nil {
+				return 0, fmt.Errorf("%w: invalid value in %q", ErrInvalidSchedule, item)
+			}
+			high = low
+			if isRange {
+				high, err = strconv.Atoi(highPart)
+				if err != nil {
+					return 0, fmt.Errorf("%w: invalid value in %q", ErrInvalidSchedule, item)
+				}
+			} else if hasStep {
+				// "5/15" means from 5 to the end of the range every 15
+				high = max
+			}
+		}
+		if low < min || high > max || low > high {
+			return 0, fmt.Errorf("%w: %q is out of range %d-%d", ErrInvalidSchedule, item, min, max)
+		}
+		for value := low; value <= high; value += step {
+			bits |= 1 << uint(value)
+		}
+	}
+	return bits, nil
+}
+
+// Next returns the first time matching the schedule strictly after the given time, truncated to the
+// minute. It returns the zero time when the schedule never matches, like the 30th of February.
+func (schedule Schedule) Next(after time.Time) time.Time {
+	t := after.UTC().Truncate(time.Minute).Add(time.Minute)
+	limit := t.Add(scheduleHorizon)
+	for t.Before(limit) {
+		if schedule.month&(1<<uint(t.Month())) == 0 {
+			t = time.Date(t.Year(), t.Month()+1, 1, 0, 0, 0, 0, time.UTC)
+			continue
+		}
+		if !schedule.matchesDay(t) {
+			t = time.Date(t.Year(), t.Month(), t.Day()+1, 0, 0, 0, 0, time.UTC)
+			continue
+		}
+		if schedule.hour&(1<<uint(t.Hour())) == 0 {
+			t = t.Truncate(time.Hour).Add(time.Hour)
+			continue
+		}
+		if schedule.minute&(1<<uint(t.Minute())) == 0 {
+			t = t.Add(time.Minute)
+			continue
+		}
+		return t
+	}
+	return time.Time{}
+}
+
+func (schedule Schedule) matchesDay(t time.Time) bool {
+	dayOfMonth := schedule.dayOfMonth&(1<<uint(t.Day())) != 0
+	dayOfWeek := schedule.dayOfWeek&(1<<uint(t.Weekday())) != 0
+	if schedule.anyDayOfMonth || schedule.anyDayOfWeek {
+		return dayOfMonth && dayOfWeek
+	}
+	return dayOfMonth || dayOfWeek
+}
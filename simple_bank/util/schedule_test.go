@@ -0,0 +1,43 @@
+package util
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestScheduleNext(t *testing.T) {
+	// a wednesday
+	now := time.Date(2024, 1, 31, 10, 30, 45, 0, time.UTC)
+	testCases := []struct {
+		expr     string
+		expected time.Time
+	}{
+		{expr: "* * * * *", expected: time.Date(2024, 1, 31, 10, 31, 0, 0, time.UTC)},
+		{expr: "*/15 * * * *", expected: time.Date(2024, 1, 31, 10, 45, 0, 0, time.UTC)},
+		{expr: "0 9 * * *", expected: time.Date(2024, 2, 1, 9, 0, 0, 0, time.UTC)},
+		{expr: "@daily", expected: time.Date(2024, 2, 1, 0, 0, 0, 0, time.UTC)},
+		{expr: "0 0 1 * *", expected: time.Date(2024, 2, 1, 0, 0, 0, 0, time.UTC)},
+		{expr: "0 8 * * 1-5", expected: time.Date(2024, 2, 1, 8, 0, 0, 0, time.UTC)},
+		{expr: "0 8 * * 7", expected: time.Date(2024, 2, 4, 8, 0, 0, 0, time.UTC)},
+		{expr: "0 12 29 2 *", expected: time.Date(2024, 2, 29, 12, 0, 0, 0, time.UTC)},
+		{expr: "0 0 31 * *", expected: time.Date(2024, 3, 31, 0, 0, 0, 0, time.UTC)},
+		{expr: "30 10,22 * * *", expected: time.Date(2024, 1, 31, 22, 30, 0, 0, time.UTC)},
+		// restricted day of month and day of week match either one
+		{expr: "0 0 15 * 5", expected: time.Date(2024, 2, 2, 0, 0, 0, 0, time.UTC)},
+	}
+	for _, tc := range testCases {
+		schedule, err := ParseSchedule(tc.expr)
+		require.NoError(t, err, tc.expr)
+		require.Equal(t, tc.expected, schedule.Next(now), tc.expr)
+	}
+}
+
+func TestParseScheduleInvalid(t *testing.T) {
+	for _, expr := range []string{"", "* * * *", "* * * * * *", "60 * * * *", "* 24 * * *", "* * 0 * *",
+		"* * * 13 *", "* * * * 8", "5-1 * * * *", "*/0 * * * *", "a * * * *", "@never", "0 0 30 2 *"} {
+		_, err := ParseSchedule(expr)
+		require.ErrorIs(t, err, ErrInvalidSchedule, expr)
+	}
+}
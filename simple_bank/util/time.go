@@ -0,0 +1,13 @@
+package util
+
+import (
+	"database/sql"
+	"time"
+)
+
+func TimeToSqlNullTime(t time.Time) sql.NullTime {
+	if t.IsZero() {
+		return sql.NullTime{Valid: false}
+	}
+	return sql.NullTime{Time: t, Valid: true}
+}
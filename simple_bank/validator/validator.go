@@ -4,11 +4,13 @@ import (
 	"fmt"
 	"net/mail"
 	"regexp"
+	"simple_bank/util"
 )
 
 var (
 	isValidUsername = regexp.MustCompile(`^[a-zA-Z0-9_]+$`).MatchString
 	isValidName = regexp.MustCompile(`^[a-zA-Z\\s]+$`).MatchString
+	isValidCurrencyCode = regexp.MustCompile(`^[A-Z]{3}$`).MatchString
 )
 
 func ValidateStringLenght(value string, min int, max int) error {
@@ -54,4 +56,147 @@ func ValidateEmail(email string) error {
 		return fmt.Errorf("email provided is not valid %v", err)
 	}
 	return nil
-}
\ No newline at end of file
+}
+
+func ValidateAccountId(id int64) error {
+	if id < 1 {
+		return fmt.Errorf("account id must be a positive number")
+	}
+	return nil
+}
+
+func ValidateEmailId(id int64) error {
+	if id < 1 {
+		return fmt.Errorf("email id must be a positive number")
+	}
+	return nil
+}
+
+func ValidateSecretCode(code string) error {
+	return ValidateStringLenght(code, 32, 128)
+}
+
+func ValidateTransferId(id int64) error {
+	if id < 1 {
+		return fmt.Errorf("transfer id must be a positive number")
+	}
+	return nil
+}
+
+func ValidateScheduledTransferId(id int64) error {
+	if id < 1 {
+		return fmt.Errorf("scheduled transfer id must be a positive number")
+	}
+	return nil
+}
+
+// ValidateTransferLimit checks a limit that is set, limits that are not set do not apply
+func ValidateTransferLimit(limit int64) error {
+	if limit < 1 {
+		return fmt.Errorf("limit must be a positive number")
+	}
+	return nil
+}
+
+func ValidateIntRange(value int64, min int64, max int64) error {
+	if value < min || value > max {
+		return fmt.Errorf("must be between %d and %d", min, max)
+	}
+	return nil
+}
+
+func ValidateCurrency(currency string) error {
+	if !util.IsSupportedCurrency(currency) {
+		return fmt.Errorf("currency %s is not supported", currency)
+	}
+	return nil
+}
+
+// ValidateCurrencyCode checks the format of an ISO 4217 code, unlike ValidateCurrency the currency does not have to be known
+func ValidateCurrencyCode(code string) error {
+	if !isValidCurrencyCode(code) {
+		return fmt.Errorf("currency code must be 3 uppercase letters")
+	}
+	return nil
+}
+
+func ValidateMinorUnit(minorUnit int32) error {
+	if err := ValidateIntRange(int64(minorUnit), 0, 4); err != nil {
+		return fmt.Errorf("minor unit %v", err)
+	}
+	return nil
+}
+
+func ValidateCurrencySymbol(symbol string) error {
+	if err := ValidateStringLenght(symbol, 1, 10); err != nil {
+		return fmt.Errorf("symbol %v", err)
+	}
+	return nil
+}
+
+func ValidateTransferDirection(direction string) error {
+	switch direction {
+	case "", "in", "out":
+		return nil
+	}
+	return fmt.Errorf("direction must be either in or out")
+}
+
+// ValidateIdempotencyKey accepts an empty key, which means the request is not idempotent
+func ValidateIdempotencyKey(key string) error {
+	if key == "" {
+		return nil
+	}
+	if err := ValidateStringLenght(key, 1, 255); err != nil {
+		return fmt.Errorf("idempotency key %v", err)
+	}
+	return nil
+}
+
+func ValidateEntryReason(reason string) error {
+	if err := ValidateStringLenght(reason, 0, 255); err != nil {
+		return fmt.Errorf("reason %v", err)
+	}
+	return nil
+}
+
+func ValidateExternalReference(reference string) error {
+	if err := ValidateStringLenght(reference, 0, 255); err != nil {
+		return fmt.Errorf("external reference %v", err)
+	}
+	return nil
+}
+
+// ValidateSchedule accepts an empty schedule, which means the transfer runs once
+func ValidateSchedule(schedule string) error {
+	if schedule == "" {
+		return nil
+	}
+	_, err := util.ParseSchedule(schedule)
+	return err
+}
+
+// ValidateScheduledTransferStatus only accepts the statuses a user can set, the others are set by the scheduler
+func ValidateScheduledTransferStatus(status string) error {
+	switch status {
+	case "active", "paused":
+		return nil
+	}
+	return fmt.Errorf("status must be either active or paused")
+}
+
+func ValidateRole(role string) error {
+	if !util.IsSupportedRole(role) {
+		return fmt.Errorf("role %s is not supported", role)
+	}
+	return nil
+}
+
+// ValidateAccountStatus only accepts the statuses an owner can set, accounts are closed by deleting them
+func ValidateAccountStatus(status string) error {
+	switch status {
+	case "active", "frozen":
+		return nil
+	}
+	return fmt.Errorf("status must be either active or frozen")
+}
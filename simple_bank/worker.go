@@ -0,0 +1,43 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"log"
+	db "simple_bank/db/sqlc"
+	"simple_bank/mail"
+	"simple_bank/util"
+	"simple_bank/worker"
+	"time"
+
+	"golang.org/x/sync/errgroup"
+)
+
+// runTaskProcessor runs the due background tasks every TaskInterval until ctx is done. Every replica can
+// run it, each due task is claimed by a single one. A failed pass is logged and does not stop the servers.
+func runTaskProcessor(ctx context.Context, waitGroup *errgroup.Group, config util.Config, store db.Store, mailer mail.Mailer) error {
+	if config.TaskInterval <= 0 {
+		// the tasks are still enqueued, another replica has to run them or verification emails are never sent
+		log.Print("WARNING: TASK_INTERVAL is not positive, background tasks like verification emails are not run by this server")
+		return nil
+	}
+	processor, err := worker.NewPGTaskProcessor(config, store, mailer)
+	if err != nil {
+		return fmt.Errorf("cannot create the task processor: %w", err)
+	}
+	waitGroup.Go(func() error {
+		ticker := time.NewTicker(config.TaskInterval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ctx.Done():
+				return nil
+			case <-ticker.C:
+				if _, err := processor.ProcessDue(ctx); err != nil {
+					log.Print("background tasks failed: ", err)
+				}
+			}
+		}
+	})
+	return nil
+}
@@ -0,0 +1,44 @@
+package worker
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	db "simple_bank/db/sqlc"
+)
+
+// TaskDistributor enqueues the tasks run in the background by a TaskProcessor. Tasks are written
+// through q, so a task enqueued with the queries of a transaction only exists once it commits.
+type TaskDistributor interface {
+	DistributeTaskSendVerifyEmail(ctx context.Context, q db.Querier, payload *PayloadSendVerifyEmail) error
+}
+
+// PGTaskDistributor enqueues the tasks in the task table of the database
+type PGTaskDistributor struct {
+	maxAttempts int32
+}
+
+func NewPGTaskDistributor(maxAttempts int32) TaskDistributor {
+	if maxAttempts < 1 {
+		maxAttempts = 1
+	}
+	return &PGTaskDistributor{
+		maxAttempts: maxAttempts,
+	}
+}
+
+func (distributor *PGTaskDistributor) distribute(ctx context.Context, q db.Querier, taskType string, payload any) error {
+	data, err := json.Marshal(payload)
+	if err != nil {
+		return fmt.Errorf("cannot marshal payload of task %s: %w", taskType, err)
+	}
+	_, err = q.CreateTask(ctx, db.CreateTaskParams{
+		Type:        taskType,
+		Payload:     data,
+		MaxAttempts: distributor.maxAttempts,
+	})
+	if err != nil {
+		return fmt.Errorf("cannot enqueue task %s: %w", taskType, err)
+	}
+	return nil
+}
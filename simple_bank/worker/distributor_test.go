@@ -0,0 +1,38 @@
+package worker
+
+import (
+	"context"
+	"database/sql"
+	mockdb "simple_bank/db/mock"
+	db "simple_bank/db/sqlc"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+)
+
+func TestDistributeTaskSendVerifyEmail(t *testing.T) {
+	controller := gomock.NewController(t)
+	store := mockdb.NewMockStore(controller)
+	store.EXPECT().CreateTask(gomock.Any(), gomock.Any()).Times(1).
+		DoAndReturn(func(ctx context.Context, arg db.CreateTaskParams) (db.Task, error) {
+			require.Equal(t, TaskSendVerifyEmail, arg.Type)
+			require.JSONEq(t, `{"emailId": 7}`, string(arg.Payload))
+			require.Equal(t, int32(5), arg.MaxAttempts)
+			return db.Task{ID: 1}, nil
+		})
+
+	distributor := NewPGTaskDistributor(5)
+	err := distributor.DistributeTaskSendVerifyEmail(context.Background(), store, &PayloadSendVerifyEmail{EmailId: 7})
+	require.NoError(t, err)
+}
+
+func TestDistributeTaskError(t *testing.T) {
+	controller := gomock.NewController(t)
+	store := mockdb.NewMockStore(controller)
+	store.EXPECT().CreateTask(gomock.Any(), gomock.Any()).Times(1).Return(db.Task{}, sql.ErrConnDone)
+
+	distributor := NewPGTaskDistributor(5)
+	err := distributor.DistributeTaskSendVerifyEmail(context.Background(), store, &PayloadSendVerifyEmail{EmailId: 7})
+	require.ErrorIs(t, err, sql.ErrConnDone)
+}
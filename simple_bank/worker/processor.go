@@ -0,0 +1,133 @@
+package worker
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"log"
+	db "simple_bank/db/sqlc"
+	"simple_bank/mail"
+	"simple_bank/util"
+	"time"
+
+	"golang.org/x/sync/errgroup"
+)
+
+const (
+	TaskStatusPending = "pending"
+	TaskStatusDone    = "done"
+	TaskStatusDead    = "dead"
+)
+
+// claimLease is how long a claimed task stays hidden from other processors. When a processor dies
+// mid-task the task is run again after the lease, so handlers have to cope with running twice.
+const claimLease = 5 * time.Minute
+
+// ErrSkipRetry is wrapped by handlers whose task cannot succeed on a later attempt, the task goes
+// straight to the dead state
+var ErrSkipRetry = errors.New("task cannot succeed")
+
+// TaskProcessor runs the tasks enqueued by a TaskDistributor
+type TaskProcessor interface {
+	ProcessDue(ctx context.Context) (int, error)
+	ProcessTaskSendVerifyEmail(ctx context.Context, task db.Task) error
+}
+
+// PGTaskProcessor runs the due tasks of the task table. Several processors can share a database: due
+// tasks are claimed with FOR UPDATE SKIP LOCKED so each one is run by a single processor.
+type PGTaskProcessor struct {
+	config   util.Config
+	store    db.Store
+	mailer   mail.Mailer
+	handlers map[string]func(ctx context.Context, task db.Task) error
+	now      func() time.Time
+}
+
+// NewPGTaskProcessor fails when TaskBatchSize is not positive, such a processor would never claim a task
+func NewPGTaskProcessor(config util.Config, store db.Store, mailer mail.Mailer) (*PGTaskProcessor, error) {
+	if config.TaskBatchSize < 1 {
+		return nil, fmt.Errorf("task batch size must be at least 1, got %d", config.TaskBatchSize)
+	}
+	processor := &PGTaskProcessor{
+		config: config,
+		store:  store,
+		mailer: mailer,
+		now:    time.Now,
+	}
+	processor.handlers = map[string]func(ctx context.Context, task db.Task) error{
+		TaskSendVerifyEmail: processor.ProcessTaskSendVerifyEmail,
+	}
+	return processor, nil
+}
+
+// ProcessDue claims the tasks that are due and runs up to TaskConcurrency of them at the same time. It
+// returns how many tasks were run; a failed task is recorded and retried later, it is not returned as an error.
+func (processor *PGTaskProcessor) ProcessDue(ctx context.Context) (int, error) {
+	now := processor.now().UTC()
+	due, err := processor.store.ClaimDueTasks(ctx, db.ClaimDueTasksParams{
+		LockedUntil: now.Add(claimLease),
+		Now:         now,
+		RowLimit:    processor.config.TaskBatchSize,
+	})
+	if err != nil {
+		return 0, fmt.Errorf("cannot claim tasks: %w", err)
+	}
+	var group errgroup.Group
+	group.SetLimit(max(processor.config.TaskConcurrency, 1))
+	for _, task := range due {
+		task := task
+		group.Go(func() error {
+			return processor.process(ctx, task)
+		})
+	}
+	return len(due), group.Wait()
+}
+
+func (processor *PGTaskProcessor) process(ctx context.Context, task db.Task) error {
+	err := processor.run(ctx, task)
+	now := processor.now().UTC()
+	arg := db.FinishTaskParams{
+		ID:       task.ID,
+		Status:   TaskStatusDone,
+		Attempts: task.Attempts,
+		RunAt:    task.RunAt,
+	}
+	if err != nil {
+		arg.Attempts++
+		arg.LastError = err.Error()
+		if arg.Attempts < task.MaxAttempts && !errors.Is(err, ErrSkipRetry) {
+			arg.Status = TaskStatusPending
+			arg.RunAt = now.Add(processor.backoff(arg.Attempts))
+		} else {
+			log.Printf("task [%d] %s is dead after %d attempt(s): %v", task.ID, task.Type, arg.Attempts, err)
+			arg.Status = TaskStatusDead
+		}
+	}
+	if arg.Status != TaskStatusPending {
+		arg.FinishedAt = sql.NullTime{Time: now, Valid: true}
+	}
+	_, err = processor.store.FinishTask(ctx, arg)
+	if err != nil {
+		return fmt.Errorf("cannot record outcome of task [%d]: %w", task.ID, err)
+	}
+	return nil
+}
+
+func (processor *PGTaskProcessor) run(ctx context.Context, task db.Task) error {
+	handler, ok := processor.handlers[task.Type]
+	if !ok {
+		return fmt.Errorf("%w: unknown task type %s", ErrSkipRetry, task.Type)
+	}
+	return handler(ctx, task)
+}
+
+// backoff returns how long to wait after the given failed attempt, TaskRetryDelay doubled for every
+// attempt after the first
+func (processor *PGTaskProcessor) backoff(attempt int32) time.Duration {
+	delay := processor.config.TaskRetryDelay
+	for i := int32(1); i < attempt; i++ {
+		delay *= 2
+	}
+	return delay
+}
@@ -0,0 +1,156 @@
+package worker
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	mockdb "simple_bank/db/mock"
+	db "simple_bank/db/sqlc"
+	"simple_bank/mail"
+	"simple_bank/util"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+)
+
+var testConfig = util.Config{
+	TaskBatchSize:   10,
+	TaskConcurrency: 2,
+	TaskRetryDelay:  time.Minute,
+	VerifyEmailUrl:  "http://localhost:8080/verify_email",
+}
+
+func newTestProcessor(t *testing.T, store db.Store, mailer mail.Mailer, now time.Time) *PGTaskProcessor {
+	processor, err := NewPGTaskProcessor(testConfig, store, mailer)
+	require.NoError(t, err)
+	processor.now = func() time.Time { return now }
+	return processor
+}
+
+func TestNewPGTaskProcessorInvalidBatchSize(t *testing.T) {
+	config := testConfig
+	config.TaskBatchSize = 0
+	processor, err := NewPGTaskProcessor(config, nil, nil)
+	require.Error(t, err)
+	require.Nil(t, processor)
+}
+
+func newSendVerifyEmailTask(t *testing.T, attempts int32) db.Task {
+	payload, err := json.Marshal(PayloadSendVerifyEmail{EmailId: 7})
+	require.NoError(t, err)
+	return db.Task{
+		ID:          1,
+		Type:        TaskSendVerifyEmail,
+		Payload:     payload,
+		Status:      TaskStatusPending,
+		Attempts:    attempts,
+		MaxAttempts: 3,
+		RunAt:       time.Date(2024, 3, 1, 9, 0, 0, 0, time.UTC),
+	}
+}
+
+func TestProcessDueSendVerifyEmail(t *testing.T) {
+	now := time.Date(2024, 3, 1, 9, 0, 30, 0, time.UTC)
+	task := newSendVerifyEmailTask(t, 0)
+	verifyEmail := db.VerifyEmail{ID: 7, Username: "john", Email: "john@email.com", SecretCode: util.RandomString(32),
+		ExpiredAt: now.Add(15 * time.Minute)}
+	user := db.User{Username: "john", Name1: "John", Email: "john@email.com"}
+
+	controller := gomock.NewController(t)
+	store := mockdb.NewMockStore(controller)
+	claim := db.ClaimDueTasksParams{LockedUntil: now.Add(claimLease), Now: now, RowLimit: 10}
+	store.EXPECT().ClaimDueTasks(gomock.Any(), gomock.Eq(claim)).Times(1).Return([]db.Task{task}, nil)
+	store.EXPECT().GetVerifyEmail(gomock.Any(), gomock.Eq(int64(7))).Times(1).Return(verifyEmail, nil)
+	store.EXPECT().GetUser(gomock.Any(), gomock.Eq("john")).Times(1).Return(user, nil)
+	arg := db.FinishTaskParams{
+		ID:         1,
+		Status:     TaskStatusDone,
+		RunAt:      task.RunAt,
+		FinishedAt: sql.NullTime{Time: now, Valid: true},
+	}
+	store.EXPECT().FinishTask(gomock.Any(), gomock.Eq(arg)).Times(1)
+
+	mailer := mail.NewInMemoryMailer()
+	n, err := newTestProcessor(t, store, mailer, now).ProcessDue(context.Background())
+	require.NoError(t, err)
+	require.Equal(t, 1, n)
+	emails := mailer.Emails()
+	require.Len(t, emails, 1)
+	require.Equal(t, []string{verifyEmail.Email}, emails[0].To)
+	require.Contains(t, emails[0].Content, verifyEmail.SecretCode)
+}
+
+func TestProcessDueUsedVerifyEmail(t *testing.T) {
+	now := time.Date(2024, 3, 1, 9, 0, 30, 0, time.UTC)
+	task := newSendVerifyEmailTask(t, 0)
+	verifyEmail := db.VerifyEmail{ID: 7, Username: "john", IsUsed: true, ExpiredAt: now.Add(15 * time.Minute)}
+
+	controller := gomock.NewController(t)
+	store := mockdb.NewMockStore(controller)
+	store.EXPECT().ClaimDueTasks(gomock.Any(), gomock.Any()).Times(1).Return([]db.Task{task}, nil)
+	store.EXPECT().GetVerifyEmail(gomock.Any(), gomock.Any()).Times(1).Return(verifyEmail, nil)
+	store.EXPECT().GetUser(gomock.Any(), gomock.Any()).Times(0)
+	store.EXPECT().FinishTask(gomock.Any(), gomock.Any()).Times(1).
+		DoAndReturn(func(ctx context.Context, arg db.FinishTaskParams) (db.Task, error) {
+			require.Equal(t, TaskStatusDone, arg.Status)
+			return db.Task{}, nil
+		})
+
+	mailer := mail.NewInMemoryMailer()
+	_, err := newTestProcessor(t, store, mailer, now).ProcessDue(context.Background())
+	require.NoError(t, err)
+	require.Empty(t, mailer.Emails())
+}
+
+func TestProcessDueRetry(t *testing.T) {
+	now := time.Date(2024, 3, 1, 9, 0, 30, 0, time.UTC)
+	task := newSendVerifyEmailTask(t, 1)
+
+	controller := gomock.NewController(t)
+	store := mockdb.NewMockStore(controller)
+	store.EXPECT().ClaimDueTasks(gomock.Any(), gomock.Any()).Times(1).Return([]db.Task{task}, nil)
+	store.EXPECT().GetVerifyEmail(gomock.Any(), gomock.Any()).Times(1).Return(db.VerifyEmail{}, sql.ErrConnDone)
+	store.EXPECT().FinishTask(gomock.Any(), gomock.Any()).Times(1).
+		DoAndReturn(func(ctx context.Context, arg db.FinishTaskParams) (db.Task, error) {
+			require.Equal(t, TaskStatusPending, arg.Status)
+			require.Equal(t, int32(2), arg.Attempts)
+			// the second failure waits twice the retry delay
+			require.Equal(t, now.Add(2*time.Minute), arg.RunAt)
+			require.Contains(t, arg.LastError, sql.ErrConnDone.Error())
+			require.False(t, arg.FinishedAt.Valid)
+			return db.Task{}, nil
+		})
+
+	_, err := newTestProcessor(t, store, mail.NewInMemoryMailer(), now).ProcessDue(context.Background())
+	require.NoError(t, err)
+}
+
+func TestProcessDueDead(t *testing.T) {
+	now := time.Date(2024, 3, 1, 9, 0, 30, 0, time.UTC)
+	exhausted := newSendVerifyEmailTask(t, 2)
+	unknown := db.Task{ID: 2, Type: "task:unknown", Payload: []byte(`{}`), MaxAttempts: 3, RunAt: exhausted.RunAt}
+
+	controller := gomock.NewController(t)
+	store := mockdb.NewMockStore(controller)
+	store.EXPECT().ClaimDueTasks(gomock.Any(), gomock.Any()).Times(1).Return([]db.Task{exhausted, unknown}, nil)
+	store.EXPECT().GetVerifyEmail(gomock.Any(), gomock.Any()).Times(1).Return(db.VerifyEmail{}, sql.ErrConnDone)
+	attempts := map[int64]int32{
+		exhausted.ID: 3,
+		// a task without a handler is not retried
+		unknown.ID: 1,
+	}
+	store.EXPECT().FinishTask(gomock.Any(), gomock.Any()).Times(2).
+		DoAndReturn(func(ctx context.Context, arg db.FinishTaskParams) (db.Task, error) {
+			require.Equal(t, TaskStatusDead, arg.Status)
+			require.Equal(t, attempts[arg.ID], arg.Attempts)
+			require.NotEmpty(t, arg.LastError)
+			require.Equal(t, sql.NullTime{Time: now, Valid: true}, arg.FinishedAt)
+			return db.Task{}, nil
+		})
+
+	n, err := newTestProcessor(t, store, mail.NewInMemoryMailer(), now).ProcessDue(context.Background())
+	require.NoError(t, err)
+	require.Equal(t, 2, n)
+}
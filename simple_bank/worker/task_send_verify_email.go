@@ -0,0 +1,46 @@
+package worker
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	db "simple_bank/db/sqlc"
+	"simple_bank/mail"
+)
+
+const TaskSendVerifyEmail = "task:send_verify_email"
+
+type PayloadSendVerifyEmail struct {
+	EmailId int64 `json:"emailId"`
+}
+
+func (distributor *PGTaskDistributor) DistributeTaskSendVerifyEmail(ctx context.Context, q db.Querier, payload *PayloadSendVerifyEmail) error {
+	return distributor.distribute(ctx, q, TaskSendVerifyEmail, payload)
+}
+
+// ProcessTaskSendVerifyEmail sends the email with the verification link. Nothing is sent once the
+// code is used or expired, the user has no use for the link anymore.
+func (processor *PGTaskProcessor) ProcessTaskSendVerifyEmail(ctx context.Context, task db.Task) error {
+	var payload PayloadSendVerifyEmail
+	if err := json.Unmarshal(task.Payload, &payload); err != nil {
+		return fmt.Errorf("%w: cannot unmarshal payload: %v", ErrSkipRetry, err)
+	}
+	verifyEmail, err := processor.store.GetVerifyEmail(ctx, payload.EmailId)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return fmt.Errorf("%w: verify email [%d] does not exist", ErrSkipRetry, payload.EmailId)
+		}
+		return fmt.Errorf("cannot get verify email: %w", err)
+	}
+	if verifyEmail.IsUsed || !verifyEmail.ExpiredAt.After(processor.now()) {
+		return nil
+	}
+	user, err := processor.store.GetUser(ctx, verifyEmail.Username)
+	if err != nil {
+		return fmt.Errorf("cannot get user: %w", err)
+	}
+	email := mail.NewVerifyEmail(processor.config.VerifyEmailUrl, user, verifyEmail)
+	return processor.mailer.SendEmail(ctx, email)
+}